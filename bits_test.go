@@ -0,0 +1,93 @@
+package gologix
+
+import (
+	"testing"
+)
+
+func TestBits(t *testing.T) {
+	var tests = []struct {
+		from, to int
+		want     uint32
+	}{
+		{4, 6, 5},
+		{0, 3, 0x0F},
+		{0, 31, 0xC000_005F},
+		{30, 31, 3},
+		{7, 7, 0},
+	}
+	for _, tt := range tests {
+		client, remote := newPipeClient(t)
+		go func() {
+			hdr, _, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			writeTestResponse(t, remote, hdr.Context, dintReadReply(t, -0x3FFF_FFA1)) // 0xC000005F
+		}()
+		have, err := client.Bits("Status", tt.from, tt.to)
+		if err != nil {
+			t.Errorf("bits %d to %d: %v", tt.from, tt.to, err)
+			continue
+		}
+		if have != tt.want {
+			t.Errorf("bits %d to %d: wanted %#x got %#x", tt.from, tt.to, tt.want, have)
+		}
+	}
+
+	client, remote := newPipeClient(t)
+	go func() {
+		hdr, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, dintReadReply(t, 0))
+	}()
+	if _, err := client.Bits("Status", 30, 32); err == nil {
+		t.Errorf("bit 32 is past the end of a DINT")
+	}
+}
+
+func TestSetBits(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.KnownTags["flags"] = KnownTag{Info: TagInfo{Type: CIPTypeINT}, Instance: 7}
+
+	requests := make(chan []byte, 1)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		requests <- buf.Bytes()[20:]
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgWriteResultHeader{Service: cipService_ReadModWrite.AsResponse()})
+		b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	err := client.SetBits("Flags", 4, 6, 5)
+	if err != nil {
+		t.Fatalf("problem setting bits: %v", err)
+	}
+	req := <-requests
+	want := []byte{
+		0x02, 0x00, // mask size
+		0x50, 0x00, // OR mask sets bits 4 and 6
+		0xDF, 0xFF, // AND mask clears bit 5 and keeps the rest
+	}
+	if !check_bytes(req[8:], want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(req[8:]))
+	}
+
+	if err := client.SetBits("Flags", 4, 6, 8); err == nil {
+		t.Errorf("8 doesn't fit in 3 bits")
+	}
+	if err := client.SetBits("Flags", 12, 16, 1); err == nil {
+		t.Errorf("bit 16 is past the end of an INT")
+	}
+	if err := client.SetBits("Flags", 6, 4, 1); err == nil {
+		t.Errorf("from has to come before to")
+	}
+}
@@ -0,0 +1,223 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// set or clear one bit of an integer tag (ex: "Status.3") with the read modify write service.
+//
+// The controller applies the OR and AND masks to the word itself so other bits that change in the same word between
+// our request and its reply aren't lost the way they would be if we read the word, changed it, and wrote it back.
+// The masks have to be the size of the word.  That comes from KnownTags if the word is in there (see ListAllTags)
+// and otherwise from reading the word once, the same as ReadModifyWrite.
+func (client *Client) writeBit(tag string, ioi *tagIOI, value bool, timeout time.Duration) error {
+	size, err := client.bitWordSize(tag)
+	if err != nil {
+		return err
+	}
+	if ioi.BitPosition >= size*8 {
+		return fmt.Errorf("bit %d out of range for %s. the word is only %d bits", ioi.BitPosition, tag, size*8)
+	}
+	or_mask, and_mask := bitMasks(ioi.BitPosition, size, value)
+	return client.readModifyWrite(tag, ioi, or_mask, and_mask, timeout)
+}
+
+// Set and clear bits of an integer tag in one request with the read modify write service (0x4E).
+//
+// The controller does new = (old | orMask) & andMask on the word itself so bits that ladder logic changes between
+// our request and its reply aren't lost the way they would be with a read followed by a write.  Leave andMask all ones
+// to only set bits and orMask zero to only clear them.
+//
+// The tag has to be a SINT, INT, DINT, LINT or one of their unsigned or bit string versions.  Its type comes from
+// KnownTags if it is in there (see ListAllTags) and otherwise from reading it once.  orMask can't have bits set past
+// the width of the tag.  andMask has to be all ones or all zeros past the width, so ^uint64(0x0F) clears the low four
+// bits of any size of tag.
+func (client *Client) ReadModifyWrite(tag string, orMask, andMask uint64) error {
+	err := client.checkConnection()
+	if err != nil {
+		return fmt.Errorf("could not start read modify write of %s: %w", tag, err)
+	}
+	datatype, err := client.rmwWordType(tag)
+	if err != nil {
+		return err
+	}
+	return client.readModifyWriteWord(tag, datatype, orMask, andMask)
+}
+
+// ReadModifyWrite once the type of the word is known.
+func (client *Client) readModifyWriteWord(tag string, datatype CIPType, orMask, andMask uint64) error {
+	size := datatype.Size()
+	if size < 8 {
+		width := uint(size * 8)
+		if orMask>>width != 0 {
+			return fmt.Errorf("OR mask %#x is wider than %s %s", orMask, datatype, tag)
+		}
+		high := andMask >> width
+		if high != 0 && high != ^uint64(0)>>width {
+			return fmt.Errorf("AND mask %#x is wider than %s %s", andMask, datatype, tag)
+		}
+	}
+	ioi, err := client.newIOI(tag, datatype)
+	if err != nil {
+		return fmt.Errorf("problem generating IOI. %w", err)
+	}
+	if ioi.BitAccess {
+		return fmt.Errorf("can't read modify write bit %d of %s. use the masks on the whole word instead", ioi.BitPosition, tag)
+	}
+	or_mask := binary.LittleEndian.AppendUint64(nil, orMask)[:size]
+	and_mask := binary.LittleEndian.AppendUint64(nil, andMask)[:size]
+	return client.readModifyWrite(tag, ioi, or_mask, and_mask, 0)
+}
+
+// the type of an integer tag for ReadModifyWrite.
+func (client *Client) rmwWordType(tag string) (CIPType, error) {
+	datatype := CIPTypeUnknown
+	known, ok := client.KnownTags[strings.ToLower(tag)]
+	if ok && known.Info.Atomic() && known.Info.Dimension1 == 0 {
+		datatype = known.Info.Type
+	} else {
+		_, hdr, _, err := client.read_reply(tag, CIPTypeUnknown, 1, 0)
+		if err != nil {
+			return CIPTypeUnknown, fmt.Errorf("problem getting the type of %s: %w", tag, err)
+		}
+		datatype = hdr.Type
+	}
+	if isIntegerWord(datatype) {
+		return datatype, nil
+	}
+	return CIPTypeUnknown, fmt.Errorf("can't read modify write %s. it is a %v not an integer", tag, datatype)
+}
+
+// send a read modify write request with the masks for the word ioi points to.  The masks are in little endian order
+// and have to be the size of the word.
+func (client *Client) readModifyWrite(tag string, ioi *tagIOI, or_mask, and_mask []byte, timeout time.Duration) error {
+	if isBigEndian(client.byteOrder()) {
+		// the masks line up with the bytes of the word the way the device stores it.
+		slices.Reverse(or_mask)
+		slices.Reverse(and_mask)
+	}
+
+	ioi_header := msgCIPIOIHeader{
+		Sequence: uint16(sequencer()),
+		Service:  cipService_ReadModWrite,
+		Size:     byte(len(ioi.Buffer) / 2),
+	}
+	reqitems := make([]CIPItem, 2)
+	reqitems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
+	reqitems[1] = CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+	reqitems[1].Serialize(ioi_header)
+	reqitems[1].Serialize(ioi.Buffer)
+	reqitems[1].Serialize(uint16(len(or_mask)))
+	reqitems[1].Serialize(or_mask)
+	reqitems[1].Serialize(and_mask)
+
+	itemdata, err := serializeItems(reqitems)
+	if err != nil {
+		return err
+	}
+	hdr, data, err := client.send_recv_data_timeout(client.writeTimeout(timeout), cipCommandSendUnitData, itemdata)
+	if err != nil {
+		return err
+	}
+	if hdr.Status != 0 {
+		return fmt.Errorf("got non-success status %d when writing", hdr.Status)
+	}
+	read_result_header := msgCIPResultHeader{}
+	err = binary.Read(data, binary.LittleEndian, &read_result_header)
+	if err != nil {
+		client.Logger.Warn("Problem reading read result header", "error", err)
+	}
+	items, err := readItems(data)
+	if err != nil {
+		return fmt.Errorf("problem reading items from write, %w", err)
+	}
+	if len(items) != 2 {
+		return fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(items))
+	}
+	var hdr2 msgWriteResultHeader
+	err = items[1].DeSerialize(&hdr2)
+	if err != nil {
+		return fmt.Errorf("problem deserializing write response header, %w", err)
+	}
+	if hdr2.Status != CIPStatus_OK {
+		return fmt.Errorf("problem writing %s: %w", tag, writeReplyError(hdr2, &items[1]))
+	}
+	return nil
+}
+
+// the size in bytes of the word a bit tag is in.
+//
+// The word can be a tag or a member of a structure (ex: "MyUDT.Flags.12").  Its type comes from KnownTags or the
+// structure's template if they have it and from reading the word once otherwise.  Anything that isn't an integer
+// gets a DINT's size.
+func (client *Client) bitWordSize(tag string) (int, error) {
+	i := strings.LastIndex(tag, ".")
+	if i < 0 {
+		return CIPTypeDINT.Size(), nil
+	}
+	word := tag[:i]
+	datatype, ok := client.knownWordType(word)
+	if !ok {
+		_, hdr, _, err := client.read_reply(word, CIPTypeUnknown, 1, 0)
+		if err != nil {
+			return 0, fmt.Errorf("problem getting the type of %s: %w", word, err)
+		}
+		datatype = hdr.Type
+	}
+	if isIntegerWord(datatype) {
+		return datatype.Size(), nil
+	}
+	return CIPTypeDINT.Size(), nil
+}
+
+// the type of an atomic tag or structure member without reading it.  ok is false if the type isn't known.
+func (client *Client) knownWordType(word string) (CIPType, bool) {
+	known, ok := client.KnownTags[strings.ToLower(word)]
+	if ok {
+		if !known.Info.Atomic() || known.Info.Dimension1 != 0 {
+			return CIPTypeUnknown, false
+		}
+		return known.Info.Type, true
+	}
+	i := strings.LastIndex(word, ".")
+	if i < 0 {
+		return CIPTypeUnknown, false
+	}
+	desc, err := client.templateForTag(word[:i])
+	if err != nil {
+		return CIPTypeUnknown, false
+	}
+	name := word[i+1:]
+	indexed := false
+	if pos := array_access_regex.FindStringIndex(name); pos != nil {
+		name = name[:pos[0]]
+		indexed = true
+	}
+	m, ok := desc.member(name)
+	if !ok || m.Info.Type&memberTypeStruct != 0 {
+		return CIPTypeUnknown, false
+	}
+	if m.Info.Type&memberTypeArray != 0 && !indexed {
+		return CIPTypeUnknown, false
+	}
+	return m.Info.CIPType(), true
+}
+
+// the OR and AND masks for the read modify write service that set (or clear) bit in a word of size bytes.
+func bitMasks(bit int, size int, value bool) ([]byte, []byte) {
+	or_mask := make([]byte, size)
+	and_mask := make([]byte, size)
+	for i := range and_mask {
+		and_mask[i] = 0xFF
+	}
+	if value {
+		or_mask[bit/8] |= 1 << (bit % 8)
+	} else {
+		and_mask[bit/8] &^= 1 << (bit % 8)
+	}
+	return or_mask, and_mask
+}
@@ -0,0 +1,271 @@
+package gologix
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestBitMasks(t *testing.T) {
+	var tests = []struct {
+		bit   int
+		size  int
+		value bool
+		or    []byte
+		and   []byte
+	}{
+		{0, 4, true, []byte{0x01, 0, 0, 0}, []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{3, 4, false, []byte{0, 0, 0, 0}, []byte{0xF7, 0xFF, 0xFF, 0xFF}},
+		{8, 4, true, []byte{0, 0x01, 0, 0}, []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{31, 4, true, []byte{0, 0, 0, 0x80}, []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{31, 4, false, []byte{0, 0, 0, 0}, []byte{0xFF, 0xFF, 0xFF, 0x7F}},
+		{15, 2, false, []byte{0, 0}, []byte{0xFF, 0x7F}},
+	}
+	for _, tt := range tests {
+		or, and := bitMasks(tt.bit, tt.size, tt.value)
+		if !check_bytes(or, tt.or) || !check_bytes(and, tt.and) {
+			t.Errorf("bit %d of %d bytes = %v: wanted %v/%v got %v/%v", tt.bit, tt.size, tt.value, to_hex(tt.or), to_hex(tt.and), to_hex(or), to_hex(and))
+		}
+	}
+}
+
+// answer the read of a bit write's word with a value of type typ, then the read modify write.  The read modify write
+// request is sent on requests.
+func serveBitWrite(t *testing.T, remote net.Conn, typ CIPType, requests chan []byte) {
+	hdr, _, err := recvData(remote)
+	if err != nil {
+		t.Errorf("problem reading request: %v", err)
+		return
+	}
+	item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+	item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: typ})
+	item.Serialize(make([]byte, typ.Size()))
+	b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+	writeTestResponse(t, remote, hdr.Context, *b)
+
+	hdr, buf, err := recvData(remote)
+	if err != nil {
+		t.Errorf("problem reading request: %v", err)
+		return
+	}
+	// items header, connection address item, connected data item header
+	requests <- buf.Bytes()[20:]
+	item = CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+	item.Serialize(msgWriteResultHeader{Service: cipService_ReadModWrite.AsResponse()})
+	b, err = serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+	if err != nil {
+		t.Errorf("problem building reply: %v", err)
+		return
+	}
+	writeTestResponse(t, remote, hdr.Context, *b)
+}
+
+// a word that isn't in KnownTags is read once to get the size of the masks.
+func TestWriteBit(t *testing.T) {
+	var tests = []struct {
+		typ  CIPType
+		mask []byte
+	}{
+		{CIPTypeDINT, []byte{
+			0x04, 0x00, // mask size
+			0x00, 0x00, 0x00, 0x00, // OR mask
+			0xFF, 0xFD, 0xFF, 0xFF, // AND mask
+		}},
+		{CIPTypeINT, []byte{
+			0x02, 0x00, // mask size
+			0x00, 0x00, // OR mask
+			0xFF, 0xFD, // AND mask
+		}},
+	}
+	for _, tt := range tests {
+		client, remote := newPipeClient(t)
+		requests := make(chan []byte, 1)
+		go serveBitWrite(t, remote, tt.typ, requests)
+
+		done := make(chan error)
+		go func() { done <- client.Write("Status.9", false) }()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("%v: problem writing bit: %v", tt.typ, err)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("%v: timed out waiting for Write", tt.typ)
+		}
+
+		req := <-requests
+		want := []byte{
+			byte(cipService_ReadModWrite), 0x04, // path size in words
+			0x91, 0x06, 's', 't', 'a', 't', 'u', 's',
+		}
+		want = append(want, tt.mask...)
+		if !check_bytes(req[2:], want) {
+			t.Errorf("%v:\nwanted %v\ngot    %v", tt.typ, to_hex(want), to_hex(req[2:]))
+		}
+	}
+}
+
+func TestWriteBitErrors(t *testing.T) {
+	client, _ := newPipeClient(t)
+	client.KnownTags["flags"] = KnownTag{Info: TagInfo{Type: CIPTypeINT}}
+	client.KnownTags["status"] = KnownTag{Info: TagInfo{Type: CIPTypeDINT}}
+
+	err := client.Write("Flags.16", true)
+	if err == nil {
+		t.Errorf("bit 16 of an INT should be out of range")
+	}
+	err = client.Write("Status.64", true)
+	if err == nil {
+		t.Errorf("bit 64 should be out of range")
+	}
+	err = client.Write("Status.3", int32(1))
+	if err == nil {
+		t.Errorf("only bools should be writable to a bit")
+	}
+}
+
+func TestReadModifyWrite(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.KnownTags["flags"] = KnownTag{Info: TagInfo{Type: CIPTypeINT}, Instance: 7}
+
+	requests := make(chan []byte, 1)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		requests <- buf.Bytes()[20:]
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgWriteResultHeader{Service: cipService_ReadModWrite.AsResponse()})
+		items := []CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item}
+		b, err := serializeItems(items)
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	done := make(chan error)
+	go func() { done <- client.ReadModifyWrite("Flags", 0x0101, ^uint64(0x0010)) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("problem with read modify write: %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("timed out waiting for ReadModifyWrite")
+	}
+
+	req := <-requests
+	want := []byte{
+		byte(cipService_ReadModWrite), 0x02, // path size in words
+		0x20, 0x6B, 0x24, 0x07, // known tags go by symbol instance
+		0x02, 0x00, // mask size
+		0x01, 0x01, // OR mask
+		0xEF, 0xFF, // AND mask
+	}
+	if !check_bytes(req[2:], want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(req[2:]))
+	}
+}
+
+func TestReadModifyWriteErrors(t *testing.T) {
+	client, _ := newPipeClient(t)
+	client.KnownTags["flags"] = KnownTag{Info: TagInfo{Type: CIPTypeINT}}
+	client.KnownTags["speed"] = KnownTag{Info: TagInfo{Type: CIPTypeREAL}}
+
+	err := client.ReadModifyWrite("Flags", 0x1_0000, ^uint64(0))
+	if err == nil {
+		t.Errorf("an OR mask past 16 bits should be too wide for an INT")
+	}
+	err = client.ReadModifyWrite("Flags", 0, 0x1_FFFF)
+	if err == nil {
+		t.Errorf("an AND mask with some bits past 16 set should be too wide for an INT")
+	}
+	err = client.ReadModifyWrite("Speed", 1, ^uint64(0))
+	if err == nil {
+		t.Errorf("a REAL isn't an integer")
+	}
+}
+
+// a bit of an INT member of a structure nested in another structure.
+func TestMemberBit(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.KnownTags["machine"] = KnownTag{Name: "Machine", UDT: &UDTDescriptor{
+		Name:    "Machine_UDT",
+		Members: []UDTMemberDescriptor{{Name: "Status", Info: msgMemberInfo{Type: memberTypeStruct | 0x200}}},
+	}}
+	client.KnownTypes["Status_UDT"] = UDTDescriptor{
+		Instance_ID: 0x200,
+		Name:        "Status_UDT",
+		Members:     []UDTMemberDescriptor{{Name: "Flags", Info: msgMemberInfo{Type: uint16(CIPTypeINT)}}},
+	}
+
+	requests := make(chan []byte, 2)
+	go func() {
+		// the read
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		requests <- buf.Bytes()[20:]
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: CIPTypeINT})
+		item.Serialize(int16(0x1000))
+		b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		writeTestResponse(t, remote, hdr.Context, *b)
+
+		// the write
+		hdr, buf, err = recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		requests <- buf.Bytes()[20:]
+		item = CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgWriteResultHeader{Service: cipService_ReadModWrite.AsResponse()})
+		b, _ = serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	path := []byte{
+		0x91, 0x07, 'm', 'a', 'c', 'h', 'i', 'n', 'e', 0x00,
+		0x91, 0x06, 's', 't', 'a', 't', 'u', 's',
+		0x91, 0x05, 'f', 'l', 'a', 'g', 's', 0x00,
+	}
+
+	v, err := Read[bool](client, "Machine.Status.Flags.12")
+	if err != nil {
+		t.Fatalf("problem reading bit: %v", err)
+	}
+	if !v {
+		t.Errorf("bit 12 of 0x1000 should be set")
+	}
+	req := <-requests
+	if !check_bytes(req[4:4+len(path)], path) {
+		t.Errorf("\nwanted path %v\ngot        %v", to_hex(path), to_hex(req[4:4+len(path)]))
+	}
+
+	err = client.Write("Machine.Status.Flags.12", false)
+	if err != nil {
+		t.Fatalf("problem writing bit: %v", err)
+	}
+	req = <-requests
+	want := append([]byte{byte(cipService_ReadModWrite), byte(len(path) / 2)}, path...)
+	want = append(want,
+		0x02, 0x00, // the mask is the size of an INT
+		0x00, 0x00, // OR mask
+		0xFF, 0xEF, // AND mask
+	)
+	if !check_bytes(req[2:], want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(req[2:]))
+	}
+
+	_, err = Read[bool](client, "Machine.Status.12.Flags")
+	if err == nil {
+		t.Errorf("a bit in the middle of a path should be an error")
+	}
+}
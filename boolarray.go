@@ -0,0 +1,131 @@
+package gologix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Read count elements of a BOOL array.
+//
+// The controller stores BOOL arrays packed 32 bits to a DWORD so we read the DWORDs that cover the requested
+// bits and unpack them.  count does not need to be a multiple of 32; the padding bits at the end of the last
+// DWORD are ignored.
+//
+// If the tag has an index (ex: "MyBools[37]") the read starts at that element.  A bit of an integer tag
+// (ex: "MyDint.5") can be read with a count of 1.
+func (client *Client) ReadBoolArray(tag string, count int) ([]bool, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("count must be at least 1. got %d", count)
+	}
+
+	t, err := parse_tag_name(tag)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing tag %s: %w", tag, err)
+	}
+	if t.BitAccess {
+		if count != 1 {
+			return nil, fmt.Errorf("can only read 1 element from bit access tag %s. got %d", tag, count)
+		}
+		v, err := read[bool](client, tag, 0)
+		if err != nil {
+			return nil, err
+		}
+		return []bool{v}, nil
+	}
+
+	base, start, err := boolArrayBase(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := start % 32
+	firstWord := start / 32
+	words := (offset + count + 31) / 32
+
+	var packed []uint32
+	if words == 1 {
+		// a single element has to be read as an atomic uint32
+		v, err := read[uint32](client, fmt.Sprintf("%s[%d]", base, firstWord), 0)
+		if err != nil {
+			return nil, err
+		}
+		packed = []uint32{v}
+	} else {
+		packed, err = readArray[uint32](client, fmt.Sprintf("%s[%d]", base, firstWord), uint16(words), 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(packed) != words {
+			return nil, fmt.Errorf("got %d instead of %d words", len(packed), words)
+		}
+	}
+
+	return unpackBools(packed, offset, count), nil
+}
+
+// split a BOOL array tag into the array name and the element index it starts at.
+// "MyBools" is element 0 and "MyBools[37]" is element 37.
+func boolArrayBase(tag string) (string, int, error) {
+	pos := array_access_regex.FindStringIndex(tag)
+	if pos == nil {
+		return tag, 0, nil
+	}
+	index_text := tag[pos[0]+1 : pos[1]-1]
+	index, err := strconv.Atoi(index_text)
+	if err != nil {
+		return "", 0, fmt.Errorf("BOOL arrays only have one dimension. couldn't parse index %v: %w", index_text, err)
+	}
+	if index < 0 {
+		return "", 0, fmt.Errorf("index %d out of range", index)
+	}
+	return tag[:pos[0]], index, nil
+}
+
+// whether tag is a single element of a BOOL array (ex: "MyBools[37]" or "MyUDT.Flags[3]") and if so the array and
+// the index.  A bool can't go in an element of any other kind of array so anything ending in an index is taken as a
+// BOOL array unless KnownTags says it is something else.
+func (client *Client) boolArrayElement(tag string) (string, int, bool) {
+	base, index, ok := arrayElement(tag)
+	if !ok {
+		return "", 0, false
+	}
+	if known, ok := client.KnownTags[strings.ToLower(base)]; ok && known.Info.Type != CIPTypeBOOL {
+		return "", 0, false
+	}
+	return base, index, true
+}
+
+// write one element of a BOOL array.
+//
+// The controller stores BOOL arrays packed 32 bits to a DWORD and an index in the path picks a DWORD, not a bit, so
+// the write is a read modify write of bit index%32 of DWORD index/32.  Only that bit changes so the rest of the word,
+// including the padding past the end of an array whose length isn't a multiple of 32, is left alone and ladder logic
+// writing other bits of the word at the same time doesn't lose anything.
+//
+// The padding bits of the last DWORD can be written without an error from the controller so an index past the end is
+// checked against the array's length when it is in KnownTags (see ListAllTags).
+func (client *Client) writeBoolElement(base string, index int, value bool, timeout time.Duration) error {
+	known, ok := client.KnownTags[strings.ToLower(base)]
+	if ok && known.Info.Dimension1 > 0 && index >= int(known.Info.Dimension1) {
+		return fmt.Errorf("element %d is past the end of %s which has %d", index, base, known.Info.Dimension1)
+	}
+	word := fmt.Sprintf("%s[%d]", base, index/32)
+	ioi, err := client.newIOI(word, CIPTypeDWORD)
+	if err != nil {
+		return fmt.Errorf("problem generating IOI. %w", err)
+	}
+	or_mask, and_mask := bitMasks(index%32, CIPTypeDWORD.Size(), value)
+	return client.readModifyWrite(word, ioi, or_mask, and_mask, timeout)
+}
+
+// unpack count bits from the packed words starting at bit offset of the first word.
+func unpackBools(packed []uint32, offset, count int) []bool {
+	result := make([]bool, count)
+	for i := range result {
+		bit := offset + i
+		result[i] = packed[bit/32]&(1<<(bit%32)) != 0
+	}
+	return result
+}
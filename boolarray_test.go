@@ -0,0 +1,127 @@
+package gologix
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUnpackBools(t *testing.T) {
+	var tests = []struct {
+		name   string
+		packed []uint32
+		offset int
+		count  int
+		want   []bool
+	}{
+		{"first bits", []uint32{0b1011}, 0, 4, []bool{true, true, false, true}},
+		{"offset into word", []uint32{0b1011_0000}, 4, 4, []bool{true, true, false, true}},
+		{"padding ignored", []uint32{0xFFFF_FFFF}, 0, 3, []bool{true, true, true}},
+		{"word boundary", []uint32{1 << 31, 1}, 31, 2, []bool{true, true}},
+		{"second word only", []uint32{0, 0b10}, 32, 2, []bool{false, true}},
+	}
+
+	for _, tt := range tests {
+		have := unpackBools(tt.packed, tt.offset, tt.count)
+		if !slices.Equal(have, tt.want) {
+			t.Errorf("%s: wanted %v got %v", tt.name, tt.want, have)
+		}
+	}
+}
+
+func TestBoolArrayBase(t *testing.T) {
+	var tests = []struct {
+		tag   string
+		base  string
+		start int
+		fail  bool
+	}{
+		{"MyBools", "MyBools", 0, false},
+		{"MyBools[37]", "MyBools", 37, false},
+		{"Program:Main.Flags[64]", "Program:Main.Flags", 64, false},
+		{"MyBools[1,2]", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		base, start, err := boolArrayBase(tt.tag)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.tag)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.tag, err)
+			continue
+		}
+		if base != tt.base || start != tt.start {
+			t.Errorf("%s: wanted %s/%d got %s/%d", tt.tag, tt.base, tt.start, base, start)
+		}
+	}
+}
+
+func TestWriteBoolElement(t *testing.T) {
+	var tests = []struct {
+		tag     string
+		value   bool
+		word    byte
+		orMask  []byte
+		andMask []byte
+	}{
+		{"Bools[37]", true, 1, []byte{0x20, 0x00, 0x00, 0x00}, []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{"Bools[0]", true, 0, []byte{0x01, 0x00, 0x00, 0x00}, []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{"Bools[63]", false, 1, []byte{0x00, 0x00, 0x00, 0x00}, []byte{0xFF, 0xFF, 0xFF, 0x7F}},
+	}
+	for _, tt := range tests {
+		client, remote := newPipeClient(t)
+		requests := make(chan []byte, 1)
+		go func() {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			requests <- buf.Bytes()[20:]
+			item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+			item.Serialize(msgWriteResultHeader{Service: cipService_ReadModWrite.AsResponse()})
+			b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}()
+
+		err := client.Write(tt.tag, tt.value)
+		if err != nil {
+			t.Errorf("%s: problem writing: %v", tt.tag, err)
+			continue
+		}
+		req := <-requests
+		want := []byte{
+			byte(cipService_ReadModWrite), 0x05, // path size in words
+			0x91, 0x05, 'b', 'o', 'o', 'l', 's', 0x00, 0x28, tt.word, // the DWORD the bit is in
+			0x04, 0x00, // mask size
+		}
+		want = append(want, tt.orMask...)
+		want = append(want, tt.andMask...)
+		if !check_bytes(req[2:], want) {
+			t.Errorf("%s:\nwanted %v\ngot    %v", tt.tag, to_hex(want), to_hex(req[2:]))
+		}
+	}
+}
+
+func TestWriteBoolElementBounds(t *testing.T) {
+	client, _ := newPipeClient(t)
+	// a BOOL[40] is two DWORDs so bits 40 to 63 are padding the controller would let us write.
+	client.KnownTags["bools"] = KnownTag{Name: "Bools", Info: TagInfo{Type: CIPTypeBOOL, TypeInfo: 0x20, Dimension1: 40}}
+	client.KnownTags["counts"] = KnownTag{Name: "Counts", Info: TagInfo{Type: CIPTypeDINT, TypeInfo: 0x20, Dimension1: 10}}
+
+	if err := client.Write("Bools[45]", true); err == nil {
+		t.Errorf("element 45 of a BOOL[40] should be past the end")
+	}
+	if _, _, ok := client.boolArrayElement("Counts[3]"); ok {
+		t.Errorf("an element of a known DINT array isn't a BOOL")
+	}
+	if base, index, ok := client.boolArrayElement("Machine.Flags[39]"); !ok || base != "Machine.Flags" || index != 39 {
+		t.Errorf("wanted Machine.Flags element 39. got %s %d %v", base, index, ok)
+	}
+	if _, err := client.encodeTagWrite(TagWrite{Tag: "Bools[3]", Value: true}); err == nil {
+		t.Errorf("a BOOL element can't go in a multiple write")
+	}
+}
@@ -0,0 +1,106 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// buffers for building outgoing messages.  Every request needs one only until it is on the wire so they are reused
+// instead of being left for the garbage collector, which adds up when polling lots of tags quickly.
+var sendBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// buffers that grew past this are dropped instead of being put back so one huge write doesn't pin the memory forever.
+const maxPooledBuffer = 64 * 1024
+
+func getSendBuffer() *bytes.Buffer {
+	buf := sendBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putSendBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	sendBufferPool.Put(buf)
+}
+
+// the encoded size of one part of a message.  The byte slices every request is built from are sized without going
+// through reflection.
+func msgSize(msg any) int {
+	switch m := msg.(type) {
+	case []byte:
+		return len(m)
+	case *[]byte:
+		return len(*m)
+	}
+	return binary.Size(msg)
+}
+
+// add one part of a message to buf.
+func writeMsg(buf *bytes.Buffer, msg any) error {
+	switch m := msg.(type) {
+	case []byte:
+		buf.Write(m)
+		return nil
+	case *[]byte:
+		buf.Write(*m)
+		return nil
+	}
+	return binary.Write(buf, binary.LittleEndian, msg)
+}
+
+// the wire format of an encapsulation header.
+func (hdr eipHeader) appendTo(b []byte) []byte {
+	b = binary.LittleEndian.AppendUint16(b, uint16(hdr.Command))
+	b = binary.LittleEndian.AppendUint16(b, hdr.Length)
+	b = binary.LittleEndian.AppendUint32(b, hdr.SessionHandle)
+	b = binary.LittleEndian.AppendUint32(b, hdr.Status)
+	b = binary.LittleEndian.AppendUint64(b, hdr.Context)
+	b = binary.LittleEndian.AppendUint32(b, hdr.Options)
+	return b
+}
+
+// the inverse of appendTo.  b has to be at least eipHeaderSize long.
+func parseEIPHeader(b []byte) eipHeader {
+	return eipHeader{
+		Command:       CIPCommand(binary.LittleEndian.Uint16(b[0:])),
+		Length:        binary.LittleEndian.Uint16(b[2:]),
+		SessionHandle: binary.LittleEndian.Uint32(b[4:]),
+		Status:        binary.LittleEndian.Uint32(b[8:]),
+		Context:       binary.LittleEndian.Uint64(b[12:]),
+		Options:       binary.LittleEndian.Uint32(b[20:]),
+	}
+}
+
+// the channels requests wait on for their response.  They go back in the pool only after the response came through
+// them so the pump can't send on one that has been handed to another request.
+var pendingPool = sync.Pool{
+	New: func() any { return make(chan pumpResponse, 1) },
+}
+
+// timers for waiting on responses.  Like the channels, only a timer that was stopped before it fired is put back.
+var timerPool sync.Pool
+
+func getTimer(d time.Duration) *time.Timer {
+	if t, ok := timerPool.Get().(*time.Timer); ok {
+		t.Reset(d)
+		return t
+	}
+	return time.NewTimer(d)
+}
+
+func putTimer(t *time.Timer) {
+	if !t.Stop() {
+		// it fired while we were taking the response.  drain it so the next Reset starts clean.
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	timerPool.Put(t)
+}
@@ -0,0 +1,44 @@
+package gologix
+
+// ChangeCounter is a snapshot of the controller's change tracking attributes.  They change whenever a program is
+// downloaded, an online edit is accepted, or tags or data types are added or removed, which are the times tag
+// instance IDs can change.  Get one with GetChangeCounter and compare it to a later one with Changed.
+//
+// Logix doesn't say when the change happened so there is no timestamp, only whether something changed.
+type ChangeCounter struct {
+	props msgGetControllerPropList
+}
+
+// Whether the controller has changed since the earlier snapshot.
+func (c ChangeCounter) Changed(since ChangeCounter) bool {
+	return !c.props.Match(since.props)
+}
+
+// Read the controller's change tracking attributes.  These are attributes 1, 2, 3, 4, and 10 of the controller
+// object (class 0xAC) that 1756-PM020 says to watch for changes.
+//
+// A download makes the instance IDs in KnownTags stale so a typical use is
+//
+//	if now, err := client.GetChangeCounter(); err == nil && now.Changed(last) {
+//		client.ClearKnownTags()
+//		err = client.ListAllTags(0)
+//		last = now
+//	}
+func (client *Client) GetChangeCounter() (ChangeCounter, error) {
+	props, err := client.GetControllerPropList()
+	if err != nil {
+		return ChangeCounter{}, err
+	}
+	return ChangeCounter{props: props}, nil
+}
+
+// Forget everything learned from ListAllTags, ListAllPrograms, and ListMembers along with the cached tag paths so new
+// requests go by name until the tags are listed again.
+func (client *Client) ClearKnownTags() {
+	client.ioi_cache_lock.Lock()
+	defer client.ioi_cache_lock.Unlock()
+	client.KnownTags = make(map[string]KnownTag)
+	client.KnownTypes = make(map[string]UDTDescriptor)
+	client.KnownPrograms = nil
+	client.ioi_cache = make(map[string]*tagIOI)
+}
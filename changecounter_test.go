@@ -0,0 +1,82 @@
+package gologix
+
+import "testing"
+
+func TestChangeCounter(t *testing.T) {
+	client, remote := newPipeClient(t)
+
+	replies := []msgGetControllerPropList{
+		{Attr1_ID: 1, Attr1: 5, Attr2_ID: 2, Attr2: 6, Attr3_ID: 3, Attr3: 7, Attr4_ID: 4, Attr4: 8, Attr5_ID: 10, Attr5: 9},
+		{Attr1_ID: 1, Attr1: 5, Attr2_ID: 2, Attr2: 6, Attr3_ID: 3, Attr3: 7, Attr4_ID: 4, Attr4: 8, Attr5_ID: 10, Attr5: 9},
+		{Attr1_ID: 1, Attr1: 5, Attr2_ID: 2, Attr2: 6, Attr3_ID: 3, Attr3: 8, Attr4_ID: 4, Attr4: 8, Attr5_ID: 10, Attr5: 9},
+	}
+	go func() {
+		for _, props := range replies {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			path := buf.Bytes()[24:28]
+			if !check_bytes(path, []byte{0x20, 0xAC, 0x24, 0x01}) {
+				t.Errorf("wanted a request to the controller object. got %v", to_hex(path))
+			}
+			item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+			item.Serialize(cipAttributeResponseHdr{ResponseService: CIPService_GetAttributeList.AsResponse()})
+			item.Serialize(uint16(5))
+			item.Serialize(props)
+			b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+			if err != nil {
+				t.Errorf("problem building reply: %v", err)
+				return
+			}
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}
+	}()
+
+	first, err := client.GetChangeCounter()
+	if err != nil {
+		t.Fatalf("problem getting change counter: %v", err)
+	}
+	same, err := client.GetChangeCounter()
+	if err != nil {
+		t.Fatalf("problem getting change counter: %v", err)
+	}
+	if same.Changed(first) {
+		t.Errorf("nothing changed on the controller")
+	}
+	changed, err := client.GetChangeCounter()
+	if err != nil {
+		t.Fatalf("problem getting change counter: %v", err)
+	}
+	if !changed.Changed(first) {
+		t.Errorf("attribute 3 changed so the controller did")
+	}
+}
+
+func TestClearKnownTags(t *testing.T) {
+	client := NewClient("localhost")
+	client.knownFirmware = 32
+	client.KnownTags["count"] = KnownTag{Name: "Count", Info: TagInfo{Type: CIPTypeDINT}, Instance: 7}
+	client.KnownPrograms = map[string]*KnownProgram{"MainProgram": {Name: "MainProgram", ID: 1}}
+
+	ioi, err := client.newIOI("Count", CIPTypeDINT)
+	if err != nil {
+		t.Fatalf("problem building ioi: %v", err)
+	}
+	if !check_bytes(ioi.Buffer, []byte{0x20, 0x6B, 0x24, 0x07}) {
+		t.Fatalf("a known tag should go by instance. got %v", to_hex(ioi.Buffer))
+	}
+
+	client.ClearKnownTags()
+	if len(client.KnownTags) != 0 || len(client.KnownTypes) != 0 || len(client.KnownPrograms) != 0 {
+		t.Errorf("everything known should be gone")
+	}
+	ioi, err = client.newIOI("Count", CIPTypeDINT)
+	if err != nil {
+		t.Fatalf("problem building ioi: %v", err)
+	}
+	if !check_bytes(ioi.Buffer, []byte{0x91, 0x05, 'c', 'o', 'u', 'n', 't', 0x00}) {
+		t.Errorf("a forgotten tag should go by name. got %v", to_hex(ioi.Buffer))
+	}
+}
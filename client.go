@@ -2,7 +2,10 @@ package gologix
 
 import (
 	"bytes"
-	"log"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
 	"math/rand"
 	"net"
 	"sync"
@@ -45,23 +48,116 @@ type Controller struct {
 type Client struct {
 	Controller Controller
 
-	SerialNumber uint32 // serial number for the client
+	// The originator serial number and vendor id the forward open identifies the client by.  0 for SerialNumber picks a
+	// random one on the first Connect and VendorId defaults to 0x9999.  Give each program its own serial if the
+	// controller's connection list should tell them apart.  See ForwardOpenConfig.ConnectionSerial too.
+	SerialNumber uint32
 	VendorId     uint16 // vendor id for the client as determined from ODVA
 
 	// Used for the keepalive messages.
 	SocketTimeout time.Duration
 
+	// How long to wait for the TCP connection to open.  0 uses SocketTimeout.
+	ConnectTimeout time.Duration
+
+	// The local address to connect from, for picking the network card on a PC with more than one.  nil lets the
+	// operating system pick.  A *net.TCPAddr, *net.UDPAddr, or *net.IPAddr all work and only the IP is used unless a
+	// *net.TCPAddr gives a port.
+	LocalAddr net.Addr
+
+	// Open the connection to the controller with this instead of dialing TCP, for running the session over a tunnel
+	// or an in-memory pipe.  It gets "tcp" and the controller's host:port.  ConnectTimeout, LocalAddr, and TCPKeepAlive
+	// are up to it.  NoDelay is still set if it returns a *net.TCPConn and TLSConfig still wraps what it returns.
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// Run the EtherNet/IP session over TLS with this config for controllers that use CIP Security.  nil connects
+	// without TLS.  Secured controllers listen on port 2221 so set Controller.Port too, or use NewTLSClient.  An empty
+	// ServerName is filled in from the controller's IP address.  See PinnedTLSConfig for trusting a certificate by its
+	// fingerprint.
+	TLSConfig *tls.Config
+
+	// Period of the operating system's TCP keepalive probes on the socket.  0 uses the go default of 15 seconds and a
+	// negative value turns them off.  These find a dead peer or a half open socket even while nothing is being sent.
+	// ConnectionKeepAlive is separate.  It keeps the cip connection from timing out on the controller's side and only
+	// notices a dead peer when one of its requests times out.
+	TCPKeepAlive time.Duration
+
+	// Send small requests right away instead of letting the operating system batch them up (TCP_NODELAY).  On by
+	// default.
+	NoDelay bool
+
+	// How long to wait for the controller to respond to reads and writes.  0 uses SocketTimeout.
+	// A request that times out returns ErrTimeout without closing the session.
+	// ReadWithTimeout and WriteWithTimeout override these for a single call.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// The priority/tick time and timeout ticks of the Unconnected Send that GenericCIPUnconnectedSend and the forward
+	// close go in.  The low nibble of the tick time is a power of 2 milliseconds and the connection manager waits that
+	// many ticks for the target to answer so the default of 0x0A and 0x0E is 1024ms * 14, about 14 seconds.  A slow
+	// multi-hop route may need more.  0 keeps the default.  Requests still give up after the client's own timeout.
+	UnconnectedPriorityTick byte
+	UnconnectedTimeoutTicks byte
+
 	// Set to true to allow auto-connects on reads and writes without having to call Connect() yourself.
 	AutoConnect bool
 
+	// Set to true to re-establish the session and retry once when a read or write finds the connection dead.
+	// Retries follow ReconnectBackoff and OnReconnect, if set, is called after every attempt with its result.
+	// If the socket is still open the cip connection is redone over the same session first.  See RefreshConnection.
+	// With the socket open, a read or write answered with connection not found or connection lost is taken as the
+	// controller having dropped the cip connection.  A timeout isn't since the request may have gone through.
+	AutoReconnect    bool
+	ReconnectBackoff ReconnectBackoff
+	OnReconnect      func(attempt int, err error)
+
+	// Told about every request sent to the controller, for feeding a metrics system.  nil does nothing.
+	Metrics MetricsObserver
+
+	// Which failed reads and writes to send again, like when a busy controller says resource unavailable.  The zero
+	// value doesn't retry.
+	RetryPolicy RetryPolicy
+
+	// KeepAlive polls the controller's KeepAliveProps every KeepAliveFrequency to watch for changes and, with
+	// KeepAlivePollTags, lists the tags again when they do.  Its polls go over the cip connection so they also keep it
+	// from timing out as long as KeepAliveFrequency is shorter than the connection timeout.  Using it with
+	// ConnectionKeepAlive is fine since the heartbeat counts the polls as traffic and stays quiet.
 	KeepAliveAutoStart bool           // if the state is changed the keepalive will continue to run unless cancelled
 	KeepAliveProps     []CIPAttribute // properties monitored during keep alive
 	KeepAliveFrequency time.Duration
 	keepAliveRunning   bool
 	KeepAlivePollTags  bool
 
+	// Send a cheap request whenever the cip connection has been idle for a quarter of its timeout so the controller
+	// doesn't drop it.  The timeout is RPI times the forward open's timeout multiplier.  Unlike KeepAlive it only
+	// sends when nothing else has and doesn't watch the controller for changes.  Off by default.
+	ConnectionKeepAlive bool
+
+	// Have the connection keep alive send an encapsulation NOP (see Nop) on the checks where the cip connection isn't
+	// yet half way to timing out, and the cheap request only once it is.  A NOP doesn't count as traffic on the cip
+	// connection so it adds to the requests rather than replacing them: an idle connection gets a NOP and a request
+	// about every three quarters of the timeout instead of a request about every half.  That is fewer requests for the
+	// controller to answer but more packets overall, for links where keeping the TCP session warm matters.  If a NOP
+	// can't be sent the request goes out instead.  Off by default.
+	HeartbeatNop bool
+
 	RPI time.Duration // Request Packet Interval
 
+	// How ReadTime turns a 64 bit integer tag into a time.Time.  The zero value is Logix wall clock time.
+	TimeEncoding TimeEncoding
+
+	// Byte order of the values in tag data.  nil is little endian which is what Logix controllers use.  Some third
+	// party adapters present big endian data.  Only the values change.  The encapsulation and CIP headers, structure
+	// handles, and the characters of strings are the same either way.
+	ByteOrder binary.ByteOrder
+
+	// Timing of the ForwardOpen and whether to fall back to a standard one.  See ConnectWithConfig.
+	ForwardOpenConfig ForwardOpenConfig
+
+	// Set to true to get ErrFloatSpecial back instead of a NaN or ±Inf value when reading a REAL or LREAL.
+	// An uninitialized REAL in the controller can come through as NaN.
+	TreatFloatSpecialsAsError bool
+
 	// this keeps track of what tags are in the controller.
 	// it maps tag names to a struct which has, among other things, the instance ID and class
 	// which can be used to read the tag more efficiently than sending the ascii tag name to the
@@ -86,18 +182,45 @@ type Client struct {
 	connecting             bool
 	disconnecting          bool
 	sequenceNumber         atomic.Uint32
+	connGeneration         atomic.Uint32 // incremented on every successful connect
+	reconnect_lock         sync.Mutex
+	requestContext         atomic.Uint64 // makes the sender context of every request unique
 
-	cancel_keepalive chan struct{}
+	// requests waiting on the read pump for their response, by sender context.
+	pending      map[uint64]chan pumpResponse
+	pending_lock sync.Mutex
+	pump_err     error // why the read pump stopped. protected by pending_lock
+	pump_done    chan struct{}
+
+	cancel_keepalive  chan struct{}
+	heartbeat_stop    chan struct{}
+	lastUnitData      atomic.Int64 // unix nanoseconds of the last connected message we sent
+	timeoutMultiplier byte         // from the forward open.  the connection times out after RPI * 4 << timeoutMultiplier
+	largeForwardOpen  bool         // whether the last forward open sent was the large one
+	connInfo          ConnectionInfo
 
 	// this just lets us not have to re-process tag strings.
 	ioi_cache      map[string]*tagIOI
 	ioi_cache_lock sync.Mutex
 
-	// Replace this to capture logs
+	// Logs are thrown away by default. Replace this to capture them, such as with NewSlogLogger or NewLogger.
 	Logger        LoggerInterface
 	logger_ip_set bool
 }
 
+// Create a client like NewClient that routes to the controller over the given CIP path.  See ParsePath for the
+// format.  Use this when the controller isn't in slot 0 or is on the other side of a bridge module, such as "1,2" for
+// a processor in slot 2 behind the ENxT you connect to.  An empty path connects straight to the device at ip.
+func NewClientWithPath(ip string, path string) (*Client, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing path %q: %w", path, err)
+	}
+	client := NewClient(ip)
+	client.Controller.Path = p
+	return client, nil
+}
+
 // Create a client with reasonable defaults for the given ip address.
 //
 // Before using the client, you will probably want to call Connect().
@@ -110,7 +233,7 @@ func NewClient(ip string) *Client {
 	// default path is back plane -> slot 0
 	path, err := ParsePath("1,0")
 	if err != nil {
-		log.Panicf("this should not have failed since the path is hardcoded.  problem with path. %v", err)
+		panic(fmt.Sprintf("this should not have failed since the path is hardcoded.  problem with path. %v", err))
 	}
 	controller := Controller{
 		IpAddress: ip,
@@ -125,12 +248,20 @@ func NewClient(ip string) *Client {
 		KeepAliveAutoStart: false,
 		KeepAliveFrequency: time.Second * 30,
 		KeepAliveProps:     []CIPAttribute{1, 2, 3, 4, 10},
+		NoDelay:            true,
 		RPI:                rpiDefault,
+		ForwardOpenConfig:  ForwardOpenConfig{FallbackToStandard: true},
 		SocketTimeout:      socketTimeoutDefault,
 		KnownTags:          make(map[string]KnownTag),
 		KnownTypes:         make(map[string]UDTDescriptor),
 		ioi_cache:          make(map[string]*tagIOI),
-		Logger:             NewLogger(),
+		Logger:             NewNopLogger(),
+		ReconnectBackoff: ReconnectBackoff{
+			Initial:    time.Millisecond * 250,
+			Max:        time.Second * 10,
+			Multiplier: 2,
+			Attempts:   5,
+		},
 	}
 
 }
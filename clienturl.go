@@ -0,0 +1,128 @@
+package gologix
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Create a client from a URL so the connection settings can live in one config string or environment variable.
+//
+//	eip://host[:port][/route][?rpi=10ms&conn=large]
+//
+// The route after the host is one of
+//
+//	(nothing)        back plane slot 0, the same as NewClient
+//	/slot/2          back plane slot 2
+//	/path/1,2,2,...  any CIP path in the format ParsePath takes
+//	/direct          no path, for talking straight to the device at host such as a micro800
+//
+// and the query parameters are
+//
+//	rpi=10ms         the RPI as a go duration
+//	conn=large       the connection size: large (4000), standard (511), or a number of bytes
+//	timeout=5s       SocketTimeout as a go duration
+//
+// Anything else in the URL is an error instead of being ignored so a typo doesn't go unnoticed.
+func NewClientFromURL(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing url: %w", err)
+	}
+	if u.Scheme != "eip" {
+		return nil, fmt.Errorf("url scheme should be eip. got %q", u.Scheme)
+	}
+	if u.User != nil || u.Fragment != "" {
+		return nil, fmt.Errorf("eip urls don't take a user or a fragment")
+	}
+	host := u.Hostname()
+	if host == "" {
+		return nil, fmt.Errorf("no host in %q", rawURL)
+	}
+	client := NewClient(host)
+	if p := u.Port(); p != "" {
+		port, err := strconv.ParseUint(p, 10, 16)
+		if err != nil || port == 0 {
+			return nil, fmt.Errorf("bad port %q", p)
+		}
+		client.Controller.Port = uint(port)
+	}
+
+	err = client.setURLRoute(strings.Trim(u.Path, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range u.Query() {
+		if len(values) != 1 {
+			return nil, fmt.Errorf("%s given %d times", key, len(values))
+		}
+		v := values[0]
+		switch key {
+		case "rpi":
+			rpi, err := time.ParseDuration(v)
+			if err != nil || rpi <= 0 {
+				return nil, fmt.Errorf("bad rpi %q. it should be a duration like 10ms", v)
+			}
+			client.RPI = rpi
+		case "timeout":
+			timeout, err := time.ParseDuration(v)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("bad timeout %q. it should be a duration like 5s", v)
+			}
+			client.SocketTimeout = timeout
+		case "conn":
+			switch v {
+			case "large":
+				client.ConnectionSize = connSizeLargeDefault
+			case "standard":
+				client.ConnectionSize = connSizeStandardDefault
+			default:
+				size, err := strconv.ParseUint(v, 10, 16)
+				if err != nil || size == 0 {
+					return nil, fmt.Errorf("bad conn %q. it should be large, standard, or a size in bytes", v)
+				}
+				client.ConnectionSize = uint16(size)
+			}
+		default:
+			return nil, fmt.Errorf("unknown url parameter %q", key)
+		}
+	}
+	return client, nil
+}
+
+// set the path to the controller from the route part of an eip url.  See NewClientFromURL.
+func (client *Client) setURLRoute(route string) error {
+	if route == "" {
+		return nil
+	}
+	kind, rest, _ := strings.Cut(route, "/")
+	path := ""
+	switch kind {
+	case "slot":
+		slot, err := strconv.ParseUint(rest, 10, 8)
+		if err != nil {
+			return fmt.Errorf("bad slot %q", rest)
+		}
+		path = fmt.Sprintf("1,%d", slot)
+	case "path":
+		if rest == "" {
+			return fmt.Errorf("no path after /path")
+		}
+		path = rest
+	case "direct":
+		if rest != "" {
+			return fmt.Errorf("nothing goes after /direct. got %q", rest)
+		}
+	default:
+		return fmt.Errorf("unknown route %q. use /slot/N, /path/..., or /direct", route)
+	}
+	p, err := ParsePath(path)
+	if err != nil {
+		return fmt.Errorf("problem parsing path %q: %w", path, err)
+	}
+	client.Controller.Path = p
+	return nil
+}
@@ -0,0 +1,78 @@
+package gologix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewClientFromURL(t *testing.T) {
+	client, err := NewClientFromURL("eip://192.168.2.241:44819/slot/2?rpi=10ms&conn=standard&timeout=3s")
+	if err != nil {
+		t.Fatalf("problem parsing url: %v", err)
+	}
+	if client.Controller.IpAddress != "192.168.2.241" || client.Controller.Port != 44819 {
+		t.Errorf("wrong address %s:%d", client.Controller.IpAddress, client.Controller.Port)
+	}
+	if want := []byte{1, 2}; !check_bytes(client.Controller.Path.Bytes(), want) {
+		t.Errorf("wanted path %v got %v", to_hex(want), to_hex(client.Controller.Path.Bytes()))
+	}
+	if client.RPI != time.Millisecond*10 {
+		t.Errorf("wanted an rpi of 10ms. got %v", client.RPI)
+	}
+	if client.ConnectionSize != connSizeStandardDefault {
+		t.Errorf("wanted a standard connection size. got %d", client.ConnectionSize)
+	}
+	if client.SocketTimeout != time.Second*3 {
+		t.Errorf("wanted a timeout of 3s. got %v", client.SocketTimeout)
+	}
+
+	// the defaults are the same as NewClient.
+	client, err = NewClientFromURL("eip://plc1")
+	if err != nil {
+		t.Fatalf("problem parsing url: %v", err)
+	}
+	def := NewClient("plc1")
+	if client.Controller.Port != def.Controller.Port || client.RPI != def.RPI || client.ConnectionSize != def.ConnectionSize ||
+		!check_bytes(client.Controller.Path.Bytes(), def.Controller.Path.Bytes()) {
+		t.Errorf("a bare url should get the NewClient defaults")
+	}
+
+	client, err = NewClientFromURL("eip://plc1/direct?conn=1200")
+	if err != nil {
+		t.Fatalf("problem parsing url: %v", err)
+	}
+	if client.Controller.Path.Len() != 0 || client.ConnectionSize != 1200 {
+		t.Errorf("wanted no path and a connection size of 1200. got %v %d", to_hex(client.Controller.Path.Bytes()), client.ConnectionSize)
+	}
+
+	client, err = NewClientFromURL("eip://plc1/path/1,2,2,10.0.0.5,1,0")
+	if err != nil {
+		t.Fatalf("problem parsing url: %v", err)
+	}
+	want, _ := ParsePath("1,2,2,10.0.0.5,1,0")
+	if !check_bytes(client.Controller.Path.Bytes(), want.Bytes()) {
+		t.Errorf("wanted path %v got %v", to_hex(want.Bytes()), to_hex(client.Controller.Path.Bytes()))
+	}
+
+	for _, bad := range []string{
+		"http://plc1",
+		"eip://",
+		"eip://plc1:99999",
+		"eip://plc1/slot/x",
+		"eip://plc1/slot/300",
+		"eip://plc1/rack/2",
+		"eip://plc1/path/",
+		"eip://plc1?rpi=fast",
+		"eip://plc1?conn=huge",
+		"eip://plc1?rpi=10ms&rpi=20ms",
+		"eip://plc1?slot=2",
+		"eip://user@plc1",
+		"eip://plc1/path/1,x",
+		"::",
+	} {
+		_, err := NewClientFromURL(bad)
+		if err == nil {
+			t.Errorf("%s: wanted an error", bad)
+		}
+	}
+}
@@ -2,10 +2,12 @@ package gologix
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand"
 	"net"
 	"slices"
 	"time"
@@ -21,12 +23,161 @@ const (
 	rpiDefault              = time.Millisecond * 2500
 )
 
+// ForwardOpenConfig holds the parameters of the ForwardOpen that sets up the cip connection.
+//
+// The zero value of each field keeps the default.  The requested packet interval and connection size are the
+// client's RPI and ConnectionSize fields.  ConnectWithConfig copies them over from here if they are set.
+type ForwardOpenConfig struct {
+	RPI            time.Duration // requested packet interval.
+	ConnectionSize uint16        // bytes per message.  Sizes over 511 use the large forward open (0x5B).
+
+	// priority/tick time.  The low nibble is the tick time as a power of 2 milliseconds.  Defaults to 0x0A for the
+	// large forward open and 0x07 for the standard one.
+	PriorityTick byte
+	// how many ticks the controller waits on an unconnected message before giving up.  Defaults to 0x0E for the large
+	// forward open and 0xE9 for the standard one.
+	TimeoutTicks byte
+	// transport class and trigger.  Defaults to 0xA3 which is a server connection, application triggered, class 3.
+	// Setting this overrides TransportClass.
+	TransportTrigger byte
+
+	// the transport class of the connection the reads and writes go over.  0 is class 3, the connected explicit
+	// messaging that Logix controllers take, for both the standard and large forward open.  Class 2 is the only other
+	// class that carries explicit messages.  Classes 0 and 1 are for implicit I/O and can't be used by the client.
+	TransportClass byte
+
+	// how many RPIs the controller waits without any traffic on the connection before it drops it.  One of 4, 8, 16,
+	// 32, 64, 128, 256, or 512.  Defaults to 32 for the large forward open and 4 for the standard one.  With the
+	// default RPI of 2.5s that is 80s and 10s.  A mostly idle connection can use 512 to stay up for over 21 minutes
+	// between messages at the default RPI.  The client's heartbeat goes by this too so it only sends when needed.
+	TimeoutMultiplier uint16
+
+	// If the large forward open is refused because the controller doesn't support it or doesn't like the connection
+	// size, try again with a standard forward open and a connection size of 511.  NewClient turns this on.
+	FallbackToStandard bool
+
+	// the connection serial number of the forward open.  0 picks a new one for every forward open.  The controller
+	// goes by the serial along with the client's VendorId and SerialNumber so a fixed one makes the connection easy to
+	// pick out, but a reconnect before the controller drops the old connection is refused as a duplicate.
+	ConnectionSerial uint16
+}
+
+// the connection timeout multiplier as the forward open sends it where n means a multiplier of 4 << n.  def is
+// used when TimeoutMultiplier isn't set.
+func (cfg ForwardOpenConfig) timeoutMultiplier(def byte) byte {
+	if cfg.TimeoutMultiplier == 0 {
+		return def
+	}
+	for n := byte(0); n <= 7; n++ {
+		if 4<<n == cfg.TimeoutMultiplier {
+			return n
+		}
+	}
+	return def
+}
+
+// the timeout multiplier has to be one the forward open can send.
+func (cfg ForwardOpenConfig) checkTimeoutMultiplier() error {
+	m := cfg.TimeoutMultiplier
+	if m == 0 {
+		return nil
+	}
+	if m < 4 || m > 512 || m&(m-1) != 0 {
+		return fmt.Errorf("timeout multiplier %d isn't one of 4, 8, 16, 32, 64, 128, 256, or 512", m)
+	}
+	return nil
+}
+
+func (cfg ForwardOpenConfig) priorityTick(def byte) byte {
+	if cfg.PriorityTick == 0 {
+		return def
+	}
+	return cfg.PriorityTick
+}
+
+func (cfg ForwardOpenConfig) timeoutTicks(def byte) byte {
+	if cfg.TimeoutTicks == 0 {
+		return def
+	}
+	return cfg.TimeoutTicks
+}
+
+func (cfg ForwardOpenConfig) transportTrigger() byte {
+	if cfg.TransportTrigger != 0 {
+		return cfg.TransportTrigger
+	}
+	const (
+		server             = 0x80 // the target is the server end of the connection
+		applicationTrigger = 0x20 // messages are sent when we have something to send rather than cyclically
+	)
+	class := cfg.TransportClass
+	if class == 0 {
+		class = 3
+	}
+	return server | applicationTrigger | class
+}
+
+// the transport class in the low nibble of the transport trigger has to be one that carries explicit messages.
+func (cfg ForwardOpenConfig) checkTransport() error {
+	class := cfg.transportTrigger() & 0x0F
+	if class != 2 && class != 3 {
+		return fmt.Errorf("transport class %d can't carry explicit messages. use class 2 or 3", class)
+	}
+	return nil
+}
+
+// extended status of a connection failure when the controller doesn't like the connection size.  See
+// ErrInvalidConnectionSize.
+const extStatusInvalidConnectionSize = 0x0109
+
+// whether a refused large forward open is worth retrying as a standard forward open.
+func largeForwardOpenRefused(err error) bool {
+	var cerr *CIPError
+	if !errors.As(err, &cerr) {
+		return false
+	}
+	switch CIPStatus(cerr.General) {
+	case CIPStatus_ServiceNotSupported:
+		return true
+	case CIPStatus_ConnectionFailure:
+		return cerr.extended() == extStatusInvalidConnectionSize
+	}
+	return false
+}
+
+// Connect to the PLC with the given forward open parameters.  They stay in the client's ForwardOpenConfig and are
+// used again on reconnects.
+func (client *Client) ConnectWithConfig(ctx context.Context, cfg ForwardOpenConfig) error {
+	if cfg.RPI != 0 {
+		client.RPI = cfg.RPI
+	}
+	if cfg.ConnectionSize != 0 {
+		client.ConnectionSize = cfg.ConnectionSize
+	}
+	client.ForwardOpenConfig = cfg
+	return client.ConnectContext(ctx)
+}
+
 // Connect to the PLC.
+//
+// This is the same as calling ConnectContext with context.Background()
 func (client *Client) Connect() error {
+	return client.ConnectContext(context.Background())
+}
+
+// Connect to the PLC, aborting if ctx is cancelled or its deadline passes before the connection is established.
+//
+// Cancellation applies to the tcp dial as well as the RegisterSession and ForwardOpen exchanges.  If the context fires
+// while one of those is waiting on the controller the socket is closed and the context error is returned wrapped.
+func (client *Client) ConnectContext(ctx context.Context) error {
 	if client.disconnecting {
 		client.Logger.Debug("waiting for client to finish disconnecting before connecting")
 		for client.disconnecting {
-			time.Sleep(time.Millisecond * 10)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("gave up waiting for disconnect to finish: %w", ctx.Err())
+			case <-time.After(time.Millisecond * 10):
+			}
 		}
 	}
 	if client.connected || client.connecting {
@@ -52,6 +203,14 @@ func (client *Client) Connect() error {
 	if client.Controller.VendorId == 0 {
 		client.Controller.VendorId = vendorIdDefault
 	}
+	if client.VendorId == 0 {
+		client.VendorId = vendorIdDefault
+	}
+	// every client with the same vendor and serial looks like the same originator to the controller so pick one
+	// that won't collide with another program's.
+	for client.SerialNumber == 0 {
+		client.SerialNumber = rand.Uint32()
+	}
 	if client.SocketTimeout == 0 {
 		client.SocketTimeout = socketTimeoutDefault
 	}
@@ -76,18 +235,122 @@ func (client *Client) Connect() error {
 	}
 
 	address := fmt.Sprintf("%s:%v", client.Controller.IpAddress, client.Controller.Port)
-	client.conn, err = net.DialTimeout("tcp", address, client.SocketTimeout)
+	conn, err := client.dial(ctx, address)
 	if err != nil {
 		msg := "cannot connect to controller"
 		client.Logger.Error(msg, slog.Any("err", err))
 		return fmt.Errorf("%s: %w", msg, err)
 	}
+	if conn == nil {
+		return fmt.Errorf("cannot connect to controller: Dial returned no connection")
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		err = tcp.SetNoDelay(client.NoDelay)
+		if err != nil {
+			client.Logger.Warn("problem setting TCP_NODELAY", slog.Any("err", err))
+		}
+	}
+	if client.TLSConfig != nil {
+		conn, err = client.tlsHandshake(ctx, conn)
+		if err != nil {
+			return err
+		}
+	}
+	client.conn = conn
+	client.startPump(conn)
+
+	// if the context fires while we're waiting on the controller, closing the socket is the only way
+	// to unblock the pending read.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	err = client.openSession()
+	if !stop() {
+		// the context fired.  Even if the session came up, the socket is gone now.
+		conn.Close()
+		if err != nil {
+			return fmt.Errorf("connect aborted: %w: %w", ctx.Err(), err)
+		}
+		return fmt.Errorf("connect aborted: %w", ctx.Err())
+	}
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	client.connected = true
+	client.connGeneration.Add(1)
+
+	if client.KeepAliveAutoStart {
+		go client.KeepAlive()
+	}
+	if client.ConnectionKeepAlive {
+		client.startHeartbeat()
+	}
+	return nil
+}
+
+// how long to wait for the socket to open.
+func (client *Client) connectTimeout() time.Duration {
+	if client.ConnectTimeout > 0 {
+		return client.ConnectTimeout
+	}
+	return client.SocketTimeout
+}
+
+// open the socket to the controller with the client's Dial if it has one.
+func (client *Client) dial(ctx context.Context, address string) (net.Conn, error) {
+	if client.Dial != nil {
+		return client.Dial(ctx, "tcp", address)
+	}
+	dialer := net.Dialer{Timeout: client.connectTimeout(), KeepAlive: client.TCPKeepAlive}
+	if client.LocalAddr != nil {
+		local, err := tcpLocalAddr(client.LocalAddr)
+		if err != nil {
+			return nil, err
+		}
+		dialer.LocalAddr = local
+	}
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+// the connection serial number for a forward open.  A new one each time unless ForwardOpenConfig has one.
+func (client *Client) connectionSerial() uint16 {
+	if client.ForwardOpenConfig.ConnectionSerial != 0 {
+		return client.ForwardOpenConfig.ConnectionSerial
+	}
+	return uint16(client.sequenceNumber.Add(1))
+}
+
+// the dialer only takes a *net.TCPAddr for a tcp local address.
+func tcpLocalAddr(addr net.Addr) (*net.TCPAddr, error) {
+	if a, ok := addr.(*net.TCPAddr); ok {
+		return a, nil
+	}
+	ip := addrIP(addr)
+	if ip == nil {
+		return nil, fmt.Errorf("can't connect from local address %v", addr)
+	}
+	return &net.TCPAddr{IP: ip}, nil
+}
 
+// register the session and open the cip connection on an already dialed socket.
+func (client *Client) openSession() error {
+	err := client.ForwardOpenConfig.checkTransport()
+	if err != nil {
+		return err
+	}
+	err = client.ForwardOpenConfig.checkTimeoutMultiplier()
+	if err != nil {
+		return err
+	}
 	err = client.registerSession()
 	if err != nil {
 		return err
 	}
+	return client.openConnection()
+}
 
+// open the cip connection with a forward open over the registered session.  The large forward open is tried first if
+// the connection size needs it.
+func (client *Client) openConnection() error {
 	if client.ConnectionSize > connSizeStandardMax {
 		item, err := client.newForwardOpenLarge()
 		if err != nil {
@@ -95,7 +358,11 @@ func (client *Client) Connect() error {
 		}
 		err = client.forwardOpen(item)
 		if err != nil {
-			client.Logger.Warn("large forward open failed. falling back to standard forward open", slog.Any("err", err))
+			if !client.ForwardOpenConfig.FallbackToStandard || !largeForwardOpenRefused(err) {
+				client.Logger.Error("unable to open large connection", slog.Any("err", err))
+				return err
+			}
+			client.Logger.Warn("large forward open refused. falling back to standard forward open", slog.Any("err", err))
 			client.ConnectionSize = connSizeStandardDefault
 		}
 	}
@@ -110,11 +377,6 @@ func (client *Client) Connect() error {
 			return err
 		}
 	}
-	client.connected = true
-
-	if client.KeepAliveAutoStart {
-		go client.KeepAlive()
-	}
 	return nil
 }
 
@@ -122,6 +384,46 @@ func (client *Client) Connected() bool {
 	return client.connected
 }
 
+// ConnectionInfo is what was agreed on with the controller in the forward open.
+type ConnectionInfo struct {
+	// bytes per message.  Requests and replies both have to fit in this so it bounds how many tags fit in one
+	// multiple service request.  It can be smaller than the ConnectionSize asked for if the large forward open was
+	// refused and we fell back to a standard one.
+	Size  uint16
+	Large bool // true if the large forward open (0x5B) was used.  false for the standard one (0x54).
+
+	TransportClass byte // the transport class of the connection.  3 unless ForwardOpenConfig asked for something else.
+
+	// the actual packet intervals the controller granted in each direction.
+	OTRPI time.Duration
+	TORPI time.Duration
+
+	// what identifies this session and connection on the wire, for matching it up with a packet capture.  Wireshark
+	// shows these as the session handle of the encapsulation header, the O->T and T->O network connection IDs of the
+	// forward open reply, and its connection serial number.
+	SessionHandle    uint32
+	OTConnectionID   uint32
+	TOConnectionID   uint32
+	ConnectionSerial uint16
+}
+
+// The connection parameters from the last successful forward open.  This is the zero value if the client has never
+// connected.
+func (client *Client) ConnectionInfo() ConnectionInfo {
+	return client.connInfo
+}
+
+// The network connection ID the controller gave us for what we send it, from the last successful forward open.  Every
+// connected request carries it in its address item.
+func (client *Client) OTConnectionID() uint32 {
+	return client.connInfo.OTConnectionID
+}
+
+// The network connection ID we gave the controller for what it sends back, from the last successful forward open.
+func (client *Client) TOConnectionID() uint32 {
+	return client.connInfo.TOConnectionID
+}
+
 func (client *Client) registerSession() error {
 	reg_msg := msgCIPRegister{
 		ProtocolVersion: 1,
@@ -139,6 +441,11 @@ func (client *Client) registerSession() error {
 	return nil
 }
 
+// Poll the controller's KeepAliveProps every KeepAliveFrequency until cancelled, listing the tags again when they
+// change if KeepAlivePollTags is set.  It only runs when KeepAliveAutoStart is set and Connect starts it then.
+//
+// This is separate from ConnectionKeepAlive, which only sends when the cip connection has gone idle.  The polls here
+// count as traffic so running both doesn't double up the requests.
 func (client *Client) KeepAlive() {
 	if !client.KeepAliveAutoStart || client.SocketTimeout == 0 {
 		return
@@ -291,7 +598,7 @@ func (client *Client) newForwardOpenLarge() (CIPItem, error) {
 		return item, fmt.Errorf("couldn't build path. %w", err)
 	}
 
-	client.ConnectionSerialNumber = uint16(client.sequenceNumber.Add(1))
+	client.ConnectionSerialNumber = client.connectionSerial()
 	const (
 		redundantOwner     uint32 = 0 // 0 = no-redundant, 1 = redundant
 		connectionType     uint32 = 2 // 0 = null, 1 = multicast, 2 = point to point, 3 = reserved
@@ -315,19 +622,21 @@ func (client *Client) newForwardOpenLarge() (CIPItem, error) {
 	msg.InstanceType = cipInstance_8bit
 	msg.Instance = 0x01
 	// this next section is the path
-	msg.Priority = 0x0A
-	msg.TimeoutTicks = 0x0E
+	msg.Priority = client.ForwardOpenConfig.priorityTick(0x0A)
+	msg.TimeoutTicks = client.ForwardOpenConfig.timeoutTicks(0x0E)
 	msg.OTConnectionID = client.sequenceNumber.Add(1) // pyLogix always uses 0x20000002
 	msg.TOConnectionID = client.sequenceNumber.Add(1)
 	msg.ConnectionSerialNumber = client.ConnectionSerialNumber
 	msg.VendorID = client.VendorId
 	msg.OriginatorSerialNumber = client.SerialNumber
-	msg.Multiplier = 0x03
+	client.timeoutMultiplier = client.ForwardOpenConfig.timeoutMultiplier(0x03)
+	msg.Multiplier = uint32(client.timeoutMultiplier)
+	client.largeForwardOpen = true
 	msg.OtRpi = uint32(client.RPI / time.Microsecond)
 	msg.OTNetworkConnParams = connectionParameters
 	msg.ToRpi = uint32(client.RPI / time.Microsecond)
 	msg.TONetworkConnParams = connectionParameters
-	msg.TransportTrigger = 0xA3
+	msg.TransportTrigger = client.ForwardOpenConfig.transportTrigger()
 	msg.ConnPathSize = byte(path.Len() / 2)
 
 	item.Serialize(msg)
@@ -363,7 +672,7 @@ func (client *Client) newForwardOpenStandard() (CIPItem, error) {
 		return item, fmt.Errorf("couldn't build path. %w", err)
 	}
 
-	client.ConnectionSerialNumber = uint16(client.sequenceNumber.Add(1))
+	client.ConnectionSerialNumber = client.connectionSerial()
 	const (
 		redundantOwner     uint16 = 0 // 0 = no-redundant, 1 = redundant
 		connectionType     uint16 = 2 // 0 = null, 1 = multicast, 2 = point to point, 3 = reserved
@@ -387,19 +696,21 @@ func (client *Client) newForwardOpenStandard() (CIPItem, error) {
 	msg.InstanceType = cipInstance_8bit
 	msg.Instance = 0x01
 	// end of path
-	msg.Priority = 0x07
-	msg.TimeoutTicks = 0xE9
+	msg.Priority = client.ForwardOpenConfig.priorityTick(0x07)
+	msg.TimeoutTicks = client.ForwardOpenConfig.timeoutTicks(0xE9)
 	msg.OTConnectionID = client.sequenceNumber.Add(1)
 	msg.TOConnectionID = client.sequenceNumber.Add(1)
 	msg.ConnectionSerialNumber = client.ConnectionSerialNumber
 	msg.VendorID = client.VendorId
 	msg.OriginatorSerialNumber = client.SerialNumber
-	msg.Multiplier = 0x00
+	client.timeoutMultiplier = client.ForwardOpenConfig.timeoutMultiplier(0x00)
+	msg.Multiplier = uint32(client.timeoutMultiplier)
+	client.largeForwardOpen = false
 	msg.OtRpi = uint32(client.RPI / time.Microsecond)
 	msg.OTNetworkConnParams = connectionParameters
 	msg.ToRpi = uint32(client.RPI / time.Microsecond)
 	msg.TONetworkConnParams = connectionParameters
-	msg.TransportTrigger = 0xA3
+	msg.TransportTrigger = client.ForwardOpenConfig.transportTrigger()
 	msg.ConnPathSize = byte(path.Len() / 2)
 	item.Serialize(msg)
 	item.Serialize(path.Bytes())
@@ -437,6 +748,18 @@ func (client *Client) forwardOpen(forwardOpenMsg CIPItem) error {
 	}
 
 	client.OTNetworkConnectionID = respContent.OtNetworkConnectionId
+	client.connInfo = ConnectionInfo{
+		Size:           client.ConnectionSize,
+		Large:          client.largeForwardOpen,
+		TransportClass: client.ForwardOpenConfig.transportTrigger() & 0x0F,
+		OTRPI:          time.Duration(respContent.OTApiNs) * time.Microsecond,
+		TORPI:          time.Duration(respContent.TOApiNs) * time.Microsecond,
+
+		SessionHandle:    client.SessionHandle,
+		OTConnectionID:   respContent.OtNetworkConnectionId,
+		TOConnectionID:   respContent.TOConnectionId,
+		ConnectionSerial: respContent.ConnectionSerialNumber,
+	}
 
 	client.Logger.Info(
 		"successfully opened connection",
@@ -504,6 +827,9 @@ func (client *Client) parseResponse(header *eipHeader, data *bytes.Buffer) ([]CI
 	if err != nil {
 		return nil, fmt.Errorf("problem reading items from forward open request. %w", err)
 	}
+	if len(items) != 2 {
+		return nil, fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(items))
+	}
 
 	respHeader := msgCIPMessageRouterResponse{}
 	err = items[1].DeSerialize(&respHeader)
@@ -524,7 +850,14 @@ func (client *Client) parseResponse(header *eipHeader, data *bytes.Buffer) ([]CI
 			slog.Any("status", respHeader.Status),
 			slog.String("statusDesc", respHeader.Status.String()),
 		)
-		return nil, fmt.Errorf("%s status: %v", errMsg, respHeader.Status)
+		cerr := &CIPError{General: byte(respHeader.Status)}
+		for i := 0; i+1 < len(extended_status); i += 2 {
+			cerr.Extended = append(cerr.Extended, binary.LittleEndian.Uint16(extended_status[i:]))
+		}
+		if reason := connectionFailureReason(cerr); reason != nil {
+			return nil, fmt.Errorf("%s: %w", errMsg, &connectionFailure{reason: reason, cerr: cerr})
+		}
+		return nil, fmt.Errorf("%s: %w", errMsg, cerr)
 	}
 	return items, nil
 }
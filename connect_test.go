@@ -0,0 +1,458 @@
+package gologix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// the tcp dial succeeds but the "controller" never answers the RegisterSession request.
+// ConnectContext should give up when the context does instead of waiting on the socket timeout.
+func TestConnectContextRegisterSessionTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("couldn't open listener: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			// hold the connection open without ever replying.
+			defer conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	client := NewClient(host)
+	client.Controller.Port = uint(p)
+	client.SocketTimeout = time.Second * 10
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	start := time.Now()
+	err = client.ConnectContext(ctx)
+	elapsed := time.Since(start)
+	if err == nil {
+		t.Fatalf("expected an error but connect succeeded")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected wrapped context.DeadlineExceeded. got %v", err)
+	}
+	if elapsed > time.Second*2 {
+		t.Errorf("connect took %v to return after the context expired", elapsed)
+	}
+	if client.Connected() {
+		t.Errorf("client should not report being connected")
+	}
+}
+
+func TestConnectContextDialCancelled(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.Controller.Port = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.ConnectContext(ctx)
+	if err == nil {
+		t.Fatalf("expected an error but connect succeeded")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected wrapped context.Canceled. got %v", err)
+	}
+}
+
+func TestLargeForwardOpenRefused(t *testing.T) {
+	var tests = []struct {
+		err      error
+		fallback bool
+	}{
+		{fmt.Errorf("bad status on response: %w", &CIPError{General: 0x08}), true},
+		{&CIPError{General: 0x01, Extended: []uint16{extStatusInvalidConnectionSize}}, true},
+		{&CIPError{General: 0x01, Extended: []uint16{0x0100}}, false},
+		{&CIPError{General: 0x01}, false},
+		{ErrTimeout, false},
+	}
+	for _, tt := range tests {
+		if have := largeForwardOpenRefused(tt.err); have != tt.fallback {
+			t.Errorf("%v: wanted %v got %v", tt.err, tt.fallback, have)
+		}
+	}
+	if !NewClient("127.0.0.1").ForwardOpenConfig.FallbackToStandard {
+		t.Errorf("a new client should fall back to the standard forward open")
+	}
+}
+
+// a refused forward open says why with a named error and still has the raw status.
+func TestForwardOpenRefusalReason(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	var tests = []struct {
+		extended []uint16
+		want     error
+	}{
+		{[]uint16{0x0109}, ErrInvalidConnectionSize},
+		{[]uint16{0x0100}, ErrConnectionInUse},
+		{[]uint16{0x0113}, ErrOutOfConnections},
+		{[]uint16{0x0315}, ErrBadConnectionPath},
+		{[]uint16{0x0999}, nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		reply := CIPItem{Header: cipItemHeader{ID: cipItem_UnconnectedData}}
+		reply.Serialize(msgCIPMessageRouterResponse{Service: CIPService_LargeForwardOpen.AsResponse(), Status: CIPStatus_ConnectionFailure, StatusLen: byte(len(tt.extended))})
+		reply.Serialize(tt.extended)
+		dat, err := serializeItems([]CIPItem{{Header: cipItemHeader{ID: cipItem_Null}}, reply})
+		if err != nil {
+			t.Fatalf("problem building reply: %v", err)
+		}
+		_, err = client.parseResponse(&eipHeader{}, bytes.NewBuffer(*dat))
+		var cerr *CIPError
+		if !errors.As(err, &cerr) || cerr.General != 0x01 {
+			t.Errorf("%X: wanted a connection failure CIPError. got %v", tt.extended, err)
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%X: wanted %v. got %v", tt.extended, tt.want, err)
+		}
+		if tt.want != nil && strings.Count(err.Error(), tt.want.Error()) != 1 {
+			t.Errorf("%X: the reason should be in the message once. got %q", tt.extended, err)
+		}
+		for _, named := range []error{ErrInvalidConnectionSize, ErrConnectionInUse, ErrOutOfConnections, ErrBadConnectionPath} {
+			if named != tt.want && errors.Is(err, named) {
+				t.Errorf("%X: shouldn't be %v", tt.extended, named)
+			}
+		}
+	}
+	if msg := (&CIPError{General: 0x01, Extended: []uint16{0x0109}}).Error(); !strings.Contains(msg, "invalid connection size") {
+		t.Errorf("the message should say why the connection failed. got %q", msg)
+	}
+}
+
+func TestForwardOpenConfig(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.RPI = time.Millisecond * 500
+
+	item, err := client.newForwardOpenLarge()
+	if err != nil {
+		t.Fatalf("problem building forward open: %v", err)
+	}
+	var msg cipForwardOpen[uint32]
+	err = item.DeSerialize(&msg)
+	if err != nil {
+		t.Fatalf("problem reading forward open: %v", err)
+	}
+	if msg.Priority != 0x0A || msg.TimeoutTicks != 0x0E || msg.TransportTrigger != 0xA3 {
+		t.Errorf("wanted the default timing. got %X %X %X", msg.Priority, msg.TimeoutTicks, msg.TransportTrigger)
+	}
+	if msg.OtRpi != 500_000 || msg.ToRpi != 500_000 {
+		t.Errorf("wanted an RPI of 500ms. got %d %d", msg.OtRpi, msg.ToRpi)
+	}
+	if msg.OTNetworkConnParams&0xFFFF != connSizeLargeDefault {
+		t.Errorf("wanted the default connection size. got %d", msg.OTNetworkConnParams&0xFFFF)
+	}
+
+	client.ForwardOpenConfig = ForwardOpenConfig{PriorityTick: 0x05, TimeoutTicks: 0x20, TransportTrigger: 0x83}
+	client.ConnectionSize = 400
+	item, err = client.newForwardOpenStandard()
+	if err != nil {
+		t.Fatalf("problem building forward open: %v", err)
+	}
+	var smsg cipForwardOpen[uint16]
+	err = item.DeSerialize(&smsg)
+	if err != nil {
+		t.Fatalf("problem reading forward open: %v", err)
+	}
+	if smsg.Priority != 0x05 || smsg.TimeoutTicks != 0x20 || smsg.TransportTrigger != 0x83 {
+		t.Errorf("wanted the configured timing. got %X %X %X", smsg.Priority, smsg.TimeoutTicks, smsg.TransportTrigger)
+	}
+	if smsg.OTNetworkConnParams&0x1FF != 400 {
+		t.Errorf("wanted a connection size of 400. got %d", smsg.OTNetworkConnParams&0x1FF)
+	}
+}
+
+// the timeout multiplier goes in the forward open as n for a multiplier of 4 << n and the heartbeat follows it.  One
+// the forward open can't send fails Connect.
+func TestForwardOpenTimeoutMultiplier(t *testing.T) {
+	srv, _ := newTestServer(t, map[string]any{})
+	var tests = []struct {
+		multiplier uint16
+		n          byte
+		ok         bool
+	}{
+		{0, 0x03, true}, // the default for the large forward open
+		{4, 0, true},
+		{8, 1, true},
+		{16, 2, true},
+		{32, 3, true},
+		{64, 4, true},
+		{128, 5, true},
+		{256, 6, true},
+		{512, 7, true},
+		{3, 0, false},
+		{6, 0, false},
+		{1024, 0, false},
+	}
+	for _, tt := range tests {
+		client := NewClient("192.0.2.1")
+		client.ConnectionKeepAlive = false
+		client.RPI = time.Millisecond * 100
+		client.ForwardOpenConfig.TimeoutMultiplier = tt.multiplier
+		client.Dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			local, remote := net.Pipe()
+			h := serverTCPHandler{conn: remote, server: srv}
+			go h.serve(srv)
+			return local, nil
+		}
+		err := client.Connect()
+		if !tt.ok {
+			if err == nil {
+				client.Disconnect()
+				t.Errorf("%d: wanted Connect to fail", tt.multiplier)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d: problem connecting: %v", tt.multiplier, err)
+			continue
+		}
+		// the heartbeat checks every quarter of the connection timeout.
+		timeout := client.RPI * time.Duration(4<<tt.n)
+		if have := client.connectionTimeout(); have != timeout {
+			t.Errorf("%d: wanted a connection timeout of %v got %v", tt.multiplier, timeout, have)
+		}
+		client.Disconnect()
+
+		item, err := client.newForwardOpenLarge()
+		if err != nil {
+			t.Fatalf("%d: problem building forward open: %v", tt.multiplier, err)
+		}
+		var msg cipForwardOpen[uint32]
+		err = item.DeSerialize(&msg)
+		if err != nil {
+			t.Fatalf("%d: problem reading forward open: %v", tt.multiplier, err)
+		}
+		if msg.Multiplier != uint32(tt.n) {
+			t.Errorf("%d: wanted multiplier byte %d got %d", tt.multiplier, tt.n, msg.Multiplier)
+		}
+	}
+}
+
+func TestForwardOpenTransportClass(t *testing.T) {
+	var tests = []struct {
+		cfg     ForwardOpenConfig
+		trigger byte
+		ok      bool
+	}{
+		{ForwardOpenConfig{}, 0xA3, true},
+		{ForwardOpenConfig{TransportClass: 3}, 0xA3, true},
+		{ForwardOpenConfig{TransportClass: 2}, 0xA2, true},
+		{ForwardOpenConfig{TransportClass: 1}, 0xA1, false},
+		{ForwardOpenConfig{TransportClass: 2, TransportTrigger: 0x83}, 0x83, true},
+		{ForwardOpenConfig{TransportTrigger: 0x01}, 0x01, false},
+	}
+	for _, tt := range tests {
+		if have := tt.cfg.transportTrigger(); have != tt.trigger {
+			t.Errorf("%+v: wanted trigger %#x got %#x", tt.cfg, tt.trigger, have)
+		}
+		err := tt.cfg.checkTransport()
+		if (err == nil) != tt.ok {
+			t.Errorf("%+v: wanted ok %v got %v", tt.cfg, tt.ok, err)
+		}
+	}
+}
+
+func TestForwardOpenConnectionInfo(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.ConnectionSize = 4002
+	client.RPI = time.Millisecond * 2500
+	client.SessionHandle = 0x42
+
+	go func() {
+		hdr, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading forward open: %v", err)
+			return
+		}
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_UnconnectedData}}
+		item.Serialize(msgCIPMessageRouterResponse{Service: CIPService_LargeForwardOpen.AsResponse()})
+		item.Serialize(msgCipForwardOpenReply{OtNetworkConnectionId: 0x1234, TOConnectionId: 0x5678, ConnectionSerialNumber: 0x9A, OTApiNs: 2500000, TOApiNs: 3000000})
+		items, err := serializeItems([]CIPItem{{}, item})
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *items)
+	}()
+
+	if have := client.ConnectionInfo(); have != (ConnectionInfo{}) {
+		t.Errorf("wanted no connection info before the forward open. got %+v", have)
+	}
+	msg, err := client.newForwardOpenLarge()
+	if err != nil {
+		t.Fatalf("problem building forward open: %v", err)
+	}
+	err = client.forwardOpen(msg)
+	if err != nil {
+		t.Fatalf("problem with forward open: %v", err)
+	}
+	want := ConnectionInfo{Size: 4002, Large: true, TransportClass: 3, OTRPI: time.Millisecond * 2500, TORPI: time.Second * 3,
+		SessionHandle: 0x42, OTConnectionID: 0x1234, TOConnectionID: 0x5678, ConnectionSerial: 0x9A}
+	if have := client.ConnectionInfo(); have != want {
+		t.Errorf("wanted %+v got %+v", want, have)
+	}
+	if client.OTNetworkConnectionID != 0x1234 {
+		t.Errorf("wanted connection id 0x1234 got %#x", client.OTNetworkConnectionID)
+	}
+	if client.OTConnectionID() != 0x1234 || client.TOConnectionID() != 0x5678 {
+		t.Errorf("wanted connection ids 0x1234 and 0x5678. got %#x and %#x", client.OTConnectionID(), client.TOConnectionID())
+	}
+}
+
+func TestConnectTimeout(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	if client.connectTimeout() != socketTimeoutDefault {
+		t.Errorf("wanted the socket timeout by default. got %v", client.connectTimeout())
+	}
+	client.ConnectTimeout = time.Second
+	if client.connectTimeout() != time.Second {
+		t.Errorf("wanted ConnectTimeout to win. got %v", client.connectTimeout())
+	}
+	if !client.NoDelay {
+		t.Errorf("NoDelay should be on by default")
+	}
+}
+
+func TestConnectLocalAddr(t *testing.T) {
+	srv, _ := newTestServer(t, map[string]any{})
+
+	client := NewClient("127.0.0.1")
+	client.Controller.Port = uint(srv.TCPAddr().(*net.TCPAddr).Port)
+	client.ConnectionKeepAlive = false
+	client.LocalAddr = &net.IPAddr{IP: net.IPv4(127, 0, 0, 2)}
+	err := client.Connect()
+	if err != nil {
+		t.Skipf("can't connect from 127.0.0.2 here: %v", err)
+	}
+	defer client.Disconnect()
+	local := client.conn.LocalAddr().(*net.TCPAddr)
+	if !local.IP.Equal(net.IPv4(127, 0, 0, 2)) {
+		t.Errorf("wanted to connect from 127.0.0.2. got %v", local)
+	}
+
+	_, err = tcpLocalAddr(&net.UnixAddr{Name: "nope", Net: "unix"})
+	if err == nil {
+		t.Errorf("a unix address shouldn't work as a tcp local address")
+	}
+}
+
+// the whole session runs over an in-memory pipe handed to the client by Dial.
+func TestConnectDial(t *testing.T) {
+	srv, _ := newTestServer(t, map[string]any{"count": int32(12)})
+
+	client := NewClient("192.0.2.1")
+	client.ConnectionKeepAlive = false
+	var address string
+	client.Dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		address = addr
+		local, remote := net.Pipe()
+		h := serverTCPHandler{conn: remote, server: srv}
+		go h.serve(srv)
+		return local, nil
+	}
+	err := client.Connect()
+	if err != nil {
+		t.Fatalf("problem connecting over the pipe: %v", err)
+	}
+	defer client.Disconnect()
+	if address != "192.0.2.1:44818" {
+		t.Errorf("wanted Dial to get the controller's address. got %q", address)
+	}
+
+	var count int32
+	err = client.Read("count", &count)
+	if err != nil {
+		t.Fatalf("problem reading over the pipe: %v", err)
+	}
+	if count != 12 {
+		t.Errorf("wanted 12 got %d", count)
+	}
+}
+
+func TestConnectDialError(t *testing.T) {
+	client := NewClient("192.0.2.1")
+	dialErr := errors.New("relay is down")
+	client.Dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, dialErr
+	}
+	err := client.Connect()
+	if !errors.Is(err, dialErr) {
+		t.Errorf("wanted the Dial error. got %v", err)
+	}
+	if client.Connected() {
+		t.Errorf("shouldn't be connected")
+	}
+}
+
+func TestForwardOpenOriginator(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.VendorId = 0x1337
+	client.SerialNumber = 0xDEADBEEF
+	client.ForwardOpenConfig.ConnectionSerial = 0x4242
+
+	item, err := client.newForwardOpenLarge()
+	if err != nil {
+		t.Fatalf("problem building large forward open: %v", err)
+	}
+	var large cipForwardOpen[uint32]
+	err = item.DeSerialize(&large)
+	if err != nil {
+		t.Fatalf("problem reading large forward open: %v", err)
+	}
+	if large.VendorID != 0x1337 || large.OriginatorSerialNumber != 0xDEADBEEF || large.ConnectionSerialNumber != 0x4242 {
+		t.Errorf("large forward open has vendor %#x serial %#x connection serial %#x", large.VendorID, large.OriginatorSerialNumber, large.ConnectionSerialNumber)
+	}
+
+	item, err = client.newForwardOpenStandard()
+	if err != nil {
+		t.Fatalf("problem building standard forward open: %v", err)
+	}
+	var standard cipForwardOpen[uint16]
+	err = item.DeSerialize(&standard)
+	if err != nil {
+		t.Fatalf("problem reading standard forward open: %v", err)
+	}
+	if standard.VendorID != 0x1337 || standard.OriginatorSerialNumber != 0xDEADBEEF || standard.ConnectionSerialNumber != 0x4242 {
+		t.Errorf("standard forward open has vendor %#x serial %#x connection serial %#x", standard.VendorID, standard.OriginatorSerialNumber, standard.ConnectionSerialNumber)
+	}
+
+	// without a fixed one every forward open gets its own.
+	client.ForwardOpenConfig.ConnectionSerial = 0
+	client.newForwardOpenLarge()
+	first := client.ConnectionSerialNumber
+	client.newForwardOpenLarge()
+	if client.ConnectionSerialNumber == first {
+		t.Errorf("wanted a new connection serial for each forward open. got %#x twice", first)
+	}
+}
+
+func TestConnectPicksSerialNumber(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{})
+	defer client.Disconnect()
+	if client.SerialNumber == 0 {
+		t.Errorf("connect should pick a serial number")
+	}
+	if client.VendorId != vendorIdDefault {
+		t.Errorf("wanted the default vendor. got %#x", client.VendorId)
+	}
+}
@@ -0,0 +1,215 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Position of the key switch on the front of the controller.
+type KeySwitchPosition byte
+
+const (
+	KeySwitchUnknown KeySwitchPosition = iota
+	KeySwitchRun
+	KeySwitchProgram
+	KeySwitchRemote
+)
+
+func (k KeySwitchPosition) String() string {
+	switch k {
+	case KeySwitchRun:
+		return "Run"
+	case KeySwitchProgram:
+		return "Program"
+	case KeySwitchRemote:
+		return "Remote"
+	default:
+		return "Unknown"
+	}
+}
+
+// ControllerInfo is the identity of the controller from its Identity object.
+type ControllerInfo struct {
+	Vendor        VendorId
+	DeviceType    DeviceType
+	ProductCode   uint16
+	RevisionMajor byte
+	RevisionMinor byte
+	Status        uint16 // the raw identity status word
+	SerialNumber  uint32
+	Name          string
+
+	// these come out of the status word for logix controllers.  Other devices use those bits for other things.
+	KeySwitch KeySwitchPosition
+	Running   bool // true in run or remote run.  false in program, remote program, or faulted.
+}
+
+// Read the identity of the controller: who made it, what it is, its firmware revision, and its serial number and name.
+//
+// This is a GetAttributesAll of the Identity object (class 0x01, instance 1).  The firmware major revision is also
+// remembered for picking how to build tag paths so this saves a request later.
+func (client *Client) GetControllerInfo() (ControllerInfo, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return ControllerInfo{}, fmt.Errorf("could not get controller info: %w", err)
+	}
+	path, err := Serialize(CipObject_Identity, CIPInstance(1))
+	if err != nil {
+		return ControllerInfo{}, fmt.Errorf("could not build path. %w", err)
+	}
+	item, err := client.GenericCIPMessage(CIPService_GetAttributeAll, path.Bytes(), nil)
+	if err != nil {
+		return ControllerInfo{}, fmt.Errorf("problem reading identity object: %w", err)
+	}
+	info, err := parseControllerInfo(item)
+	if err != nil {
+		return info, err
+	}
+	client.knownFirmware = int(info.RevisionMajor)
+	return info, nil
+}
+
+// the identity object's attributes in the order GetAttributesAll returns them.  The name is a short string after this.
+type msgIdentityAttributes struct {
+	Vendor        VendorId
+	DeviceType    DeviceType
+	ProductCode   uint16
+	RevisionMajor byte
+	RevisionMinor byte
+	Status        uint16
+	SerialNumber  uint32
+}
+
+// parse the GetAttributesAll reply data of the identity object.
+func parseControllerInfo(item *CIPItem) (ControllerInfo, error) {
+	var attrs msgIdentityAttributes
+	err := item.DeSerialize(&attrs)
+	if err != nil {
+		return ControllerInfo{}, fmt.Errorf("problem reading identity attributes. %w", err)
+	}
+	info := ControllerInfo{
+		Vendor:        attrs.Vendor,
+		DeviceType:    attrs.DeviceType,
+		ProductCode:   attrs.ProductCode,
+		RevisionMajor: attrs.RevisionMajor,
+		RevisionMinor: attrs.RevisionMinor,
+		Status:        attrs.Status,
+		SerialNumber:  attrs.SerialNumber,
+	}
+	name_len, err := item.Byte()
+	if err != nil {
+		return info, fmt.Errorf("problem reading product name length. %w", err)
+	}
+	name := make([]byte, name_len)
+	err = item.DeSerialize(&name)
+	if err != nil {
+		return info, fmt.Errorf("problem reading product name. %w", err)
+	}
+	info.Name = string(name)
+
+	// bits 4-7 are 6 in run mode and 7 in program mode.  bits 12-13 are the key switch.
+	info.Running = (attrs.Status>>4)&0x0F == 6
+	info.KeySwitch = KeySwitchPosition((attrs.Status >> 12) & 0x03)
+	return info, nil
+}
+
+// The operating mode of a logix controller from bits 4-7 of its identity status word.
+type ControllerMode byte
+
+const (
+	ControllerModeUnknown ControllerMode = iota
+	ControllerModeRun
+	ControllerModeProgram
+	ControllerModeFaulted
+	ControllerModeFirmwareUpdate
+)
+
+func (m ControllerMode) String() string {
+	switch m {
+	case ControllerModeRun:
+		return "Run"
+	case ControllerModeProgram:
+		return "Program"
+	case ControllerModeFaulted:
+		return "Faulted"
+	case ControllerModeFirmwareUpdate:
+		return "FirmwareUpdate"
+	default:
+		return "Unknown"
+	}
+}
+
+// ControllerStatus is the state of the controller from the status word of its Identity object.
+//
+// The fault bits are the ones CIP defines for every device.  The mode and key switch are what logix controllers put in
+// the vendor specific bits.  There's no bit for test mode.  A controller in remote test holds its outputs the way it does
+// in program mode and reports the same mode bits, so it reads as program mode.
+type ControllerStatus struct {
+	Status uint16 // the raw identity status word
+
+	Mode      ControllerMode
+	KeySwitch KeySwitchPosition
+
+	Owned      bool // something has a connection that owns the controller's I/O
+	Configured bool // the controller has been downloaded to
+
+	MinorRecoverableFault   bool
+	MinorUnrecoverableFault bool
+	MajorRecoverableFault   bool
+	MajorUnrecoverableFault bool
+}
+
+// Whether the controller is in run or remote run.
+func (s ControllerStatus) Running() bool {
+	return s.Mode == ControllerModeRun
+}
+
+// Whether the controller has a major fault.  A faulted controller isn't running.
+func (s ControllerStatus) MajorFault() bool {
+	return s.Mode == ControllerModeFaulted || s.MajorRecoverableFault || s.MajorUnrecoverableFault
+}
+
+// Whether the controller has a minor fault.  Minor faults don't stop the controller.
+func (s ControllerStatus) MinorFault() bool {
+	return s.MinorRecoverableFault || s.MinorUnrecoverableFault
+}
+
+// Read the run/program mode, key switch, and fault bits of the controller.  This is a single small request for
+// attribute 5 of the Identity object so it is cheap enough to poll for alarming when the controller faults.
+func (client *Client) GetControllerStatus() (ControllerStatus, error) {
+	dat, err := client.GenericCIP(CIPService_GetAttributeSingle, CipObject_Identity, 1, 5, nil)
+	if err != nil {
+		return ControllerStatus{}, fmt.Errorf("problem reading identity status: %w", err)
+	}
+	if len(dat) < 2 {
+		return ControllerStatus{}, fmt.Errorf("identity status should be 2 bytes. got %d", len(dat))
+	}
+	return parseControllerStatus(binary.LittleEndian.Uint16(dat)), nil
+}
+
+// split the identity status word of a logix controller into its bits.  See CIP volume 1 table 5-2.3.
+func parseControllerStatus(status uint16) ControllerStatus {
+	s := ControllerStatus{
+		Status:    status,
+		KeySwitch: KeySwitchPosition((status >> 12) & 0x03),
+
+		Owned:      status&0x0001 != 0,
+		Configured: status&0x0004 != 0,
+
+		MinorRecoverableFault:   status&0x0100 != 0,
+		MinorUnrecoverableFault: status&0x0200 != 0,
+		MajorRecoverableFault:   status&0x0400 != 0,
+		MajorUnrecoverableFault: status&0x0800 != 0,
+	}
+	switch (status >> 4) & 0x0F {
+	case 1:
+		s.Mode = ControllerModeFirmwareUpdate
+	case 5:
+		s.Mode = ControllerModeFaulted
+	case 6:
+		s.Mode = ControllerModeRun
+	case 7:
+		s.Mode = ControllerModeProgram
+	}
+	return s
+}
@@ -0,0 +1,120 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestParseControllerInfo(t *testing.T) {
+	item := CIPItem{}
+	item.Serialize(msgIdentityAttributes{
+		Vendor:        1,
+		DeviceType:    0x0E,
+		ProductCode:   166,
+		RevisionMajor: 32,
+		RevisionMinor: 11,
+		Status:        0x3060, // remote, run mode
+		SerialNumber:  0x00C0FFEE,
+	})
+	name := "1756-L83E/B"
+	item.Serialize(byte(len(name)))
+	item.Serialize([]byte(name))
+
+	info, err := parseControllerInfo(&item)
+	if err != nil {
+		t.Fatalf("problem parsing: %v", err)
+	}
+	if info.Vendor != 1 || info.DeviceType != 0x0E || info.ProductCode != 166 || info.SerialNumber != 0x00C0FFEE {
+		t.Errorf("wrong identity %+v", info)
+	}
+	if info.RevisionMajor != 32 || info.RevisionMinor != 11 {
+		t.Errorf("wanted revision 32.11 got %d.%d", info.RevisionMajor, info.RevisionMinor)
+	}
+	if info.Name != name {
+		t.Errorf("wanted name %q got %q", name, info.Name)
+	}
+	if info.KeySwitch != KeySwitchRemote || !info.Running {
+		t.Errorf("wanted remote run got %v running=%v", info.KeySwitch, info.Running)
+	}
+
+	// program mode with the key in program
+	binary.LittleEndian.PutUint16(item.Data[8:], 0x2070)
+	item.Reset()
+	info, err = parseControllerInfo(&item)
+	if err != nil {
+		t.Fatalf("problem parsing: %v", err)
+	}
+	if info.KeySwitch != KeySwitchProgram || info.Running {
+		t.Errorf("wanted program got %v running=%v", info.KeySwitch, info.Running)
+	}
+
+	// a truncated name is an error
+	item.Data = item.Data[:len(item.Data)-2]
+	item.Reset()
+	_, err = parseControllerInfo(&item)
+	if err == nil {
+		t.Errorf("expected an error for a short name")
+	}
+}
+
+func TestParseControllerStatus(t *testing.T) {
+	var tests = []struct {
+		status  uint16
+		mode    ControllerMode
+		key     KeySwitchPosition
+		running bool
+		major   bool
+		minor   bool
+	}{
+		{0x3065, ControllerModeRun, KeySwitchRemote, true, false, false},
+		{0x2074, ControllerModeProgram, KeySwitchProgram, false, false, false},
+		{0x1160, ControllerModeRun, KeySwitchRun, true, false, true}, // running with a minor fault
+		{0x3454, ControllerModeFaulted, KeySwitchRemote, false, true, false},
+		{0x3804, ControllerModeUnknown, KeySwitchRemote, false, true, false}, // an unrecoverable major fault on its own
+		{0x0010, ControllerModeFirmwareUpdate, KeySwitchUnknown, false, false, false},
+		{0x3074, ControllerModeProgram, KeySwitchRemote, false, false, false}, // remote test looks like remote program
+	}
+	for _, tt := range tests {
+		s := parseControllerStatus(tt.status)
+		if s.Mode != tt.mode || s.KeySwitch != tt.key {
+			t.Errorf("%#04x: wanted %v with the key in %v. got %v and %v", tt.status, tt.mode, tt.key, s.Mode, s.KeySwitch)
+		}
+		if s.Running() != tt.running || s.MajorFault() != tt.major || s.MinorFault() != tt.minor {
+			t.Errorf("%#04x: wanted running=%v major=%v minor=%v. got %+v", tt.status, tt.running, tt.major, tt.minor, s)
+		}
+	}
+	if s := parseControllerStatus(0x0005); !s.Owned || !s.Configured {
+		t.Errorf("wanted owned and configured. got %+v", s)
+	}
+}
+
+func TestGetControllerStatus(t *testing.T) {
+	client, remote := newPipeClient(t)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		req := buf.Bytes()[20:]
+		want := []byte{byte(CIPService_GetAttributeSingle), 0x03, 0x20, 0x01, 0x24, 0x01, 0x30, 0x05}
+		if !check_bytes(req[2:], want) {
+			t.Errorf("wanted %v got %v", to_hex(want), to_hex(req[2:]))
+		}
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(uint16(1))
+		item.Serialize([]byte{byte(CIPService_GetAttributeSingle.AsResponse()), 0})
+		item.Serialize(uint16(0))
+		item.Serialize(uint16(0x3454))
+		b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	s, err := client.GetControllerStatus()
+	if err != nil {
+		t.Fatalf("problem getting status: %v", err)
+	}
+	if !s.MajorFault() || s.Running() || s.Status != 0x3454 {
+		t.Errorf("wanted a major fault. got %+v", s)
+	}
+}
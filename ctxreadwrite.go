@@ -0,0 +1,98 @@
+package gologix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Read a tag, giving up when ctx is cancelled or its deadline passes.  The value comes back the way Read_single with
+// CIPTypeUnknown gives it.
+//
+// The deadline of ctx is used as the read timeout.  Without one the usual ReadTimeout applies.  If ctx fires while
+// the request is out the read returns right away with the context error.  The request itself is left to finish in the
+// background and its response is dropped when it shows up, so the session stays up and later requests get their own
+// responses.
+func (client *Client) ReadCtx(ctx context.Context, tag string) (any, error) {
+	timeout, err := ctxTimeout(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read of %s not started: %w", tag, err)
+	}
+	err = client.checkConnection()
+	if err != nil {
+		return nil, fmt.Errorf("could not start read: %w", err)
+	}
+	type result struct {
+		value any
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := client.read_single(tag, CIPTypeUnknown, 1, timeout)
+		done <- result{v, err}
+	}()
+	select {
+	case r := <-done:
+		if cerr := ctxFailure(ctx, timeout, r.err); cerr != nil {
+			return nil, fmt.Errorf("read of %s: %w: %w", tag, cerr, r.err)
+		}
+		return r.value, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("read of %s abandoned: %w", tag, ctx.Err())
+	}
+}
+
+// Write a value to a tag like Write, giving up when ctx is cancelled or its deadline passes.  See ReadCtx.
+//
+// When the context fires the write may or may not have happened, the same as for a write that times out.
+func (client *Client) WriteCtx(ctx context.Context, tag string, value any) error {
+	timeout, err := ctxTimeout(ctx)
+	if err != nil {
+		return fmt.Errorf("write of %s not started: %w", tag, err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- client.WriteWithTimeout(tag, value, timeout)
+	}()
+	select {
+	case err := <-done:
+		if cerr := ctxFailure(ctx, timeout, err); cerr != nil {
+			return fmt.Errorf("write of %s: %w: %w", tag, cerr, err)
+		}
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("write of %s abandoned: %w", tag, ctx.Err())
+	}
+}
+
+// the timeout for a request that has to finish before ctx's deadline.  0 if it has none so the usual timeout applies.
+func ctxTimeout(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, nil
+	}
+	d := time.Until(deadline)
+	if d <= 0 {
+		return 0, context.DeadlineExceeded
+	}
+	return d, nil
+}
+
+// the context error to go with err from a request that had timeout from ctxTimeout, or nil if ctx had nothing to do
+// with it.  The request's own timer can go off a moment before the context's so a timeout counts as the deadline.
+func ctxFailure(ctx context.Context, timeout time.Duration, err error) error {
+	if err == nil {
+		return nil
+	}
+	if cerr := ctx.Err(); cerr != nil {
+		return cerr
+	}
+	if timeout > 0 && errors.Is(err, ErrTimeout) {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
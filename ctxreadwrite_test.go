@@ -0,0 +1,66 @@
+package gologix
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// a cancelled read should come back right away and its late response shouldn't end up with the next read.
+func TestReadCtxCancel(t *testing.T) {
+	client, remote := newPipeClient(t)
+
+	go func() {
+		first, _, err := recvData(remote)
+		if err != nil {
+			return
+		}
+		second, _, err := recvData(remote)
+		if err != nil {
+			return
+		}
+		writeTestResponse(t, remote, first.Context, dintReadReply(t, 111))
+		writeTestResponse(t, remote, second.Context, dintReadReply(t, 222))
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*20, cancel)
+	start := time.Now()
+	_, err := client.ReadCtx(ctx, "Count")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("wanted a cancelled read. got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("cancel took %v", time.Since(start))
+	}
+
+	v, err := client.ReadCtx(context.Background(), "Count")
+	if err != nil || v != int32(222) {
+		t.Errorf("wanted 222 got %v (%v)", v, err)
+	}
+}
+
+func TestReadCtxDeadline(t *testing.T) {
+	client, remote := newPipeClient(t)
+	go func() {
+		// take the requests but never answer.
+		for {
+			_, _, err := recvData(remote)
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	_, err := client.ReadCtx(ctx, "Count")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wanted deadline exceeded. got %v", err)
+	}
+	err = client.WriteCtx(ctx, "Count", int32(1))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wanted deadline exceeded for a write after the deadline. got %v", err)
+	}
+}
@@ -1,18 +1,50 @@
 package gologix
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"time"
 )
 
 // You will want to defer this after a successful Connect() to make sure you free up the controller resources
-// to disconnect we send two items - a null item and an unconnected data item for the unregister service
+// to disconnect we send two items - a null item and an unconnected data item for the forward close service.  Then the
+// session is unregistered and the socket closed.
+//
+// If the controller refuses the forward close the socket is still closed and the refusal is returned as a *CIPError.
+//
+// This is the same as calling DisconnectContext with context.Background()
 func (client *Client) Disconnect() error {
+	return client.DisconnectContext(context.Background())
+}
+
+// Close the connection to the controller.  This is Disconnect but it is safe to defer right after creating the client:
+// calling it when Connect failed part way through, was never called, or Close already ran is fine.  Whatever is left of
+// the socket gets closed in every case.
+func (client *Client) Close() error {
+	err := client.Disconnect()
+	if client.conn != nil {
+		// Disconnect does nothing if Connect never finished but the socket might still be around.
+		// closing it again when Disconnect already did is harmless.
+		client.conn.Close()
+		client.waitPump()
+	}
+	return err
+}
+
+// Disconnect from the PLC, giving up on the ForwardClose exchange if ctx is cancelled or its deadline passes.
+//
+// The socket is always closed.  If the context fires before the controller answers, the context error is returned
+// wrapped.
+func (client *Client) DisconnectContext(ctx context.Context) error {
 	if client.connecting {
 		client.Logger.Debug("waiting for client to finish connecting before disconnecting")
 		for client.connecting {
-			time.Sleep(time.Millisecond * 10)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("gave up waiting for connect to finish: %w", ctx.Err())
+			case <-time.After(time.Millisecond * 10):
+			}
 		}
 	}
 	if !client.connected || client.disconnecting {
@@ -22,44 +54,25 @@ func (client *Client) Disconnect() error {
 	defer func() { client.disconnecting = false }()
 	client.connected = false
 	var err error
+	var closeErr error
 	client.Logger.Info("starting disconnection")
 
 	if client.keepAliveRunning {
 		close(client.cancel_keepalive)
 	}
+	client.stopHeartbeat()
 
 	items := make([]CIPItem, 2)
 	items[0] = CIPItem{} // null item
-	items[1] = CIPItem{Header: cipItemHeader{ID: cipItem_UnconnectedData}}
-
-	path, err := Serialize(
-		client.Controller.Path,
-		CipObject_MessageRouter,
-		CIPInstance(1),
-	)
+	items[1], err = client.newForwardClose()
 	if err != nil {
 		client.Logger.Error("Error serializing path", slog.Any("err", err))
-		return fmt.Errorf("error serializing path: %w", err)
+		return err
 	}
 
-	msg := msgCipUnRegister{
-		Service:                CIPService_ForwardClose,
-		CipPathSize:            0x02,
-		ClassType:              cipClass_8bit,
-		Class:                  0x06,
-		InstanceType:           cipInstance_8bit,
-		Instance:               0x01,
-		Priority:               0x0A,
-		TimeoutTicks:           0x0E,
-		ConnectionSerialNumber: client.ConnectionSerialNumber,
-		VendorID:               client.VendorId,
-		OriginatorSerialNumber: client.SerialNumber,
-		PathSize:               byte(path.Len() / 2),
-		Reserved:               0x00,
-	}
-
-	items[1].Serialize(msg)
-	items[1].Serialize(path)
+	// closing the socket is the only way to unblock a pending read if the context fires.
+	conn := client.conn
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
 
 	itemData, err := serializeItems(items)
 	if err != nil {
@@ -81,19 +94,82 @@ func (client *Client) Disconnect() error {
 					"error parsing disconnect response",
 					slog.Any("err", err),
 				)
+				closeErr = fmt.Errorf("forward close refused: %w", err)
 			}
 		}
 	}
+	client.unregisterSession()
 
-	err = client.conn.Close()
+	if !stop() {
+		client.Logger.Warn("disconnect aborted before the controller answered", slog.Any("err", ctx.Err()))
+		conn.Close()
+		client.waitPump()
+		return fmt.Errorf("disconnect aborted: %w", ctx.Err())
+	}
+
+	err = conn.Close()
 	if err != nil {
 		client.Logger.Error("error closing connection", slog.Any("err", err))
 	}
+	// anybody still waiting on a response gets an error once the pump sees the socket close.
+	client.waitPump()
 
+	if closeErr != nil {
+		return closeErr
+	}
 	client.Logger.Info("successfully disconnected from controller")
 	return nil
 }
 
+// the unconnected data item of a forward close for the current cip connection.
+func (client *Client) newForwardClose() (CIPItem, error) {
+	item := CIPItem{Header: cipItemHeader{ID: cipItem_UnconnectedData}}
+	path, err := Serialize(
+		client.Controller.Path,
+		CipObject_MessageRouter,
+		CIPInstance(1),
+	)
+	if err != nil {
+		return item, fmt.Errorf("error serializing path: %w", err)
+	}
+
+	tick, ticks := client.unconnectedTicks()
+	msg := msgCipUnRegister{
+		Service:                CIPService_ForwardClose,
+		CipPathSize:            0x02,
+		ClassType:              cipClass_8bit,
+		Class:                  0x06,
+		InstanceType:           cipInstance_8bit,
+		Instance:               0x01,
+		Priority:               tick,
+		TimeoutTicks:           ticks,
+		ConnectionSerialNumber: client.ConnectionSerialNumber,
+		VendorID:               client.VendorId,
+		OriginatorSerialNumber: client.SerialNumber,
+		PathSize:               byte(path.Len() / 2),
+		Reserved:               0x00,
+	}
+
+	item.Serialize(msg)
+	item.Serialize(path)
+	return item, nil
+}
+
+// tell the controller we're done with the session.  There is no reply to this.  The controller just closes the socket.
+func (client *Client) unregisterSession() {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	buf := getSendBuffer()
+	defer putSendBuffer(buf)
+	err := client.sendMsgBuild(buf, cipCommandUnRegisterSession)
+	if err == nil {
+		err = client.sendData(buf.Bytes())
+	}
+	if err != nil {
+		client.Logger.Warn("problem unregistering session", slog.Any("err", err))
+	}
+}
+
 // Cancels keepalive if KeepAliveAutoStart is false. Use force to cancel keepalive regardless.
 // If forced, the keepalive will not resume unless the client is reconnected or KeepAlive is triggered
 func (client *Client) KeepAliveCancel(force bool) error {
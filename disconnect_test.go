@@ -0,0 +1,79 @@
+package gologix
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// the controller refuses the forward close.  The session should still be unregistered and the socket closed, and the
+// refusal should come back from Disconnect.
+func TestDisconnectForwardCloseRefused(t *testing.T) {
+	client, remote := newPipeClient(t)
+
+	commands := make(chan CIPCommand, 2)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading forward close: %v", err)
+			return
+		}
+		commands <- hdr.Command
+		// interface handle and timeout, items header, null item, unconnected data item header
+		if service := CIPService(buf.Bytes()[16]); service != CIPService_ForwardClose {
+			t.Errorf("wanted a forward close. got %v", service)
+		}
+
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_UnconnectedData}}
+		item.Serialize(msgCIPMessageRouterResponse{Service: CIPService_ForwardClose.AsResponse(), Status: 0x01, StatusLen: 1})
+		item.Serialize(uint16(0x0107)) // connection not found
+		items, err := serializeItems([]CIPItem{{}, item})
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *items)
+
+		hdr, _, err = recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading unregister session: %v", err)
+			return
+		}
+		commands <- hdr.Command
+	}()
+
+	done := make(chan error)
+	go func() { done <- client.Disconnect() }()
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("timed out waiting for Disconnect")
+	}
+	var cerr *CIPError
+	if !errors.As(err, &cerr) || cerr.General != 0x01 || cerr.extended() != 0x0107 {
+		t.Errorf("wanted the forward close refusal. got %v", err)
+	}
+	if have := <-commands; have != cipCommandSendRRData {
+		t.Errorf("wanted the forward close first. got %v", have)
+	}
+	if have := <-commands; have != cipCommandUnRegisterSession {
+		t.Errorf("wanted unregister session after the forward close. got %v", have)
+	}
+	if client.Connected() {
+		t.Errorf("client should not be connected")
+	}
+}
+
+// Close should be fine to call on a client that never connected and to call more than once.
+func TestCloseIdempotent(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	err := client.Close()
+	if err != nil {
+		t.Errorf("close of a client that never connected: %v", err)
+	}
+	err = client.Close()
+	if err != nil {
+		t.Errorf("second close: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// Identity is what a device says about itself in reply to a ListIdentity.
+type Identity struct {
+	IP            net.IP // the address the reply came from
+	Vendor        VendorId
+	DeviceType    DeviceType
+	ProductCode   uint16
+	RevisionMajor byte
+	RevisionMinor byte
+	Status        uint16
+	SerialNumber  uint32
+	ProductName   string
+	State         uint8
+}
+
+func newIdentity(ip net.IP, body listIdentityResponeBody) Identity {
+	return Identity{
+		IP:            ip,
+		Vendor:        body.Vendor,
+		DeviceType:    body.DeviceType,
+		ProductCode:   body.ProductCode,
+		RevisionMajor: byte(body.Revision),
+		RevisionMinor: byte(body.Revision >> 8),
+		Status:        body.Status,
+		SerialNumber:  body.SerialNumber,
+		ProductName:   body.ProductName,
+		State:         body.State,
+	}
+}
+
+// Find EtherNet/IP devices on the local subnet.
+//
+// A ListIdentity is broadcast on udp port 44818 and every reply that comes back within timeout is collected.  Devices
+// are listed once each in the order they answered even if they answer more than once or on more than one interface.
+func Discover(timeout time.Duration) ([]Identity, error) {
+	return DiscoverFrom(nil, timeout)
+}
+
+// Find EtherNet/IP devices like Discover but send from the given local address, for picking the network card on a PC
+// with more than one.  Only the IP of local is used.  nil is the same as Discover.
+//
+// The broadcast goes to the subnet of the interface that has the address so it leaves through that card.
+func DiscoverFrom(local net.Addr, timeout time.Duration) ([]Identity, error) {
+	bind := ":0"
+	dest := &net.UDPAddr{IP: net.IPv4bcast, Port: portDefault}
+	if local != nil {
+		ip := addrIP(local)
+		if ip == nil || ip.To4() == nil {
+			return nil, fmt.Errorf("can't discover from local address %v", local)
+		}
+		bind = net.JoinHostPort(ip.String(), "0")
+		dest.IP = subnetBroadcast(ip)
+	}
+	conn, err := net.ListenPacket("udp4", bind)
+	if err != nil {
+		return nil, fmt.Errorf("problem opening udp socket: %w", err)
+	}
+	defer conn.Close()
+	return discover(conn, dest, timeout)
+}
+
+// the broadcast address of the subnet ip is on, or the limited broadcast address if no local interface has ip.
+func subnetBroadcast(ip net.IP) net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return net.IPv4bcast
+	}
+	for _, a := range addrs {
+		n, ok := a.(*net.IPNet)
+		if !ok || !n.IP.Equal(ip) {
+			continue
+		}
+		ip4 := n.IP.To4()
+		mask := n.Mask
+		if ip4 == nil || len(mask) != net.IPv4len {
+			continue
+		}
+		bcast := make(net.IP, net.IPv4len)
+		for i := range bcast {
+			bcast[i] = ip4[i] | ^mask[i]
+		}
+		return bcast
+	}
+	return net.IPv4bcast
+}
+
+// send a ListIdentity to dest on conn and collect the replies until timeout passes.
+func discover(conn net.PacketConn, dest net.Addr, timeout time.Duration) ([]Identity, error) {
+	req := bytes.Buffer{}
+	err := binary.Write(&req, binary.LittleEndian, eipHeader{Command: cipCommandListIdentity})
+	if err != nil {
+		return nil, fmt.Errorf("problem building list identity request: %w", err)
+	}
+	_, err = conn.WriteTo(req.Bytes(), dest)
+	if err != nil {
+		return nil, fmt.Errorf("problem sending list identity to %v: %w", dest, err)
+	}
+
+	err = conn.SetReadDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return nil, fmt.Errorf("problem setting read deadline: %w", err)
+	}
+
+	results := make([]Identity, 0)
+	seen := make(map[string]bool)
+	buf := make([]byte, 4096)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				return results, nil
+			}
+			return results, fmt.Errorf("problem reading list identity replies: %w", err)
+		}
+		ip := addrIP(addr)
+		body, err := parseListIdentityReply(buf[:n])
+		if err != nil {
+			// something else talking on the port.  skip it.
+			continue
+		}
+		if ip == nil {
+			ip = identityIP(body.SocketAddress)
+		}
+		if seen[ip.String()] {
+			continue
+		}
+		seen[ip.String()] = true
+		results = append(results, newIdentity(ip, body))
+	}
+}
+
+// parse a whole ListIdentity reply packet including the encapsulation header.
+func parseListIdentityReply(dat []byte) (listIdentityResponeBody, error) {
+	var body listIdentityResponeBody
+	r := bytes.NewReader(dat)
+	var hdr eipHeader
+	err := binary.Read(r, binary.LittleEndian, &hdr)
+	if err != nil {
+		return body, fmt.Errorf("problem reading header: %w", err)
+	}
+	if hdr.Command != cipCommandListIdentity {
+		return body, fmt.Errorf("expected a list identity reply. got command %v", hdr.Command)
+	}
+	items, err := readItems(r)
+	if err != nil {
+		return body, fmt.Errorf("couldn't parse items. %w", err)
+	}
+	if len(items) != 1 {
+		return body, fmt.Errorf("expected 1 item, got %d", len(items))
+	}
+	err = body.ParseFromBytes(items[0].Data)
+	if err != nil {
+		return body, err
+	}
+	return body, nil
+}
+
+// the ip address a reply came from.
+func addrIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return a.IP
+	case *net.TCPAddr:
+		return a.IP
+	case *net.IPAddr:
+		return a.IP
+	case nil:
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
+
+// the ip address the device reports for itself.
+func identityIP(s listIdentitySocketAddress) net.IP {
+	return net.IPv4(byte(s.Address>>24), byte(s.Address>>16), byte(s.Address>>8), byte(s.Address))
+}
@@ -0,0 +1,91 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// the sample reply from TestParseListIdentityResponse wrapped up in a header and item list.
+func listIdentityReplyPacket() []byte {
+	itemBytes := []byte{1, 0, 0, 2, 175, 18, 192, 168, 1, 65, 0, 0, 0, 0, 0, 0, 0, 0, 21, 5, 12, 0, 1, 0, 1, 2, 52, 0, 3, 232, 34, 23, 20, 73, 81, 32, 83, 101, 110, 115, 111, 114, 32, 78, 101, 116, 32, 83, 121, 115, 116, 101, 109, 3}
+	b := bytes.Buffer{}
+	binary.Write(&b, binary.LittleEndian, eipHeader{Command: cipCommandListIdentity, Length: uint16(2 + 4 + len(itemBytes))})
+	binary.Write(&b, binary.LittleEndian, uint16(1))
+	binary.Write(&b, binary.LittleEndian, cipItemHeader{ID: 0x0C, Length: uint16(len(itemBytes))})
+	b.Write(itemBytes)
+	return b.Bytes()
+}
+
+func TestDiscover(t *testing.T) {
+	device, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("problem opening fake device: %v", err)
+	}
+	defer device.Close()
+
+	go func() {
+		buf := make([]byte, 1024)
+		n, addr, err := device.ReadFrom(buf)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		var hdr eipHeader
+		binary.Read(bytes.NewReader(buf[:n]), binary.LittleEndian, &hdr)
+		if hdr.Command != cipCommandListIdentity {
+			t.Errorf("expected a list identity. got %v", hdr.Command)
+		}
+		// junk should be skipped and the duplicate reply dropped.
+		device.WriteTo([]byte{1, 2, 3}, addr)
+		device.WriteTo(listIdentityReplyPacket(), addr)
+		device.WriteTo(listIdentityReplyPacket(), addr)
+	}()
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("problem opening socket: %v", err)
+	}
+	defer conn.Close()
+	found, err := discover(conn, device.LocalAddr(), time.Millisecond*200)
+	if err != nil {
+		t.Fatalf("problem discovering: %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("wanted 1 device got %+v", found)
+	}
+	id := found[0]
+	if !id.IP.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("wanted the sender's address. got %v", id.IP)
+	}
+	if id.Vendor != 0x0515 || id.ProductName != "IQ Sensor Net System" || id.SerialNumber != 0x1722E803 {
+		t.Errorf("wrong identity %+v", id)
+	}
+	if id.RevisionMajor != 1 || id.RevisionMinor != 2 {
+		t.Errorf("wanted revision 1.2 got %d.%d", id.RevisionMajor, id.RevisionMinor)
+	}
+}
+
+func TestIdentityIP(t *testing.T) {
+	ip := identityIP(listIdentitySocketAddress{Address: 3232235841})
+	if !ip.Equal(net.IPv4(192, 168, 1, 65)) {
+		t.Errorf("wanted 192.168.1.65 got %v", ip)
+	}
+}
+
+func TestSubnetBroadcast(t *testing.T) {
+	b := subnetBroadcast(net.IPv4(127, 0, 0, 1))
+	if !b.Equal(net.IPv4(127, 255, 255, 255)) {
+		t.Errorf("wanted the loopback subnet's broadcast. got %v", b)
+	}
+	b = subnetBroadcast(net.IPv4(203, 0, 113, 7))
+	if !b.Equal(net.IPv4bcast) {
+		t.Errorf("an address no interface has should use the limited broadcast. got %v", b)
+	}
+	_, err := DiscoverFrom(&net.IPAddr{IP: net.ParseIP("::1")}, time.Millisecond)
+	if err == nil {
+		t.Errorf("discovery from an ipv6 address should fail")
+	}
+}
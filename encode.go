@@ -0,0 +1,117 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Build the read request for one element of tag without sending it.  Nothing needs to be connected.
+//
+// The bytes are the CIP request the controller's message router gets: the read service, the path size in words, the
+// path, and the element count.  On the wire they go in the connected data item after the sequence count.  The tag is
+// always addressed by name here even if it is in KnownTags.
+func (client *Client) EncodeRead(tag string) ([]byte, error) {
+	ioi, err := client.newIOI(tag, CIPTypeUnknown)
+	if err != nil {
+		return nil, fmt.Errorf("problem generating IOI. %w", err)
+	}
+	b := bytes.Buffer{}
+	err = binary.Write(&b, binary.LittleEndian, msgCIPMultiIOIHeader{Service: CIPService_Read, Size: byte(len(ioi.Buffer) / 2)})
+	if err != nil {
+		return nil, fmt.Errorf("problem writing read header to buffer. %w", err)
+	}
+	b.Write(ioi.Buffer)
+	err = binary.Write(&b, binary.LittleEndian, msgCIPIOIFooter{Elements: 1})
+	if err != nil {
+		return nil, fmt.Errorf("problem writing element count to buffer. %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// Build the write request for value to tag without sending it.  Nothing needs to be connected.  value can be anything
+// Write takes except for a bool written to a bit of an integer, which goes out as a read-modify-write.
+//
+// The bytes are the CIP request the same as for EncodeRead: the write service, the path size in words, the path, the
+// type, the element count, and the data.  Values too big for one message would be sent in fragments but are encoded
+// here as one request.
+func (client *Client) EncodeWrite(tag string, value any) ([]byte, error) {
+	ioi, err := client.newIOI(tag, CIPTypeUnknown)
+	if err != nil {
+		return nil, fmt.Errorf("problem generating IOI. %w", err)
+	}
+	if ioi.BitAccess {
+		return nil, fmt.Errorf("can't encode a write to bit %d of %s. bit writes are a read-modify-write", ioi.BitPosition, tag)
+	}
+	return client.encodeTagWrite(TagWrite{Tag: tag, Value: value})
+}
+
+// Encode v as the data of a tag of type t the way Write sends it: little endian with no type or element count in front.
+// It is the write side of reading a value and gives the library's encoding for building GenericCIP request data.
+//
+// v has to fit t without being cut off or wrapping around and anything else is an error.  Unlike Validate, go numbers
+// of another type are converted so an int 5 is encoded as the 4 bytes of a DINT.  A STRING is the LEN DINT followed by
+// the 82 bytes of DATA and padding, 88 bytes in all.  A slice or array is encoded element by element.  Structures
+// aren't handled here.  Use Pack for those.
+func EncodeValue(t CIPType, v any) ([]byte, error) {
+	if t == CIPTypeStruct {
+		return nil, fmt.Errorf("can't encode a structure without its layout. use Pack")
+	}
+	err := t.fits(v)
+	if err != nil {
+		return nil, err
+	}
+	return appendValue(nil, t, reflect.ValueOf(v))
+}
+
+// add the encoding of rv as a t to b.  rv has already passed t.fits.
+func appendValue(b []byte, t CIPType, rv reflect.Value) ([]byte, error) {
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		var err error
+		for i := 0; i < rv.Len(); i++ {
+			b, err = appendValue(b, t, rv.Index(i))
+			if err != nil {
+				return b, fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		return b, nil
+	}
+
+	switch t {
+	case CIPTypeBOOL:
+		if rv.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case CIPTypeSTRING:
+		buf := bytes.NewBuffer(b)
+		_, err := logixString{Value: rv.String()}.Pack(buf)
+		return buf.Bytes(), err
+	case CIPTypeREAL:
+		f, _ := floatValue(rv)
+		return binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(f))), nil
+	case CIPTypeLREAL:
+		f, _ := floatValue(rv)
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(f)), nil
+	}
+
+	var u uint64
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		u = uint64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u = rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); f < 0 {
+			u = uint64(int64(f))
+		} else {
+			u = uint64(f)
+		}
+	default:
+		return b, fmt.Errorf("can't encode a %v as a %v", rv.Type(), t)
+	}
+	// the low bytes of the two's complement are the value at any size.
+	return append(b, binary.LittleEndian.AppendUint64(nil, u)[:t.Size()]...), nil
+}
@@ -0,0 +1,117 @@
+package gologix
+
+import "testing"
+
+func TestEncodeRead(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	var tests = []struct {
+		tag  string
+		want []byte
+	}{
+		{"Count", []byte{0x4C, 0x04, 0x91, 0x05, 'c', 'o', 'u', 'n', 't', 0x00, 0x01, 0x00}},
+		{"Arr[1,2,300]", []byte{0x4C, 0x07, 0x91, 0x03, 'a', 'r', 'r', 0x00, 0x28, 0x01, 0x28, 0x02, 0x29, 0x00, 0x2C, 0x01, 0x01, 0x00}},
+		{"Tank.Level", []byte{0x4C, 0x07, 0x91, 0x04, 't', 'a', 'n', 'k', 0x91, 0x05, 'l', 'e', 'v', 'e', 'l', 0x00, 0x01, 0x00}},
+		// bits are read as the whole integer
+		{"Flags.3", []byte{0x4C, 0x04, 0x91, 0x05, 'f', 'l', 'a', 'g', 's', 0x00, 0x01, 0x00}},
+	}
+	for _, tt := range tests {
+		have, err := client.EncodeRead(tt.tag)
+		if err != nil {
+			t.Errorf("%s: %v", tt.tag, err)
+			continue
+		}
+		if !check_bytes(have, tt.want) {
+			t.Errorf("%s:\nwanted %v\ngot    %v", tt.tag, to_hex(tt.want), to_hex(have))
+		}
+	}
+
+	_, err := client.EncodeRead("Arr[1,2,3,4]")
+	if err == nil {
+		t.Errorf("four dimensions should be an error")
+	}
+}
+
+func TestEncodeWrite(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	have, err := client.EncodeWrite("Count", int32(-2))
+	if err != nil {
+		t.Fatalf("problem encoding: %v", err)
+	}
+	want := []byte{0x4D, 0x04, 0x91, 0x05, 'c', 'o', 'u', 'n', 't', 0x00, 0xC4, 0x00, 0x01, 0x00, 0xFE, 0xFF, 0xFF, 0xFF}
+	if !check_bytes(have, want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(have))
+	}
+
+	have, err = client.EncodeWrite("Arr[2]", []int16{1, 2})
+	if err != nil {
+		t.Fatalf("problem encoding: %v", err)
+	}
+	want = []byte{0x4D, 0x04, 0x91, 0x03, 'a', 'r', 'r', 0x00, 0x28, 0x02, 0xC3, 0x00, 0x02, 0x00, 0x01, 0x00, 0x02, 0x00}
+	if !check_bytes(have, want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(have))
+	}
+
+	_, err = client.EncodeWrite("Flags.3", true)
+	if err == nil {
+		t.Errorf("a bit write should be an error")
+	}
+}
+
+func TestEncodeValue(t *testing.T) {
+	var tests = []struct {
+		typ  CIPType
+		v    any
+		want []byte
+	}{
+		{CIPTypeDINT, int32(-2), []byte{0xFE, 0xFF, 0xFF, 0xFF}},
+		{CIPTypeDINT, 5, []byte{0x05, 0x00, 0x00, 0x00}},
+		{CIPTypeINT, uint8(0x80), []byte{0x80, 0x00}},
+		{CIPTypeSINT, -1, []byte{0xFF}},
+		{CIPTypeUDINT, 3.0, []byte{0x03, 0x00, 0x00, 0x00}},
+		{CIPTypeLINT, int64(-3), []byte{0xFD, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+		{CIPTypeREAL, float32(1.5), []byte{0x00, 0x00, 0xC0, 0x3F}},
+		{CIPTypeREAL, 2, []byte{0x00, 0x00, 0x00, 0x40}},
+		{CIPTypeLREAL, 1.5, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF8, 0x3F}},
+		{CIPTypeBOOL, true, []byte{0x01}},
+		{CIPTypeINT, []int{1, -1}, []byte{0x01, 0x00, 0xFF, 0xFF}},
+	}
+	for _, tt := range tests {
+		have, err := EncodeValue(tt.typ, tt.v)
+		if err != nil {
+			t.Errorf("%v %v: %v", tt.typ, tt.v, err)
+			continue
+		}
+		if !check_bytes(have, tt.want) {
+			t.Errorf("%v %v:\nwanted %v\ngot    %v", tt.typ, tt.v, to_hex(tt.want), to_hex(have))
+		}
+	}
+
+	have, err := EncodeValue(CIPTypeSTRING, "hi")
+	if err != nil {
+		t.Fatalf("problem encoding string: %v", err)
+	}
+	if len(have) != 88 || !check_bytes(have[:6], []byte{0x02, 0x00, 0x00, 0x00, 'h', 'i'}) {
+		t.Errorf("wrong string encoding %v", to_hex(have))
+	}
+	s, err := parseLogixString(have)
+	if err != nil || s != "hi" {
+		t.Errorf("string didn't come back. got %q %v", s, err)
+	}
+
+	for _, bad := range []struct {
+		typ CIPType
+		v   any
+	}{
+		{CIPTypeDINT, "12"},
+		{CIPTypeSINT, 300},
+		{CIPTypeBOOL, 1},
+		{CIPTypeSTRING, 12},
+		{CIPTypeStruct, struct{ A int32 }{}},
+		{CIPTypeDINT, nil},
+	} {
+		_, err := EncodeValue(bad.typ, bad.v)
+		if err == nil {
+			t.Errorf("encoding %v as %v should be an error", bad.v, bad.typ)
+		}
+	}
+}
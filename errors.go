@@ -1,22 +1,136 @@
 package gologix
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
 )
 
-// Represents a CIP error.
+// Returned when the controller doesn't respond to a request within the read or write timeout.
+// The session is still usable afterwards.
+var ErrTimeout = errors.New("timed out waiting for response")
+
+// Returned when a program scoped tag names a program that isn't in KnownPrograms.  The tag itself may or may not exist.
+var ErrProgramNotFound = errors.New("program not found")
+
+// Returned by WriteVerify when the tag reads back with something other than what was written.
+var ErrVerifyMismatch = errors.New("tag didn't read back as written")
+
+// Write gives this instead of sending anything when KnownTags says the tag's external access doesn't allow writes.
+var ErrReadOnly = errors.New("tag is read only")
+
+// Why the controller refused a ForwardOpen, from the extended status of its connection failure.  The error a
+// refused connect returns wraps one of these when the reason is known and the *CIPError with the raw status either
+// way so check with errors.Is.
+var (
+	ErrConnectionInUse          = errors.New("connection in use or duplicate forward open")
+	ErrTransportNotSupported    = errors.New("transport class and trigger combination not supported")
+	ErrOwnershipConflict        = errors.New("ownership conflict")
+	ErrInvalidConnectionSize    = errors.New("invalid connection size")
+	ErrRPINotSupported          = errors.New("rpi not supported")
+	ErrOutOfConnections         = errors.New("out of connections")
+	ErrConnectionRequestTimeout = errors.New("connection request timed out")
+	ErrBadConnectionPath        = errors.New("bad segment or port in the connection path")
+)
+
+// the named error for the extended status of a connection failure (general status 0x01).  See CIP volume 1 table
+// 3-5.29.
+var connectionFailureErrors = map[uint16]error{
+	0x0100: ErrConnectionInUse,
+	0x0103: ErrTransportNotSupported,
+	0x0106: ErrOwnershipConflict,
+	0x0109: ErrInvalidConnectionSize,
+	0x0111: ErrRPINotSupported,
+	0x0113: ErrOutOfConnections,
+	0x011A: ErrOutOfConnections,
+	0x0203: ErrConnectionRequestTimeout,
+	0x0204: ErrConnectionRequestTimeout,
+	0x0311: ErrBadConnectionPath,
+	0x0312: ErrBadConnectionPath,
+	0x0315: ErrBadConnectionPath,
+}
+
+// the named error for why a connection failed or nil if err isn't a connection failure with an extended status we
+// know.
+func connectionFailureReason(err *CIPError) error {
+	if CIPStatus(err.General) != CIPStatus_ConnectionFailure {
+		return nil
+	}
+	return connectionFailureErrors[err.extended()]
+}
+
+// a connection failure that matches its named reason with errors.Is as well as the CIPError.  The message is the
+// CIPError's since that already says the reason.
+type connectionFailure struct {
+	reason error
+	cerr   *CIPError
+}
+
+func (err *connectionFailure) Error() string {
+	return err.cerr.Error()
+}
+
+func (err *connectionFailure) Unwrap() []error {
+	return []error{err.reason, err.cerr}
+}
+
+// Returned by reads of a REAL or LREAL that is NaN or ±Inf when Client.TreatFloatSpecialsAsError is set.
+var ErrFloatSpecial = errors.New("float value is NaN or Inf")
+
+// check a value read from tag for NaN or ±Inf.  v can be a float32, float64, or a []any of values.
+func checkFloatSpecial(tag string, v any) error {
+	switch x := v.(type) {
+	case float32:
+		f := float64(x)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("tag %s read as %v: %w", tag, x, ErrFloatSpecial)
+		}
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return fmt.Errorf("tag %s read as %v: %w", tag, x, ErrFloatSpecial)
+		}
+	case []any:
+		for i := range x {
+			err := checkFloatSpecial(fmt.Sprintf("%s element %d", tag, i), x[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Represents a CIP error from the status of a reply.
+//
+// General is the general status and Extended holds the extended status words if the reply had any.  Use errors.As
+// to get at it or one of the Is... helpers to check for common faults.
 type CIPError struct {
-	Code     byte
-	Extended uint16
+	General  byte
+	Extended []uint16
+}
+
+// the first extended status word or 0 if there isn't one.
+func (err *CIPError) extended() uint16 {
+	if len(err.Extended) == 0 {
+		return 0
+	}
+	return err.Extended[0]
 }
 
 func (err *CIPError) Error() string {
-	ec := fmt.Sprintf("error %X%X: ", err.Code, err.Extended)
-	switch err.Code {
+	ec := fmt.Sprintf("error %02X", err.General)
+	for _, x := range err.Extended {
+		ec += fmt.Sprintf(":%04X", x)
+	}
+	ec += ":"
+	switch err.General {
 	case 0x00:
 		return ec + " no error?  This shouldn't happen :/"
 	case 0x01:
+		if reason := connectionFailureReason(err); reason != nil {
+			return ec + " connection failure: " + reason.Error()
+		}
 		return ec + " connection failure"
 	case 0x02:
 		return ec + " resource unavailable"
@@ -37,14 +151,20 @@ func (err *CIPError) Error() string {
 	case 0x0A:
 		return ec + " attribute list error, generally attribute not supported. the status of the unsupported attribute is 0x14"
 	case 0x10:
-		switch err.Extended {
+		switch err.extended() {
 		case 0x2101:
 			return ec + " device state conflict: keyswitch position: the requestor is changing force information in HARD RUN mode"
 		case 0x2802:
 			return ec + " device state conflict: safety status: the controller is in a state in which safety memory cannot be modified"
 		}
+	case 0x0F:
+		return ec + " privilege violation"
+	case 0x11:
+		return ec + " reply data too large"
 	case 0x13:
 		return ec + " insufficient Request Data: Data too short for expected param"
+	case 0x15:
+		return ec + " too much data"
 	case 0x16:
 		return ec + " object does not exist"
 	case 0x1A:
@@ -56,17 +176,72 @@ func (err *CIPError) Error() string {
 	case 0x26:
 		return ec + " the request path size received was shorter or longer than expected."
 	case 0xFF:
-		switch err.Extended {
+		switch err.extended() {
 		case 0x2104:
-			return ec + "General Error: Offset is beyond end of the requested tag."
+			return ec + " General Error: Offset is beyond end of the requested tag."
 		case 0x2105:
-			return ec + "General Error: Number of Elements or Byte Offset is beyond the end of the requested tag."
+			return ec + " General Error: Number of Elements or Byte Offset is beyond the end of the requested tag."
 		case 0x2107:
-			return ec + "General Error: Tag type used n request does not match the target tag's data type."
+			return ec + " General Error: Tag type used n request does not match the target tag's data type."
 		}
 	}
 
-	return ec + "Unknown Error"
+	return ec + " " + CIPStatus(err.General).String()
+}
+
+// read the CIP status that starts at the beginning of dat: the general status, the number of extended status words, and
+// the extended status words.  n is the number of bytes the status took up.
+func parseCIPStatus(dat []byte) (err *CIPError, n int) {
+	if len(dat) < 2 {
+		return &CIPError{General: byte(CIPStatus_NotEnoughData)}, len(dat)
+	}
+	err = &CIPError{General: dat[0]}
+	n = 2
+	for i := 0; i < int(dat[1]) && n+2 <= len(dat); i++ {
+		err.Extended = append(err.Extended, binary.LittleEndian.Uint16(dat[n:]))
+		n += 2
+	}
+	return err, n
+}
+
+// the general status of err if it is (or wraps) a *CIPError
+func cipGeneral(err error) (CIPStatus, bool) {
+	var cerr *CIPError
+	if !errors.As(err, &cerr) {
+		return 0, false
+	}
+	return CIPStatus(cerr.General), true
+}
+
+// Whether err is the controller saying the tag (or a member or element of it) doesn't exist.
+func IsTagNotFound(err error) bool {
+	status, ok := cipGeneral(err)
+	return ok && (status == CIPStatus_PathSegmentError || status == CIPStatus_PathDestinationUnknown)
+}
+
+// Whether err is the controller refusing access, such as writing a read only tag.
+func IsPrivilegeViolation(err error) bool {
+	status, ok := cipGeneral(err)
+	return ok && status == CIPStatus_PrivilegeViolation
+}
+
+// Whether err is the controller saying the request or reply is too big.
+func IsTooMuchData(err error) bool {
+	status, ok := cipGeneral(err)
+	return ok && (status == CIPStatus_TooMuchData ||
+		status == CIPStatus_ReplyDataTooLarge ||
+		status == CIPStatus_RoutingFailureReqTooLarge ||
+		status == CIPStatus_RoutingFailureRespTooLarge)
+}
+
+// Whether retrying the request that gave err could work.  This is true for timeouts and for statuses where the
+// controller was busy or the connection dropped.
+func IsTransient(err error) bool {
+	if errors.Is(err, ErrTimeout) {
+		return true
+	}
+	status, ok := cipGeneral(err)
+	return ok && (status == CIPStatus_ResourceUnavailable || status == CIPStatus_ConnectionLost)
 }
 
 func newMultiError(err error) multiError {
@@ -0,0 +1,81 @@
+package gologix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCIPErrorPredicates(t *testing.T) {
+	var tests = []struct {
+		err         error
+		notFound    bool
+		privilege   bool
+		tooMuch     bool
+		transient   bool
+		messagePart string
+	}{
+		{&CIPError{General: 0x04}, true, false, false, false, "error 04:"},
+		{fmt.Errorf("problem reading x: %w", &CIPError{General: 0x05}), true, false, false, false, "destination unknown"},
+		{&CIPError{General: 0x0F}, false, true, false, false, "privilege"},
+		{&CIPError{General: 0x15}, false, false, true, false, "too much data"},
+		{&CIPError{General: 0x1B}, false, false, true, false, "response too large"},
+		{&CIPError{General: 0x02}, false, false, false, true, "resource unavailable"},
+		{fmt.Errorf("wrapped: %w", ErrTimeout), false, false, false, true, "timed out"},
+		{&CIPError{General: 0xFF, Extended: []uint16{0x2107}}, false, false, false, false, "error FF:2107:"},
+		{&CIPError{General: 0xFF, Extended: []uint16{0x2105, 0x0001}}, false, false, false, false, "FF:2105:0001"},
+		{errors.New("not a cip error"), false, false, false, false, "not a cip error"},
+	}
+
+	for _, tt := range tests {
+		if IsTagNotFound(tt.err) != tt.notFound {
+			t.Errorf("%v: IsTagNotFound should be %v", tt.err, tt.notFound)
+		}
+		if IsPrivilegeViolation(tt.err) != tt.privilege {
+			t.Errorf("%v: IsPrivilegeViolation should be %v", tt.err, tt.privilege)
+		}
+		if IsTooMuchData(tt.err) != tt.tooMuch {
+			t.Errorf("%v: IsTooMuchData should be %v", tt.err, tt.tooMuch)
+		}
+		if IsTransient(tt.err) != tt.transient {
+			t.Errorf("%v: IsTransient should be %v", tt.err, tt.transient)
+		}
+		if !strings.Contains(tt.err.Error(), tt.messagePart) {
+			t.Errorf("%v: message should contain %q", tt.err, tt.messagePart)
+		}
+	}
+}
+
+func TestParseCIPStatus(t *testing.T) {
+	cerr, n := parseCIPStatus([]byte{0xFF, 0x01, 0x07, 0x21, 0xAA})
+	if n != 4 || cerr.General != 0xFF || len(cerr.Extended) != 1 || cerr.Extended[0] != 0x2107 {
+		t.Errorf("wanted FF:2107 in 4 bytes. got %+v in %d", cerr, n)
+	}
+
+	cerr, n = parseCIPStatus([]byte{0x05, 0x00})
+	if n != 2 || cerr.General != 0x05 || len(cerr.Extended) != 0 {
+		t.Errorf("wanted 05 in 2 bytes. got %+v in %d", cerr, n)
+	}
+
+	// an extended size past the end of the data shouldn't panic
+	cerr, _ = parseCIPStatus([]byte{0x04, 0x03, 0x01})
+	if cerr.General != 0x04 || len(cerr.Extended) != 0 {
+		t.Errorf("wanted 04 with no extended status. got %+v", cerr)
+	}
+}
+
+func TestWriteReplyError(t *testing.T) {
+	item := CIPItem{}
+	item.Serialize(msgWriteResultHeader{Service: CIPService_Write.AsResponse(), Status: 0xFF, StatusExtended: 1})
+	item.Serialize(uint16(extStatusTypeMismatch))
+	var hdr msgWriteResultHeader
+	err := item.DeSerialize(&hdr)
+	if err != nil {
+		t.Fatalf("problem reading header: %v", err)
+	}
+	cerr := writeReplyError(hdr, &item)
+	if cerr.General != 0xFF || len(cerr.Extended) != 1 || cerr.Extended[0] != extStatusTypeMismatch {
+		t.Errorf("wanted FF:2107. got %+v", cerr)
+	}
+}
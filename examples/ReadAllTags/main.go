@@ -60,14 +60,6 @@ func main() {
 		tag := client.KnownTags[tagname]
 		log.Printf("%s: %v", tag.Name, tag.Info.Type)
 
-		// Handle array tags by accessing first element
-		qty := uint16(1)
-		if tag.Info.Dimension1 != 0 {
-			tagname = tagname + "[0]"
-			x := tag.Info.Atomic()
-			qty = uint16(tag.Info.Dimension1)
-			_ = x
-		}
 		if tag.UDT == nil && !tag.Info.Atomic() {
 			//log.Print("Not Atomic or UDT")
 			continue
@@ -76,8 +68,8 @@ func main() {
 			log.Printf("%s size = %d", tag.Name, tag.UDT.Size())
 		}
 
-		// Read and display the value of each tag
-		val, err := client.Read_single(tagname, tag.Info.Type, qty)
+		// Read and display the value of each tag.  A count of 0 reads every element of array tags.
+		val, err := client.Read_single(tagname, tag.Info.Type, 0)
 		if err != nil {
 			log.Printf("Error!  Problem reading tag %s. %v", tagname, err)
 			continue
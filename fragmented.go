@@ -0,0 +1,209 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// read a tag that is too big for one reply with the fragmented read service (0x52).
+//
+// Every fragment asks for the data starting at the byte offset after everything received so far.  The controller
+// answers with a partial transfer status until the last fragment, which comes back with a success status.  The
+// result is one item laid out the same as the reply to a normal read would be if it could hold all the data.
+func (client *Client) readFragmented(ioi *tagIOI, elements uint16, timeout time.Duration) (CIPItem, error) {
+	return assembleFragments(func(offset uint32) (CIPItem, error) {
+		return client.readFragment(ioi, elements, offset, timeout)
+	})
+}
+
+// send one fragmented read request and return the reply item.
+func (client *Client) readFragment(ioi *tagIOI, elements uint16, offset uint32, timeout time.Duration) (CIPItem, error) {
+	reqItems := make([]CIPItem, 2)
+	reqItems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
+
+	readMsg := msgCIPConnectedServiceReq{
+		SequenceCount: uint16(sequencer()),
+		Service:       CIPService_FragRead,
+		PathLength:    byte(len(ioi.Bytes()) / 2),
+	}
+	reqItems[1] = newItem(cipItem_ConnectedData, readMsg)
+	reqItems[1].Serialize(ioi.Bytes())
+	reqItems[1].Serialize(elements)
+	reqItems[1].Serialize(offset)
+
+	itemData, err := serializeItems(reqItems)
+	if err != nil {
+		return CIPItem{}, err
+	}
+	_, data, err := client.send_recv_data_timeout(client.readTimeout(timeout), cipCommandSendUnitData, itemData)
+	if err != nil {
+		return CIPItem{}, err
+	}
+
+	read_result_header := msgCIPResultHeader{}
+	err = binary.Read(data, binary.LittleEndian, &read_result_header)
+	if err != nil {
+		return CIPItem{}, fmt.Errorf("problem reading read result header. %w", err)
+	}
+	items, err := readItems(data)
+	if err != nil {
+		return CIPItem{}, fmt.Errorf("problem reading items. %w", err)
+	}
+	if len(items) != 2 {
+		return CIPItem{}, fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(items))
+	}
+	return items[1], nil
+}
+
+// keep fetching fragments at increasing offsets until one comes back with a success status and put them together.
+func assembleFragments(fetch func(offset uint32) (CIPItem, error)) (CIPItem, error) {
+	var first msgCIPReadResultData
+	var handle uint16
+	payload := bytes.Buffer{}
+
+	for {
+		offset := uint32(payload.Len())
+		item, err := fetch(offset)
+		if err != nil {
+			return CIPItem{}, fmt.Errorf("problem reading fragment at offset %d: %w", offset, err)
+		}
+		var hdr msgCIPReadResultData
+		err = item.DeSerialize(&hdr)
+		if err != nil {
+			return CIPItem{}, fmt.Errorf("problem reading fragment header at offset %d: %w", offset, err)
+		}
+		status := CIPStatus(hdr.Status[1])
+		if status != CIPStatus_OK && status != CIPStatus_PartialTransfer {
+			return CIPItem{}, fmt.Errorf("fragment at offset %d: %w", offset, readReplyError(hdr))
+		}
+		// structures have their handle in front of the data in every fragment.
+		if hdr.Type == CIPTypeStruct {
+			err = item.DeSerialize(&handle)
+			if err != nil {
+				return CIPItem{}, fmt.Errorf("problem reading structure handle at offset %d: %w", offset, err)
+			}
+		}
+		if offset == 0 {
+			first = hdr
+		}
+		chunk := item.Data[item.Pos:]
+		payload.Write(chunk)
+		if status == CIPStatus_OK {
+			break
+		}
+		if len(chunk) == 0 {
+			return CIPItem{}, fmt.Errorf("partial transfer at offset %d returned no data", offset)
+		}
+	}
+
+	first.Status = [3]byte{}
+	result := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+	result.Serialize(first)
+	if first.Type == CIPTypeStruct {
+		result.Serialize(handle)
+	}
+	result.Serialize(payload.Bytes())
+	return result, nil
+}
+
+// the error for a read reply with a bad status.  With an extended status, the status word takes the place of the type.
+func readReplyError(hdr msgCIPReadResultData) error {
+	err := &CIPError{General: hdr.Status[1]}
+	if hdr.Status[2] > 0 {
+		err.Extended = []uint16{uint16(hdr.Type) | uint16(hdr.Unknown)<<8}
+	}
+	return err
+}
+
+// whether a write of data to ioi is too big for one request on this connection.
+func (client *Client) needsFragmenting(ioi *tagIOI, typeInfo []byte, data []byte) bool {
+	// sequence, service, path size, path, type, element count, data
+	size := 2 + 1 + 1 + len(ioi.Buffer) + len(typeInfo) + 2 + len(data)
+	return size > int(client.ConnectionSize)
+}
+
+// write data that is too big for one request with the fragmented write service (0x53).
+//
+// typeInfo is the data type as it goes in a normal write request (the type, or 0xA0 0x02 and the structure handle for a
+// structure).  Every fragment carries the type, the total element count, and the byte offset of its piece of the data.
+// The pieces are as big as the connection allows while keeping elements of elemSize bytes whole.
+func (client *Client) writeFragmented(tag string, ioi *tagIOI, typeInfo []byte, elements uint16, data []byte, elemSize int, timeout time.Duration) error {
+	// sequence, service, path size, path, type, element count, offset
+	overhead := 2 + 1 + 1 + len(ioi.Buffer) + len(typeInfo) + 2 + 4
+	size := int(client.ConnectionSize) - overhead
+	if elemSize > 1 {
+		size -= size % elemSize
+	}
+	if size < 1 {
+		return fmt.Errorf("connection size %d is too small to write %s", client.ConnectionSize, tag)
+	}
+	return sendFragments(data, size, func(offset uint32, chunk []byte) error {
+		return client.writeFragment(ioi, typeInfo, elements, offset, chunk, timeout)
+	})
+}
+
+// split data into pieces of at most size bytes and send each one with its offset.
+func sendFragments(data []byte, size int, send func(offset uint32, chunk []byte) error) error {
+	for offset := 0; offset < len(data); offset += size {
+		end := min(offset+size, len(data))
+		err := send(uint32(offset), data[offset:end])
+		if err != nil {
+			return fmt.Errorf("problem writing fragment at offset %d: %w", offset, err)
+		}
+	}
+	return nil
+}
+
+// send one fragmented write request and check its reply.
+func (client *Client) writeFragment(ioi *tagIOI, typeInfo []byte, elements uint16, offset uint32, chunk []byte, timeout time.Duration) error {
+	ioi_header := msgCIPIOIHeader{
+		Sequence: uint16(sequencer()),
+		Service:  CIPService_FragWrite,
+		Size:     byte(len(ioi.Buffer) / 2),
+	}
+
+	reqitems := make([]CIPItem, 2)
+	reqitems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
+	reqitems[1] = CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+	reqitems[1].Serialize(ioi_header)
+	reqitems[1].Serialize(ioi.Buffer)
+	reqitems[1].Serialize(typeInfo)
+	reqitems[1].Serialize(elements)
+	reqitems[1].Serialize(offset)
+	reqitems[1].Serialize(chunk)
+
+	itemdata, err := serializeItems(reqitems)
+	if err != nil {
+		return err
+	}
+	hdr, data, err := client.send_recv_data_timeout(client.writeTimeout(timeout), cipCommandSendUnitData, itemdata)
+	if err != nil {
+		return err
+	}
+	if hdr.Status != 0 {
+		return fmt.Errorf("got non-success status %d when writing", hdr.Status)
+	}
+	read_result_header := msgCIPResultHeader{}
+	err = binary.Read(data, binary.LittleEndian, &read_result_header)
+	if err != nil {
+		return fmt.Errorf("problem reading write result header. %w", err)
+	}
+	items, err := readItems(data)
+	if err != nil {
+		return fmt.Errorf("problem reading items from write, %w", err)
+	}
+	if len(items) != 2 {
+		return fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(items))
+	}
+	var hdr2 msgWriteResultHeader
+	err = items[1].DeSerialize(&hdr2)
+	if err != nil {
+		return fmt.Errorf("problem deserializing write response header, %w", err)
+	}
+	if hdr2.Status != CIPStatus_OK {
+		return writeReplyError(hdr2, &items[1])
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// build a fragmented read reply holding dat.
+func fragmentReply(status CIPStatus, typ CIPType, handle uint16, dat []byte) CIPItem {
+	item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+	item.Serialize(msgCIPReadResultData{Service: CIPService_FragRead.AsResponse(), Status: [3]byte{0, byte(status), 0}, Type: typ})
+	if typ == CIPTypeStruct {
+		item.Serialize(handle)
+	}
+	item.Serialize(dat)
+	return item
+}
+
+// a DINT[5000] is 20000 bytes.  The "controller" sends it back 480 bytes at a time.
+func TestAssembleFragmentsDINT5000(t *testing.T) {
+	const elements = 5000
+	const fragment = 480
+	dat := make([]byte, elements*4)
+	for i := 0; i < elements; i++ {
+		binary.LittleEndian.PutUint32(dat[i*4:], uint32(i*3))
+	}
+
+	requests := 0
+	fetch := func(offset uint32) (CIPItem, error) {
+		if offset%fragment != 0 {
+			t.Errorf("offset %d doesn't line up with the bytes received", offset)
+		}
+		requests++
+		end := min(int(offset)+fragment, len(dat))
+		status := CIPStatus_PartialTransfer
+		if end == len(dat) {
+			status = CIPStatus_OK
+		}
+		return fragmentReply(status, CIPTypeDINT, 0, dat[offset:end]), nil
+	}
+
+	item, err := assembleFragments(fetch)
+	if err != nil {
+		t.Fatalf("problem assembling: %v", err)
+	}
+	if want := (len(dat) + fragment - 1) / fragment; requests != want {
+		t.Errorf("wanted %d requests got %d", want, requests)
+	}
+
+	var hdr msgCIPReadResultData
+	err = item.DeSerialize(&hdr)
+	if err != nil {
+		t.Fatalf("problem reading header: %v", err)
+	}
+	if hdr.Type != CIPTypeDINT || hdr.Status[1] != 0 {
+		t.Errorf("bad assembled header %+v", hdr)
+	}
+	for i := 0; i < elements; i++ {
+		v, err := readValue(hdr.Type, &item)
+		if err != nil {
+			t.Fatalf("element %d: %v", i, err)
+		}
+		if v != int32(i*3) {
+			t.Fatalf("element %d: wanted %d got %v", i, i*3, v)
+		}
+	}
+}
+
+// structures repeat their handle in every fragment but it should only be in the result once.
+func TestAssembleFragmentsStruct(t *testing.T) {
+	dat := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	fetch := func(offset uint32) (CIPItem, error) {
+		end := min(int(offset)+4, len(dat))
+		status := CIPStatus_PartialTransfer
+		if end == len(dat) {
+			status = CIPStatus_OK
+		}
+		return fragmentReply(status, CIPTypeStruct, 0x1234, dat[offset:end]), nil
+	}
+	item, err := assembleFragments(fetch)
+	if err != nil {
+		t.Fatalf("problem assembling: %v", err)
+	}
+	want := fragmentReply(CIPStatus_OK, CIPTypeStruct, 0x1234, dat)
+	if !check_bytes(item.Data, want.Data) {
+		t.Errorf("wanted %v\ngot    %v", want.Data, item.Data)
+	}
+}
+
+func TestAssembleFragmentsErrors(t *testing.T) {
+	_, err := assembleFragments(func(offset uint32) (CIPItem, error) {
+		return fragmentReply(CIPStatus_PathDestinationUnknown, CIPTypeDINT, 0, nil), nil
+	})
+	if err == nil {
+		t.Errorf("an error status should be an error")
+	}
+	_, err = assembleFragments(func(offset uint32) (CIPItem, error) {
+		return fragmentReply(CIPStatus_PartialTransfer, CIPTypeDINT, 0, nil), nil
+	})
+	if err == nil {
+		t.Errorf("an empty partial transfer should be an error instead of looping forever")
+	}
+}
+
+// a REAL[2000] is 8000 bytes which takes a bunch of fragments on a standard size connection.
+func TestSendFragmentsREAL2000(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.ConnectionSize = 504
+	ioi, err := client.newIOI("bigreals", CIPTypeREAL)
+	if err != nil {
+		t.Fatalf("problem building ioi: %v", err)
+	}
+
+	dat := make([]byte, 2000*4)
+	for i := range dat {
+		dat[i] = byte(i)
+	}
+	typeInfo := []byte{byte(CIPTypeREAL), 0}
+	if !client.needsFragmenting(ioi, typeInfo, dat) {
+		t.Fatalf("8000 bytes should need fragmenting on a 504 byte connection")
+	}
+	if client.needsFragmenting(ioi, typeInfo, dat[:400]) {
+		t.Errorf("400 bytes shouldn't need fragmenting on a 504 byte connection")
+	}
+
+	overhead := 2 + 1 + 1 + len(ioi.Buffer) + len(typeInfo) + 2 + 4
+	size := client.ConnectionSize - uint16(overhead)
+	size -= size % 4
+
+	have := make([]byte, 0, len(dat))
+	err = sendFragments(dat, int(size), func(offset uint32, chunk []byte) error {
+		if int(offset) != len(have) {
+			t.Errorf("offset %d doesn't follow the %d bytes already sent", offset, len(have))
+		}
+		if len(chunk)%4 != 0 {
+			t.Errorf("fragment at %d splits a REAL. length %d", offset, len(chunk))
+		}
+		if overhead+len(chunk) > int(client.ConnectionSize) {
+			t.Errorf("fragment at %d is too big for the connection", offset)
+		}
+		have = append(have, chunk...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("problem sending: %v", err)
+	}
+	if !check_bytes(have, dat) {
+		t.Errorf("fragments don't put back together to the original data")
+	}
+}
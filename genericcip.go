@@ -0,0 +1,256 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Send any service to any attribute of any CIP object over the cip connection and get the reply data back.
+//
+// The path is built from class, instance, and attribute.  An attribute of 0 leaves the attribute out of the path for
+// services that work on a whole instance (like GetAttributesAll) or on the class (use instance 0).  The returned bytes
+// are the reply data after the status.  A bad status comes back as a wrapped *CIPError with its extended status
+// words along with any data the reply had.
+//
+// Example: read the MAC address of the ethernet port (Ethernet Link object, instance 1, attribute 3):
+//
+//	mac, err := client.GenericCIP(gologix.CIPService_GetAttributeSingle, gologix.CipObject_EthernetLink, 1, 3, nil)
+func (client *Client) GenericCIP(service CIPService, class CIPClass, instance CIPInstance, attribute CIPAttribute, data []byte) ([]byte, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return nil, fmt.Errorf("could not start generic cip message: %w", err)
+	}
+	path, err := cipPath(class, instance, attribute)
+	if err != nil {
+		return nil, err
+	}
+	item, err := client.GenericCIPMessage(service, path, data)
+	if item == nil {
+		return nil, err
+	}
+	return item.Data[item.Pos:], err
+}
+
+// Same as GenericCIP but sent as an unconnected message.
+//
+// The message goes to the device at the client's IP address itself (usually the ethernet module) instead of down the
+// connection path to the controller.  That's where objects like TCP/IP Interface and Ethernet Link live.
+func (client *Client) GenericCIPUnconnected(service CIPService, class CIPClass, instance CIPInstance, attribute CIPAttribute, data []byte) ([]byte, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return nil, fmt.Errorf("could not start generic cip message: %w", err)
+	}
+	path, err := cipPath(class, instance, attribute)
+	if err != nil {
+		return nil, err
+	}
+	return client.sendUnconnected(service, embeddedMessage(service, path, data))
+}
+
+// Same as GenericCIP but sent as an unconnected message through the Unconnected Send service of the connection
+// manager.
+//
+// The connection manager passes the message along Controller.Path so it ends up at the controller the same as a
+// connected message would, but it doesn't go over the cip connection.  Some older controllers and bridges handle
+// browsing and object reads better this way.
+func (client *Client) GenericCIPUnconnectedSend(service CIPService, class CIPClass, instance CIPInstance, attribute CIPAttribute, data []byte) ([]byte, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return nil, fmt.Errorf("could not start generic cip message: %w", err)
+	}
+	path, err := cipPath(class, instance, attribute)
+	if err != nil {
+		return nil, err
+	}
+	route := []byte{}
+	if client.Controller.Path != nil {
+		route = client.Controller.Path.Bytes()
+	}
+	tick, ticks := client.unconnectedTicks()
+	return client.sendUnconnected(service, unconnectedSendMessage(embeddedMessage(service, path, data), route, tick, ticks))
+}
+
+// the priority/tick time and timeout ticks for an Unconnected Send.  See Client.UnconnectedPriorityTick.
+func (client *Client) unconnectedTicks() (byte, byte) {
+	tick, ticks := client.UnconnectedPriorityTick, client.UnconnectedTimeoutTicks
+	if tick == 0 {
+		tick = 0x0A
+	}
+	if ticks == 0 {
+		ticks = 0x0E
+	}
+	return tick, ticks
+}
+
+// CIPMessaging is how GenericCIPWith sends a message.
+type CIPMessaging byte
+
+const (
+	// SendUnitData over the cip connection.  The same as GenericCIP.
+	CIPMessagingConnected CIPMessaging = iota
+	// SendRRData to the device at the client's IP address.  The same as GenericCIPUnconnected.
+	CIPMessagingUnconnected
+	// SendRRData wrapped in an Unconnected Send along Controller.Path.  The same as GenericCIPUnconnectedSend.
+	CIPMessagingUnconnectedSend
+)
+
+// Send a generic cip message the way how says.  See GenericCIP, GenericCIPUnconnected, and GenericCIPUnconnectedSend.
+func (client *Client) GenericCIPWith(how CIPMessaging, service CIPService, class CIPClass, instance CIPInstance, attribute CIPAttribute, data []byte) ([]byte, error) {
+	switch how {
+	case CIPMessagingConnected:
+		return client.GenericCIP(service, class, instance, attribute, data)
+	case CIPMessagingUnconnected:
+		return client.GenericCIPUnconnected(service, class, instance, attribute, data)
+	case CIPMessagingUnconnectedSend:
+		return client.GenericCIPUnconnectedSend(service, class, instance, attribute, data)
+	}
+	return nil, fmt.Errorf("unknown cip messaging %d", how)
+}
+
+// the service, path, and data of a message request.
+func embeddedMessage(service CIPService, path []byte, data []byte) []byte {
+	msg := make([]byte, 0, 2+len(path)+len(data))
+	msg = append(msg, byte(service), byte(len(path)/2))
+	msg = append(msg, path...)
+	return append(msg, data...)
+}
+
+// wrap a message request in an Unconnected Send to the connection manager that passes it along route.  tick and
+// ticks are the priority/tick time and timeout ticks.
+func unconnectedSendMessage(embedded []byte, route []byte, tick byte, ticks byte) []byte {
+	msg := []byte{byte(CIPService_UnconnectedSend), 0x02, 0x20, byte(CipObject_ConnectionManager), 0x24, 0x01}
+	msg = append(msg, tick, ticks)
+	msg = binary.LittleEndian.AppendUint16(msg, uint16(len(embedded)))
+	msg = append(msg, embedded...)
+	if len(embedded)%2 == 1 {
+		msg = append(msg, 0x00)
+	}
+	msg = append(msg, byte(len(route)/2), 0x00)
+	return append(msg, route...)
+}
+
+// send a message request as unconnected data and get the data out of the reply.
+func (client *Client) sendUnconnected(service CIPService, msg []byte) ([]byte, error) {
+	reqitems := make([]CIPItem, 2)
+	reqitems[0] = CIPItem{Header: cipItemHeader{ID: cipItem_Null}}
+	reqitems[1] = CIPItem{Header: cipItemHeader{ID: cipItem_UnconnectedData}}
+	reqitems[1].Serialize(msg)
+
+	itemdata, err := serializeItems(reqitems)
+	if err != nil {
+		return nil, err
+	}
+	hdr, reply, err := client.send_recv_data(cipCommandSendRRData, itemdata)
+	if err != nil {
+		return nil, err
+	}
+	if hdr.Status != 0 {
+		return nil, fmt.Errorf("got non-success status %d for service 0x%X", hdr.Status, service)
+	}
+	return parseUnconnectedReply(service, reply)
+}
+
+// pull the reply data out of the reply to an unconnected message.
+func parseUnconnectedReply(service CIPService, reply *bytes.Buffer) ([]byte, error) {
+	var pre msgPreItemData
+	err := binary.Read(reply, binary.LittleEndian, &pre)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading items header: %w", err)
+	}
+	items, err := readItems(reply)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading items: %w", err)
+	}
+	if len(items) != 2 {
+		return nil, fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(items))
+	}
+	// service, reserved, general status, extended status size, extended status, then the data
+	dat := items[1].Data
+	if len(dat) < 4 {
+		return nil, fmt.Errorf("reply to service 0x%X too short. got %d bytes", service, len(dat))
+	}
+	if service != CIPService_UnconnectedSend && CIPService(dat[0]) == CIPService_UnconnectedSend.AsResponse() {
+		// the connection manager couldn't pass the message along so it answered for it.
+		cerr, _ := parseCIPStatus(dat[2:])
+		return nil, fmt.Errorf("unconnected send of service 0x%X failed: %w", service, cerr)
+	}
+	if CIPService(dat[0]).UnResponse() != service {
+		return nil, fmt.Errorf("expected service response 0x%X but got 0x%X", service, CIPService(dat[0]).UnResponse())
+	}
+	cerr, n := parseCIPStatus(dat[2:])
+	if cerr.General != 0 {
+		return nil, fmt.Errorf("service 0x%X failed: %w", service, cerr)
+	}
+	return dat[2+n:], nil
+}
+
+// the request path to an attribute of an object.  An attribute of 0 is left out.
+func cipPath(class CIPClass, instance CIPInstance, attribute CIPAttribute) ([]byte, error) {
+	parts := []any{class, instance}
+	if attribute != 0 {
+		parts = append(parts, attribute)
+	}
+	path, err := Serialize(parts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not build path. %w", err)
+	}
+	return path.Bytes(), nil
+}
+
+// Write one attribute of an object over the cip connection.  data is the attribute's value already encoded the way the
+// object expects it.
+func (client *Client) SetAttrSingle(class CIPClass, instance CIPInstance, attribute CIPAttribute, data []byte) error {
+	_, err := client.GenericCIP(CIPService_SetAttributeSingle, class, instance, attribute, data)
+	return err
+}
+
+// AttributeValue is one attribute for SetAttrList along with its encoded value.
+type AttributeValue struct {
+	Attribute CIPAttribute
+	Data      []byte
+}
+
+// Write several attributes of one object in a single request.
+//
+// The controller sets each attribute on its own so some can be set even when others fail.  If any fail the error
+// names the first one that did with its status as a wrapped *CIPError.
+func (client *Client) SetAttrList(class CIPClass, instance CIPInstance, values ...AttributeValue) error {
+	b := bytes.Buffer{}
+	binary.Write(&b, binary.LittleEndian, uint16(len(values)))
+	for _, v := range values {
+		binary.Write(&b, binary.LittleEndian, uint16(v.Attribute))
+		b.Write(v.Data)
+	}
+	reply, err := client.GenericCIP(CIPService_SetAttributeList, class, instance, 0, b.Bytes())
+	if err != nil {
+		// an attribute list error still has the per attribute statuses which say which one failed.
+		var cerr *CIPError
+		if attr_err := parseSetAttrListReply(reply); errors.As(attr_err, &cerr) {
+			return attr_err
+		}
+		return err
+	}
+	return parseSetAttrListReply(reply)
+}
+
+// the reply to a set attribute list is the count then the id and status of each attribute.
+func parseSetAttrListReply(dat []byte) error {
+	if len(dat) < 2 {
+		return fmt.Errorf("set attribute list reply too short. got %d bytes", len(dat))
+	}
+	count := int(binary.LittleEndian.Uint16(dat))
+	dat = dat[2:]
+	if len(dat) < 4*count {
+		return fmt.Errorf("set attribute list reply too short for %d attributes", count)
+	}
+	for i := 0; i < count; i++ {
+		attr := binary.LittleEndian.Uint16(dat[4*i:])
+		status := binary.LittleEndian.Uint16(dat[4*i+2:])
+		if status != 0 {
+			return fmt.Errorf("problem setting attribute %d: %w", attr, &CIPError{General: byte(status)})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package gologix
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCIPPath(t *testing.T) {
+	var tests = []struct {
+		class     CIPClass
+		instance  CIPInstance
+		attribute CIPAttribute
+		want      []byte
+	}{
+		{CipObject_TCPIP, 1, 5, []byte{0x20, 0xF5, 0x24, 0x01, 0x30, 0x05}},
+		{CipObject_Identity, 1, 0, []byte{0x20, 0x01, 0x24, 0x01}},
+		{CipObject_EthernetLink, 0x102, 3, []byte{0x20, 0xF6, 0x25, 0x00, 0x02, 0x01, 0x30, 0x03}},
+	}
+	for _, tt := range tests {
+		have, err := cipPath(tt.class, tt.instance, tt.attribute)
+		if err != nil {
+			t.Errorf("%v/%v/%v: %v", tt.class, tt.instance, tt.attribute, err)
+			continue
+		}
+		if !check_bytes(have, tt.want) {
+			t.Errorf("%v/%v/%v: wanted %v got %v", tt.class, tt.instance, tt.attribute, to_hex(tt.want), to_hex(have))
+		}
+	}
+}
+
+func unconnectedReply(t *testing.T, dat []byte) *bytes.Buffer {
+	item := CIPItem{Header: cipItemHeader{ID: cipItem_UnconnectedData}}
+	item.Serialize(dat)
+	b, err := serializeItems([]CIPItem{{}, item})
+	if err != nil {
+		t.Fatalf("problem building reply: %v", err)
+	}
+	return bytes.NewBuffer(*b)
+}
+
+func TestParseUnconnectedReply(t *testing.T) {
+	service := CIPService_GetAttributeSingle
+	reply := unconnectedReply(t, []byte{byte(service.AsResponse()), 0, 0, 0, 0x00, 0x1D, 0x9C, 0x01, 0x02, 0x03})
+	have, err := parseUnconnectedReply(service, reply)
+	if err != nil {
+		t.Fatalf("problem parsing reply: %v", err)
+	}
+	if want := []byte{0x00, 0x1D, 0x9C, 0x01, 0x02, 0x03}; !check_bytes(have, want) {
+		t.Errorf("wanted %v got %v", to_hex(want), to_hex(have))
+	}
+
+	reply = unconnectedReply(t, []byte{byte(service.AsResponse()), 0, 0x14, 0})
+	_, err = parseUnconnectedReply(service, reply)
+	if general, ok := cipGeneral(err); !ok || general != CIPStatus_AttributeNotSupported {
+		t.Errorf("wanted attribute not supported. got %v", err)
+	}
+
+	reply = unconnectedReply(t, []byte{byte(CIPService_Read.AsResponse()), 0, 0, 0})
+	_, err = parseUnconnectedReply(service, reply)
+	if err == nil {
+		t.Errorf("a reply to a different service should be an error")
+	}
+
+	// the connection manager answering for the embedded message with a remaining path size after the status.
+	reply = unconnectedReply(t, []byte{byte(CIPService_UnconnectedSend.AsResponse()), 0, byte(CIPStatus_ConnectionFailure), 1, 0x04, 0x03, 0x01})
+	_, err = parseUnconnectedReply(service, reply)
+	var cerr *CIPError
+	if !errors.As(err, &cerr) || cerr.General != byte(CIPStatus_ConnectionFailure) || cerr.extended() != 0x0304 {
+		t.Errorf("wanted the unconnected send's connection failure status. got %v", err)
+	}
+}
+
+func TestUnconnectedSendMessage(t *testing.T) {
+	path, err := cipPath(CipObject_Identity, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	embedded := embeddedMessage(CIPService_GetAttributeSingle, path, nil)
+	have := unconnectedSendMessage(embedded, []byte{0x01, 0x00}, 0x0A, 0x0E)
+	want := []byte{
+		0x52, 0x02, 0x20, 0x06, 0x24, 0x01, // unconnected send to the connection manager
+		0x0A, 0x0E, // priority/tick and timeout ticks
+		0x08, 0x00, // embedded message size
+		0x0E, 0x03, 0x20, 0x01, 0x24, 0x01, 0x30, 0x01, // get attribute single identity 1 attribute 1
+		0x01, 0x00, 0x01, 0x00, // route path size, reserved, backplane slot 0
+	}
+	if !check_bytes(have, want) {
+		t.Errorf("wanted %v got %v", to_hex(want), to_hex(have))
+	}
+
+	// an odd sized message gets a pad byte before the route.
+	have = unconnectedSendMessage([]byte{0x01, 0x02, 0x20, 0x01, 0x24, 0x01, 0xFF}, []byte{0x01, 0x00}, 0x0A, 0x0E)
+	if want := []byte{0x07, 0x00, 0x01, 0x02, 0x20, 0x01, 0x24, 0x01, 0xFF, 0x00, 0x01, 0x00, 0x01, 0x00}; !check_bytes(have[8:], want) {
+		t.Errorf("wanted %v got %v", to_hex(want), to_hex(have[8:]))
+	}
+}
+
+func TestParseSetAttrListReply(t *testing.T) {
+	err := parseSetAttrListReply([]byte{0x02, 0x00, 0x03, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00})
+	if err != nil {
+		t.Errorf("all attributes set so there should be no error. got %v", err)
+	}
+	err = parseSetAttrListReply([]byte{0x02, 0x00, 0x03, 0x00, 0x00, 0x00, 0x05, 0x00, 0x0E, 0x00})
+	if general, ok := cipGeneral(err); !ok || general != CIPStatus_AttributeNotSettable {
+		t.Errorf("attribute 5 should be not settable. got %v", err)
+	}
+	err = parseSetAttrListReply([]byte{0x02, 0x00, 0x03, 0x00})
+	if err == nil {
+		t.Errorf("a short reply should be an error")
+	}
+}
+
+func TestUnconnectedSendTicks(t *testing.T) {
+	client, remote := newPipeClient(t)
+	if tick, ticks := client.unconnectedTicks(); tick != 0x0A || ticks != 0x0E {
+		t.Errorf("wanted the default 0x0A and 0x0E. got %#x and %#x", tick, ticks)
+	}
+	client.UnconnectedPriorityTick = 0x0B
+	client.UnconnectedTimeoutTicks = 0x40
+
+	requests := make(chan []byte, 1)
+	go func() {
+		_, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		requests <- buf.Bytes()
+		remote.Close()
+	}()
+	client.GenericCIPUnconnectedSend(CIPService_GetAttributeSingle, CipObject_Identity, 1, 1, nil)
+
+	// interface handle, timeout, item count, the null address item, and the unconnected data item header come first.
+	req := (<-requests)[16:]
+	if CIPService(req[0]) != CIPService_UnconnectedSend {
+		t.Fatalf("wanted an unconnected send. got %v", CIPService(req[0]))
+	}
+	if req[6] != 0x0B || req[7] != 0x40 {
+		t.Errorf("wanted ticks 0x0B and 0x40. got %#x and %#x", req[6], req[7])
+	}
+}
@@ -277,7 +277,16 @@ func (client *Client) GenericCIPMessage(service CIPService, path, msg_data []byt
 		return nil, fmt.Errorf("problem getting resposne status: %w", err)
 	}
 	if status != 0 {
-		return &items[1], fmt.Errorf("got status of 0x%X instead of 0", status)
+		// general status in the low byte and the number of extended status words in the high byte.
+		cerr := &CIPError{General: byte(status)}
+		for i := 0; i < int(uint16(status)>>8); i++ {
+			extended, err := items[1].Uint16()
+			if err != nil {
+				break
+			}
+			cerr.Extended = append(cerr.Extended, extended)
+		}
+		return &items[1], fmt.Errorf("service 0x%X failed: %w", service, cerr)
 	}
 
 	return &items[1], nil
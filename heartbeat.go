@@ -0,0 +1,76 @@
+package gologix
+
+import (
+	"log/slog"
+	"time"
+)
+
+// how long the controller will let the cip connection sit idle before dropping it.  This is the RPI times the timeout
+// multiplier sent in the forward open where a multiplier of n means 4 << n.
+func (client *Client) connectionTimeout() time.Duration {
+	rpi := client.RPI
+	if rpi == 0 {
+		rpi = rpiDefault
+	}
+	return rpi * time.Duration(4<<client.timeoutMultiplier)
+}
+
+// start the connection heartbeat for the current connection.  Any heartbeat left over from an earlier connection is
+// stopped first.
+func (client *Client) startHeartbeat() {
+	client.stopHeartbeat()
+	stop := make(chan struct{})
+	client.heartbeat_stop = stop
+	go client.heartbeat(client.connGeneration.Load(), stop)
+}
+
+// stop the connection heartbeat if it is running.
+func (client *Client) stopHeartbeat() {
+	if client.heartbeat_stop != nil {
+		close(client.heartbeat_stop)
+		client.heartbeat_stop = nil
+	}
+}
+
+// Every quarter of the connection timeout, send a cheap request if nothing else has gone over the connection since the
+// last check.  Normal reads and writes count as traffic so a busy client never sends any extra requests.  With
+// HeartbeatNop a check that finds the connection less than half way to timing out sends a NOP instead.  NOPs don't
+// count as traffic so the request still goes out at the next check.
+//
+// The heartbeat goes through the same send path as everything else so it just waits its turn behind user requests.
+// A failed heartbeat is only logged.  If the connection really is gone the next user request will find out and
+// reconnect as usual.
+func (client *Client) heartbeat(gen uint32, stop chan struct{}) {
+	interval := client.connectionTimeout() / 4
+	if interval <= 0 {
+		return
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			if !client.connected || client.connGeneration.Load() != gen {
+				return
+			}
+			last := time.Unix(0, client.lastUnitData.Load())
+			idle := time.Since(last)
+			if idle < interval {
+				continue
+			}
+			if client.HeartbeatNop && idle < client.connectionTimeout()/2 {
+				err := client.Nop()
+				if err == nil {
+					continue
+				}
+				client.Logger.Debug("connection heartbeat nop failed. sending a request instead", slog.Any("err", err))
+			}
+			_, err := client.GetAttrSingle(CipObject_Identity, 1, 1)
+			if err != nil {
+				client.Logger.Warn("connection heartbeat failed", slog.Any("err", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package gologix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConnectionTimeout(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.RPI = time.Millisecond * 2500
+	client.timeoutMultiplier = 0x03
+	if have := client.connectionTimeout(); have != time.Second*80 {
+		t.Errorf("large forward open: wanted 80s got %v", have)
+	}
+	client.timeoutMultiplier = 0x00
+	if have := client.connectionTimeout(); have != time.Second*10 {
+		t.Errorf("standard forward open: wanted 10s got %v", have)
+	}
+}
+
+// the heartbeat should stay quiet while other requests are going out and only send once the connection goes idle.
+func TestHeartbeat(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.RPI = time.Millisecond * 25 // times out after 100ms so the heartbeat checks every 25ms
+
+	requests := make(chan byte, 10)
+	go func() {
+		for {
+			_, buf, err := recvData(remote)
+			if err != nil {
+				return
+			}
+			// items header, connection address item, connected data item header, then the sequence count
+			requests <- buf.Bytes()[22]
+		}
+	}()
+
+	client.lastUnitData.Store(time.Now().UnixNano())
+	client.startHeartbeat()
+	defer client.stopHeartbeat()
+
+	busy := time.After(time.Millisecond * 150)
+	for done := false; !done; {
+		select {
+		case <-busy:
+			done = true
+		case <-time.After(time.Millisecond * 5):
+			client.lastUnitData.Store(time.Now().UnixNano())
+		}
+	}
+	select {
+	case <-requests:
+		t.Fatalf("heartbeat sent while the connection was busy")
+	default:
+	}
+
+	select {
+	case service := <-requests:
+		if CIPService(service) != CIPService_GetAttributeSingle {
+			t.Errorf("wanted a get attribute single heartbeat. got %v", CIPService(service))
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no heartbeat once the connection went idle")
+	}
+}
+
+// with HeartbeatNop the first heartbeat is a NOP and the next one, with the connection half way to timing out, is a
+// real request.
+func TestHeartbeatNop(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.RPI = time.Millisecond * 50 // times out after 200ms so the heartbeat checks every 50ms
+	client.HeartbeatNop = true
+
+	type request struct {
+		cmd     CIPCommand
+		service CIPService
+	}
+	requests := make(chan request, 10)
+	go func() {
+		for {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				return
+			}
+			r := request{cmd: hdr.Command}
+			if buf.Len() > 22 {
+				r.service = CIPService(buf.Bytes()[22])
+			}
+			requests <- r
+		}
+	}()
+
+	client.lastUnitData.Store(time.Now().UnixNano())
+	client.startHeartbeat()
+	defer client.stopHeartbeat()
+
+	for i, want := range []request{{cmd: cipCommand_NOP}, {cmd: cipCommandSendUnitData, service: CIPService_GetAttributeSingle}} {
+		select {
+		case have := <-requests:
+			if have != want {
+				t.Errorf("heartbeat %d: wanted %+v got %+v", i, want, have)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no heartbeat %d", i)
+		}
+	}
+}
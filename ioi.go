@@ -49,9 +49,12 @@ func (tag *tagPartDescriptor) Parse(tagpath string) error {
 		tag.BasePath = strings.ReplaceAll(tag.BasePath, arr_access_text, "")
 		if strings.Contains(arr_access_text, ",") {
 			parts := strings.Split(arr_access_text, ",")
+			if len(parts) > 3 {
+				return fmt.Errorf("%v has %d array indexes. logix arrays have at most 3 dimensions", tagpath, len(parts))
+			}
 			tag.Array_Order = make([]int, len(parts))
 			for i, part := range parts {
-				tag.Array_Order[i], err = strconv.Atoi(part)
+				tag.Array_Order[i], err = strconv.Atoi(strings.TrimSpace(part))
 				if err != nil {
 					return fmt.Errorf("could't parse %v to an array position. %w", arr_access_text, err)
 				}
@@ -59,7 +62,7 @@ func (tag *tagPartDescriptor) Parse(tagpath string) error {
 
 		} else {
 			tag.Array_Order = make([]int, 1)
-			tag.Array_Order[0], err = strconv.Atoi(arr_access_text)
+			tag.Array_Order[0], err = strconv.Atoi(strings.TrimSpace(arr_access_text))
 			if err != nil {
 				return fmt.Errorf("could't parse %v to an array position. %w", arr_access_text, err)
 			}
@@ -134,6 +137,11 @@ func (client *Client) newIOI(tagpath string, datatype CIPType) (ioi *tagIOI, err
 		}
 	}
 
+	err = client.checkScope(tagpath)
+	if err != nil {
+		return nil, err
+	}
+
 	extant, exists := client.ioi_cache[tagpath]
 	if exists {
 		ioi = extant
@@ -146,7 +154,7 @@ func (client *Client) newIOI(tagpath string, datatype CIPType) (ioi *tagIOI, err
 	// we'll build this byte structure up as we go.
 	ioi.Buffer = make([]byte, 0, defaultIOIBufferSize)
 
-	for _, tag_part := range tag_array {
+	for i, tag_part := range tag_array {
 		if strings.HasSuffix(tag_part, "]") {
 			// part of an array
 			start_index := strings.Index(tag_part, "[")
@@ -162,7 +170,15 @@ func (client *Client) newIOI(tagpath string, datatype CIPType) (ioi *tagIOI, err
 
 			t, err := parse_tag_name(tag_part)
 			if err != nil {
-				client.Logger.Warn("problem parsing path", "error", err)
+				return nil, fmt.Errorf("problem parsing path %s: %w", tagpath, err)
+			}
+			if len(t.Array_Order) == 0 {
+				return nil, fmt.Errorf("couldn't parse the array index of %s in %s", tag_part, tagpath)
+			}
+			base := strings.Join(append(tag_array[:i:i], tag_part[0:start_index]), ".")
+			err = client.checkArrayIndexes(base, t.Array_Order)
+			if err != nil {
+				return nil, err
 			}
 
 			for _, order_size := range t.Array_Order {
@@ -198,10 +214,17 @@ func (client *Client) newIOI(tagpath string, datatype CIPType) (ioi *tagIOI, err
 		} else {
 			// not part of an array
 			bit_access, err := strconv.Atoi(tag_part)
-			if err == nil && bit_access <= 31 {
+			if err == nil {
 				// This is a bit access.
 				// we won't do anything for now and will just parse the
 				// bit out of the word when that time comes.
+				if bit_access < 0 || bit_access > 63 {
+					return nil, fmt.Errorf("bit %d of %s out of range. must be 0-63", bit_access, tagpath)
+				}
+				if i == 0 || i != len(tag_array)-1 {
+					// the bit has to come after the tag or member it is in and nothing can come after it.
+					return nil, fmt.Errorf("bit %d has to be the last part of %s", bit_access, tagpath)
+				}
 				ioi.BitAccess = true
 				ioi.BitPosition = bit_access
 				continue
@@ -223,6 +246,57 @@ func (client *Client) newIOI(tagpath string, datatype CIPType) (ioi *tagIOI, err
 	return
 }
 
+// if we know the array from a ListAllTags call make sure the indexes match its dimensions.  The controller wants one
+// index per dimension in the order they are declared (ex: Grid[row,col] for a DINT[rows,cols]) and a missing or extra
+// index would otherwise get an error from it or a different element than the one asked for.
+func (client *Client) checkArrayIndexes(base string, indexes []int) error {
+	known, ok := client.KnownTags[base]
+	if !ok || len(known.Array_Order) == 0 {
+		return nil
+	}
+	if len(indexes) != len(known.Array_Order) {
+		return fmt.Errorf("%s has %d dimensions but %d indexes were given", base, len(known.Array_Order), len(indexes))
+	}
+	for d, index := range indexes {
+		if index < 0 || index >= known.Array_Order[d] {
+			return fmt.Errorf("index %d is out of range for dimension %d of %s which has %d elements", index, d, base, known.Array_Order[d])
+		}
+	}
+	return nil
+}
+
+// check the "Program:Name" prefix of a program scoped tag before it goes in the path.  The whole "Program:Name" is
+// one symbolic segment so a typo in it would otherwise come back from the controller as the tag not existing.  A path
+// of just the program is fine since that addresses the program itself.  Other names with a colon in them, like the
+// module tags "Local:1:I.Data", aren't program scoped and go through as they are.
+//
+// If the programs are known from ListAllPrograms the program has to be one of them.  Otherwise only the syntax is
+// checked.
+func (client *Client) checkScope(tagpath string) error {
+	first, _, _ := strings.Cut(tagpath, ".")
+	program, ok := strings.CutPrefix(first, "program:")
+	if !ok {
+		return nil
+	}
+	if program == "" {
+		return fmt.Errorf("no program name in %s", tagpath)
+	}
+	for i, r := range program {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' && i > 0) {
+			return fmt.Errorf("invalid program name %q in %s", program, tagpath)
+		}
+	}
+	if len(tagpath) == len(first)+1 {
+		return fmt.Errorf("no tag after program %s in %s", program, tagpath)
+	}
+	if len(client.KnownPrograms) > 0 {
+		if _, ok := client.findProgram(program); !ok {
+			return fmt.Errorf("%w: %s in %s", ErrProgramNotFound, program, tagpath)
+		}
+	}
+	return nil
+}
+
 func marshalIOIPart(tagpath string) ([]byte, error) {
 	t, err := parse_tag_name(tagpath)
 	if err != nil {
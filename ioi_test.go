@@ -1,6 +1,7 @@
 package gologix
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -102,6 +103,75 @@ func TestIOI(t *testing.T) {
 
 }
 
+// indexes have to match the dimensions of arrays we know about.
+func TestIOIArrayIndexes(t *testing.T) {
+	client := NewClient("localhost")
+	client.KnownTags["grid"] = KnownTag{Name: "Grid", Info: TagInfo{Type: CIPTypeDINT, TypeInfo: 0x40, Dimension1: 3, Dimension2: 4}, Array_Order: []int{3, 4}}
+
+	res, err := client.newIOI("Grid[1, 3]", CIPTypeDINT)
+	if err != nil {
+		t.Fatalf("problem building IOI: %v", err)
+	}
+	want := []byte{0x91, 0x04, 'g', 'r', 'i', 'd', 0x28, 0x01, 0x28, 0x03}
+	if !check_bytes(res.Buffer, want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(res.Buffer))
+	}
+
+	for _, path := range []string{"Grid[1]", "Grid[1,2,3]", "Grid[3,0]", "Grid[0,4]", "Other[1,2,3,4]", "Other[1,x]"} {
+		_, err := client.newIOI(path, CIPTypeDINT)
+		if err == nil {
+			t.Errorf("%s: wanted an error", path)
+		}
+	}
+}
+
+// the program of a program scoped tag is checked before anything is sent.
+func TestIOIProgramScope(t *testing.T) {
+	client := NewClient("localhost")
+
+	res, err := client.newIOI("Program:Main.Count", CIPTypeDINT)
+	if err != nil {
+		t.Fatalf("problem building IOI: %v", err)
+	}
+	want := []byte{0x91, 0x0C, 'p', 'r', 'o', 'g', 'r', 'a', 'm', ':', 'm', 'a', 'i', 'n', 0x91, 0x05, 'c', 'o', 'u', 'n', 't', 0x00}
+	if !check_bytes(res.Buffer, want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(res.Buffer))
+	}
+
+	for _, path := range []string{"Program:.Count", "Program:Main.", "Program:1Main.Count", "Program:Ma in.Count"} {
+		_, err := client.newIOI(path, CIPTypeDINT)
+		if err == nil {
+			t.Errorf("%s: wanted an error", path)
+		}
+	}
+
+	// module tags have colons in them too but aren't program scoped.
+	res, err = client.newIOI("Local:1:I.Data", CIPTypeDINT)
+	if err != nil {
+		t.Fatalf("problem building IOI for a module tag: %v", err)
+	}
+	want = []byte{0x91, 0x09, 'l', 'o', 'c', 'a', 'l', ':', '1', ':', 'i', 0x00, 0x91, 0x04, 'd', 'a', 't', 'a'}
+	if !check_bytes(res.Buffer, want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(res.Buffer))
+	}
+
+	// without the programs known any program name is sent.
+	_, err = client.newIOI("Program:Other.Count", CIPTypeDINT)
+	if err != nil {
+		t.Errorf("problem building IOI for an unknown program: %v", err)
+	}
+
+	client.KnownPrograms = map[string]*KnownProgram{"MainProgram": {Name: "MainProgram", ID: 1}}
+	_, err = client.newIOI("Program:MainProgram.Count", CIPTypeDINT)
+	if err != nil {
+		t.Errorf("problem building IOI for a known program: %v", err)
+	}
+	_, err = client.newIOI("Program:MainProgam.Count", CIPTypeDINT)
+	if !errors.Is(err, ErrProgramNotFound) {
+		t.Errorf("wanted ErrProgramNotFound. got %v", err)
+	}
+}
+
 func to_hex(b []byte) []string {
 	out := make([]string, len(b))
 
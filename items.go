@@ -24,6 +24,9 @@ const (
 // readItems takes an io.Reader positioned at the count of items in the data stream.
 // It then reads each item from the data stream into an Item structure and returns a slice of all items.
 func readItems(r io.Reader) ([]CIPItem, error) {
+	if b, ok := r.(*bytes.Buffer); ok {
+		return readItemsBuffer(b)
+	}
 
 	var count uint16
 
@@ -49,6 +52,32 @@ func readItems(r io.Reader) ([]CIPItem, error) {
 	return items, nil
 }
 
+// readItems for a response that is already in memory.  The items' data point into b instead of being copied.
+func readItemsBuffer(b *bytes.Buffer) ([]CIPItem, error) {
+	if b.Len() < 2 {
+		return nil, fmt.Errorf("couldn't read item count. %w", io.ErrUnexpectedEOF)
+	}
+	count := binary.LittleEndian.Uint16(b.Next(2))
+
+	items := make([]CIPItem, count) // usually have 2 items.
+
+	for i := 0; i < int(count); i++ {
+		if b.Len() < 4 {
+			return nil, fmt.Errorf("couldn't read item %d header. %w", i, io.ErrUnexpectedEOF)
+		}
+		hdr := b.Next(4)
+		items[i].Header.ID = CIPItemID(binary.LittleEndian.Uint16(hdr))
+		items[i].Header.Length = binary.LittleEndian.Uint16(hdr[2:])
+		if b.Len() < int(items[i].Header.Length) {
+			return nil, fmt.Errorf("couldn't read item %d (hdr: %+v) data. %w", i, items[i].Header, io.ErrUnexpectedEOF)
+		}
+		// capped so appending to an item can't write over the next one.
+		data := b.Next(int(items[i].Header.Length))
+		items[i].Data = data[:len(data):len(data)]
+	}
+	return items, nil
+}
+
 // The CIPItem is one of the core abstractions this library uses.
 //
 // When a response comes back from the controller it is structured in a CIPItem which can then
@@ -211,10 +240,17 @@ func (item *CIPItem) Float64() (float64, error) {
 //
 // The data length in the item's header is updated to match.
 func (item *CIPItem) Serialize(str any) error {
+	return item.SerializeOrder(str, binary.LittleEndian)
+}
+
+// Serialize a structure into the item's data with its values in the given byte order.
+//
+// This is only for the value of a tag.  CIP headers are always little endian so use Serialize for them.
+func (item *CIPItem) SerializeOrder(str any, order binary.ByteOrder) error {
 	switch x := str.(type) {
 	case string:
 		strLen := uint32(len(x))
-		err := binary.Write(item, binary.LittleEndian, strLen)
+		err := binary.Write(item, order, strLen)
 		if err != nil {
 			return fmt.Errorf("problem writing string header: %v", err)
 		}
@@ -235,7 +271,7 @@ func (item *CIPItem) Serialize(str any) error {
 			return fmt.Errorf("problem writing serializable item: %v", err)
 		}
 	default:
-		err := binary.Write(item, binary.LittleEndian, str)
+		err := binary.Write(item, order, str)
 		if err != nil {
 			return fmt.Errorf("problem writing default item: %v", err)
 		}
@@ -311,27 +347,22 @@ type cipItemsHeader struct {
 //	...  repeat for all items...
 func serializeItems(items []CIPItem) (*[]byte, error) {
 
-	b := new(bytes.Buffer)
-
-	item_hdr := cipItemsHeader{
-		InterfaceHandle: 0,
-		SequenceCounter: 0,
-		Count:           uint16(len(items)),
-	}
-	err := binary.Write(b, binary.LittleEndian, item_hdr)
-	if err != nil {
-		return nil, fmt.Errorf("problem serializing item header into b. %v", err)
+	size := 8
+	for _, item := range items {
+		size += 4 + len(item.Data)
 	}
+	out := make([]byte, 0, size)
 
-	for i, item := range items {
-		b2, err := item.Bytes()
-		if err != nil {
-			return nil, fmt.Errorf("problem byte-ing item %d: %w", i, err)
-		}
-		b.Write(b2)
-	}
+	// the items header: InterfaceHandle, SequenceCounter, and Count.
+	out = binary.LittleEndian.AppendUint32(out, 0)
+	out = binary.LittleEndian.AppendUint16(out, 0)
+	out = binary.LittleEndian.AppendUint16(out, uint16(len(items)))
 
-	out := b.Bytes()
+	for _, item := range items {
+		out = binary.LittleEndian.AppendUint16(out, uint16(item.Header.ID))
+		out = binary.LittleEndian.AppendUint16(out, item.Header.Length)
+		out = append(out, item.Data...)
+	}
 
 	return &out, nil
 }
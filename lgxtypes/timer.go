@@ -35,6 +35,18 @@ func (t TIMER) Pack(w io.Writer) (int, error) {
 	if t.DN {
 		CtrlWord |= 1 << 29
 	}
+	if t.FS {
+		CtrlWord |= 1 << 28
+	}
+	if t.LS {
+		CtrlWord |= 1 << 27
+	}
+	if t.OV {
+		CtrlWord |= 1 << 26
+	}
+	if t.ER {
+		CtrlWord |= 1 << 25
+	}
 
 	err := binary.Write(w, binary.LittleEndian, CtrlWord)
 	if err != nil {
@@ -63,6 +75,10 @@ func (t *TIMER) Unpack(r io.Reader) (int, error) {
 	t.EN = CtrlWord&(1<<31) != 0
 	t.TT = CtrlWord&(1<<30) != 0
 	t.DN = CtrlWord&(1<<29) != 0
+	t.FS = CtrlWord&(1<<28) != 0
+	t.LS = CtrlWord&(1<<27) != 0
+	t.OV = CtrlWord&(1<<26) != 0
+	t.ER = CtrlWord&(1<<25) != 0
 
 	err = binary.Read(r, binary.LittleEndian, &(t.PRE))
 	if err != nil {
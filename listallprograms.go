@@ -1,9 +1,40 @@
 package gologix
 
 import (
+	"fmt"
 	"strings"
 )
 
+// Get the names of the programs in the controller, without the "Program:" prefix, in the order the controller lists
+// them.  Pass them to ListProgramTags to get the tags of each program.
+//
+// Every program has a "Program:<name>" instance in the symbol object so this pages through the symbol object the same
+// way ListAllTags does, as many requests as it takes, and keeps only those.  KnownTags isn't changed.
+func (client *Client) ListPrograms() ([]string, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return nil, fmt.Errorf("could not start program list: %w", err)
+	}
+	names := make([]string, 0)
+	start := uint32(1)
+	for {
+		tags, more, err := client.listTagsPage(start)
+		if err != nil {
+			return names, fmt.Errorf("problem listing programs from instance %d: %w", start, err)
+		}
+		for _, kt := range tags {
+			name, ok := strings.CutPrefix(kt.Name, "Program:")
+			if ok && name != "" && !strings.Contains(name, ".") {
+				names = append(names, name)
+			}
+		}
+		if !more || len(tags) == 0 {
+			return names, nil
+		}
+		start = uint32(tags[len(tags)-1].Instance) + 1
+	}
+}
+
 func (client *Client) ListAllPrograms() error {
 	client.Logger.Debug("listing all programs")
 
@@ -0,0 +1,50 @@
+package gologix
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListPrograms(t *testing.T) {
+	client, remote := newPipeClient(t)
+	pages := [][]byte{
+		symbolListReply(CIPStatus_PartialTransfer,
+			map[uint32]string{1: "Alpha", 2: "Program:MainProgram", 3: "Program:Alarms"},
+			[]uint32{1, 2, 3},
+			map[uint32]CIPType{1: CIPTypeDINT, 2: 0x68, 3: 0x68}),
+		symbolListReply(CIPStatus_OK,
+			map[uint32]string{7: "Beta", 9: "Program:Conveyor"},
+			[]uint32{7, 9},
+			map[uint32]CIPType{7: CIPTypeREAL, 9: 0x68}),
+	}
+	starts := make(chan byte, len(pages))
+	go func() {
+		for _, page := range pages {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			dat := buf.Bytes()[20:]
+			starts <- dat[4+3]
+			items := []CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), {Header: cipItemHeader{ID: cipItem_ConnectedData}}}
+			items[1].Serialize(page)
+			b, _ := serializeItems(items)
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}
+	}()
+
+	have, err := client.ListPrograms()
+	if err != nil {
+		t.Fatalf("problem listing programs: %v", err)
+	}
+	if want := []string{"MainProgram", "Alarms", "Conveyor"}; !reflect.DeepEqual(have, want) {
+		t.Errorf("wanted %v got %v", want, have)
+	}
+	if first, second := <-starts, <-starts; first != 1 || second != 4 {
+		t.Errorf("wanted pages starting at 1 and 4. got %d and %d", first, second)
+	}
+	if len(client.KnownTags) != 0 {
+		t.Errorf("listing programs shouldn't change KnownTags. got %v", client.KnownTags)
+	}
+}
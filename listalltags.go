@@ -18,6 +18,42 @@ type TagInfo struct {
 	Dimension1 uint32
 	Dimension2 uint32
 	Dimension3 uint32
+
+	// Whether anything outside the controller can read or write the tag.  This is the External Access setting of the
+	// tag in the controller's project.
+	ExternalAccess ExternalAccess
+}
+
+// ExternalAccess is attribute 10 of a tag's symbol object.
+// see page 40 of 1756-PM020H-EN-P
+type ExternalAccess byte
+
+const (
+	ExternalAccessReadWrite ExternalAccess = 0
+	ExternalAccessReadOnly  ExternalAccess = 2
+	ExternalAccessNone      ExternalAccess = 3
+)
+
+func (e ExternalAccess) String() string {
+	switch e & 0x03 {
+	case ExternalAccessReadWrite:
+		return "Read/Write"
+	case ExternalAccessReadOnly:
+		return "Read Only"
+	case ExternalAccessNone:
+		return "None"
+	}
+	return fmt.Sprintf("Unknown(%d)", byte(e))
+}
+
+// Whether the tag can be read from outside the controller.
+func (e ExternalAccess) Readable() bool {
+	return e&0x03 != ExternalAccessNone
+}
+
+// Whether the tag can be written from outside the controller.
+func (e ExternalAccess) Writable() bool {
+	return e&0x03 == ExternalAccessReadWrite
 }
 
 // returns whether the type of the tag is a pre-defined type like a DINT, SINT, INT, REAL, etc...
@@ -38,6 +74,13 @@ func (f TagInfo) Atomic() bool {
 	return val&0b1001_0000_0000_0000 == 0
 }
 
+// returns whether the tag is a structure (UDT, STRING, TIMER, etc...) rather than an atomic type.
+// see page 42 of 1756-PM020H-EN-P
+func (f TagInfo) Struct() bool {
+	val := binary.LittleEndian.Uint16([]byte{byte(f.Type), f.TypeInfo})
+	return val&(1<<15) != 0
+}
+
 // The template ID is basically the type of the tag.  Probably a udt.
 // see page 42 of 1756-PM020H-EN-P
 func (f TagInfo) Template_ID() uint16 {
@@ -56,6 +99,39 @@ func (f TagInfo) Template_ID() uint16 {
 
 }
 
+// The number of array dimensions of the tag from bits 13 and 14 of its type.  0 for a tag that isn't an array.
+// see page 42 of 1756-PM020H-EN-P
+func (f TagInfo) Dimensions() int {
+	_, _, _, dims := ParseTypeDescriptor(binary.LittleEndian.Uint16([]byte{byte(f.Type), f.TypeInfo}))
+	return dims
+}
+
+// the sizes of the array dimensions of a tag, first dimension first.  Empty for a tag that isn't an array.
+func (f TagInfo) arrayOrder() []int {
+	// the type says how many dimensions there are.  The sizes of the ones past that are 0.
+	sizes := []uint32{f.Dimension1, f.Dimension2, f.Dimension3}
+	order := make([]int, f.Dimensions())
+	for i := range order {
+		order[i] = int(sizes[i])
+	}
+	return order
+}
+
+// Split up the 16 bit symbol type of a tag the way the symbol object and template members report it.
+//
+// Bit 15 is set for structures.  Their low 12 bits are the instance of the template object that describes the
+// structure (see ListMembers) and the type is CIPTypeStruct.  For atomic types the low byte is the type and
+// structHandle is 0.  BOOLs keep their bit position in bits 8-10 which is ignored here.  Bits 13-14 are the number of
+// array dimensions, 0 through 3.
+// see page 42 of 1756-PM020H-EN-P
+func ParseTypeDescriptor(word uint16) (t CIPType, isStruct bool, structHandle uint16, dims int) {
+	dims = int(word>>13) & 0x03
+	if word&(1<<15) != 0 {
+		return CIPTypeStruct, true, word & 0x0FFF, dims
+	}
+	return CIPType(word & 0xFF), false, 0, dims
+}
+
 type msgListInstanceHeader struct {
 	Service       CIPService
 	Reserved      byte
@@ -86,6 +162,124 @@ func (client *Client) ListAllTags(start_instance uint32) error {
 		}
 	}
 
+	tags, more, err := client.listTagsPage(start_instance)
+	if err != nil {
+		return err
+	}
+	if len(tags) > 0 {
+		start_instance = uint32(tags[len(tags)-1].Instance)
+	}
+	client.storeTags(tags)
+
+	if more { //} && start_instance < 200 {
+		// pick up after the last instance we got.
+		err = client.ListAllTags(start_instance + 1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get every controller scoped tag on the device.  This is ListAllTags but the tags are returned as well as being added
+// to KnownTags with their UDT templates.  Each one has its name, type, dimensions, and structure template in Info.
+//
+// The programs aren't listed.  Use ListProgramTags for the tags of a program.
+func (client *Client) ListAllTagsInfo() ([]KnownTag, error) {
+	var all []KnownTag
+	start := uint32(1)
+	for {
+		page, more, err := client.listTagsPage(start)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, client.storeTags(page)...)
+		if !more {
+			return all, nil
+		}
+		if len(page) == 0 {
+			return all, fmt.Errorf("partial transfer from instance %d had no tags", start)
+		}
+		// pick up after the last instance we got.
+		start = uint32(page[len(page)-1].Instance) + 1
+	}
+}
+
+// Get one reply's worth of the controller scoped tags starting at startInstance and the instance to start the next
+// call at.  next is 0 once there are no more tags so a browse is
+//
+//	for next := uint16(1); next != 0; {
+//		tags, next, err = client.ListTagsFrom(next)
+//		...
+//	}
+//
+// Save next to pick a long browse back up later, even on another connection, instead of starting over.  The tags
+// found are added to KnownTags with their UDT templates the same as ListAllTags but the programs aren't listed, so do
+// that with ListAllPrograms first if you want program scoped tags.
+func (client *Client) ListTagsFrom(startInstance uint16) (tags []KnownTag, next uint16, err error) {
+	if startInstance == 0 {
+		startInstance = 1
+	}
+	page, more, err := client.listTagsPage(uint32(startInstance))
+	if err != nil {
+		return nil, 0, err
+	}
+	if more {
+		if len(page) == 0 {
+			return nil, 0, fmt.Errorf("partial transfer from instance %d had no tags", startInstance)
+		}
+		last := page[len(page)-1].Instance
+		if last >= 0xFFFF {
+			return nil, 0, fmt.Errorf("instance %d after %d is past what a 16 bit cursor holds", last+1, last)
+		}
+		next = uint16(last) + 1
+	}
+	return client.storeTags(page), next, nil
+}
+
+// put the controller scoped tags among tags into KnownTags, looking up their templates, and return the ones kept.
+func (client *Client) storeTags(tags []KnownTag) []KnownTag {
+	kept := make([]KnownTag, 0, len(tags))
+	for _, kt := range tags {
+		tag_string := kt.Name
+		tag_ftr := kt.Info
+		if !isControllerTag(tag_string, tag_ftr) {
+			continue
+		}
+
+		if tag_ftr.Template_ID() != 0 {
+			client.Logger.Debug("found UDT of some sort", "name", kt.Name)
+		}
+
+		if tag_ftr.Template_ID() != 0 { //&& !tag_ftr.PreDefined() {
+			client.Logger.Debug("Looking up template", "tag name", tag_string)
+			u, err := client.ListMembers(uint32(tag_ftr.Template_ID()))
+			if err != nil {
+				client.Logger.Error("problem reading member list",
+					"string", tag_string,
+					"instance", kt.Instance,
+					"footer", tag_ftr,
+					"template ID", tag_ftr.Template_ID(),
+					"predefined", tag_ftr.PreDefined())
+				//return err
+			} else {
+				kt.UDT = &u
+				client.Logger.Debug("Successful member read for %s", "name", kt.Name)
+				client.KnownTypes[u.Name] = u
+			}
+		}
+
+		client.KnownTags[strings.ToLower(tag_string)] = kt
+		kept = append(kept, kt)
+	}
+	return kept
+}
+
+// get one reply's worth of the symbol object instances starting at start_instance.  more is true when the controller
+// has more after these.  The tags are all there as the controller sent them with nothing filtered out and no UDT
+// templates looked up.
+func (client *Client) listTagsPage(start_instance uint32) ([]KnownTag, bool, error) {
 	reqItems := make([]CIPItem, 2)
 	reqItems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
 
@@ -93,7 +287,7 @@ func (client *Client) ListAllTags(start_instance uint32) error {
 		CipObject_Symbol, CIPInstance(start_instance),
 	)
 	if err != nil {
-		return fmt.Errorf("couldn't build path. %w", err)
+		return nil, false, fmt.Errorf("couldn't build path. %w", err)
 	}
 
 	readMsg := msgCIPConnectedServiceReq{
@@ -107,24 +301,27 @@ func (client *Client) ListAllTags(start_instance uint32) error {
 	// add path
 	reqItems[1].Serialize(path.Bytes())
 	// add service specific data
-	number_of_attr_to_receive := 3
+	// the external access has to come last to line up with the end of TagInfo.
+	number_of_attr_to_receive := 4
 	attr1_symbol_name := 1
 	attr2_symbol_type := 2
 	attr8_arrayDims := 8
-	reqItems[1].Serialize([4]uint16{
+	attr10_externalAccess := 10
+	reqItems[1].Serialize([5]uint16{
 		uint16(number_of_attr_to_receive),
 		uint16(attr1_symbol_name),
 		uint16(attr2_symbol_type),
 		uint16(attr8_arrayDims),
+		uint16(attr10_externalAccess),
 	})
 
 	itemData, err := serializeItems(reqItems)
 	if err != nil {
-		return fmt.Errorf("problem serializing items: %w", err)
+		return nil, false, fmt.Errorf("problem serializing items: %w", err)
 	}
 	hdr, data, err := client.send_recv_data(cipCommandSendUnitData, itemData)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 	_ = hdr
 	_ = data
@@ -135,12 +332,15 @@ func (client *Client) ListAllTags(start_instance uint32) error {
 	padding := make([]byte, 6)
 	_, err = data.Read(padding)
 	if err != nil {
-		return fmt.Errorf("problem getting padding bytes. %w", err)
+		return nil, false, fmt.Errorf("problem getting padding bytes. %w", err)
 	}
 
 	resp_items, err := readItems(data)
 	if err != nil {
-		return fmt.Errorf("couldn't parse items. %w", err)
+		return nil, false, fmt.Errorf("couldn't parse items. %w", err)
+	}
+	if len(resp_items) != 2 {
+		return nil, false, fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(resp_items))
 	}
 
 	// get ready to read tag info from item 1 data
@@ -149,23 +349,27 @@ func (client *Client) ListAllTags(start_instance uint32) error {
 	data_hdr := msgListInstanceHeader{}
 	err = binary.Read(data2, binary.LittleEndian, &data_hdr)
 	if err != nil {
-		return fmt.Errorf("problem reading list instance header. %w", err)
+		return nil, false, fmt.Errorf("problem reading list instance header. %w", err)
+	}
+	more := data_hdr.Status == uint16(CIPStatus_PartialTransfer)
+	if data_hdr.Status != uint16(CIPStatus_OK) && !more {
+		return nil, false, fmt.Errorf("problem listing tags from instance %d: %w", start_instance, &CIPError{General: byte(data_hdr.Status)})
 	}
 
+	tags := make([]KnownTag, 0)
 	tag_hdr := new(msgtagResultDataHeader)
 	tag_ftr := new(TagInfo)
 	for data2.Len() > 0 {
 
 		err = binary.Read(data2, binary.LittleEndian, tag_hdr)
 		if err != nil {
-			return fmt.Errorf("problem reading tag header. %w", err)
+			return nil, false, fmt.Errorf("problem reading tag header. %w", err)
 		}
 		tag_name := make([]byte, tag_hdr.NameLength)
 		err = binary.Read(data2, binary.LittleEndian, &tag_name)
 		if err != nil {
-			return fmt.Errorf("problem reading tag name. %w", err)
+			return nil, false, fmt.Errorf("problem reading tag name. %w", err)
 		}
-		tag_string := string(tag_name)
 
 		// the end of the tagName has to be aligned on a 16 bit word
 		//tagName_alignment := tag_hdr.NameLength % 2
@@ -174,7 +378,7 @@ func (client *Client) ListAllTags(start_instance uint32) error {
 		//}
 		err = binary.Read(data2, binary.LittleEndian, tag_ftr)
 		if err != nil {
-			return fmt.Errorf("problem reading tag footer. %w", err)
+			return nil, false, fmt.Errorf("problem reading tag footer. %w", err)
 		}
 
 		kt := KnownTag{
@@ -182,75 +386,31 @@ func (client *Client) ListAllTags(start_instance uint32) error {
 			Info:     *tag_ftr,
 			Instance: CIPInstance(tag_hdr.InstanceID),
 		}
-		if tag_ftr.Dimension3 != 0 {
-			kt.Array_Order = make([]int, 3)
-			kt.Array_Order[2] = int(tag_ftr.Dimension3)
-			kt.Array_Order[1] = int(tag_ftr.Dimension2)
-			kt.Array_Order[0] = int(tag_ftr.Dimension1)
-		} else if tag_ftr.Dimension2 != 0 {
-			kt.Array_Order = make([]int, 2)
-			kt.Array_Order[1] = int(tag_ftr.Dimension2)
-			kt.Array_Order[0] = int(tag_ftr.Dimension1)
-		} else if tag_ftr.Dimension1 != 0 {
-			kt.Array_Order = make([]int, 1)
-			kt.Array_Order[0] = int(tag_ftr.Dimension1)
-		} else {
-			kt.Array_Order = make([]int, 0)
-		}
-
-		if !isValidTag(tag_string, *tag_ftr) {
-			continue
-		}
-
-		if len(tag_string) > 8 {
-			if strings.HasPrefix(tag_string, "Program:") {
-				// we have a program scoped tag.  These are read separately.
-				continue
-			}
-		}
-
-		if tag_ftr.Template_ID() != 0 {
-			client.Logger.Debug("found UDT of some sort", "name", kt.Name)
-		}
-
-		if tag_ftr.Template_ID() != 0 { //&& !tag_ftr.PreDefined() {
-			client.Logger.Debug("Looking up template", "tag name", tag_string)
-			u, err := client.ListMembers(uint32(tag_ftr.Template_ID()))
-			if err != nil {
-				client.Logger.Error("problem reading member list",
-					"string", tag_string,
-					"header", tag_hdr,
-					"footer", tag_ftr,
-					"template ID", tag_ftr.Template_ID(),
-					"predefined", tag_ftr.PreDefined())
-				//return err
-			} else {
-				kt.UDT = &u
-				client.Logger.Debug("Successful member read for %s", "name", kt.Name)
-				client.KnownTypes[u.Name] = u
-			}
-		}
-
-		client.KnownTags[strings.ToLower(string(tag_name))] = kt
-
-		start_instance = tag_hdr.InstanceID
-
+		kt.Array_Order = tag_ftr.arrayOrder()
+		tags = append(tags, kt)
 	}
+	return tags, more, nil
+}
 
-	if data_hdr.Status == uint16(CIPStatus_PartialTransfer) { //} && start_instance < 200 {
-		err = client.ListAllTags(start_instance)
-		if err != nil {
-			return err
+// whether a listed tag is a controller scoped tag that can be read and written.  Program scoped tags show up in the
+// controller's list too but they are read separately with ListSubTags.
+func isControllerTag(tag_string string, tag_ftr TagInfo) bool {
+	if !isValidTag(tag_string, tag_ftr) {
+		return false
+	}
+	if len(tag_string) > 8 {
+		if strings.HasPrefix(tag_string, "Program:") {
+			// we have a program scoped tag.  These are read separately.
+			return false
 		}
 	}
-
-	return nil
+	return true
 }
 
 // per 1756-PM020H-EN-P page 43 there are some conditions in which we should discard the tags
 // because they aren't valid for reading/writing.
 func isValidTag(tag_string string, tag_ftr TagInfo) bool {
-	if tag_string[:2] == "__" {
+	if strings.HasPrefix(tag_string, "__") {
 		return false
 	}
 	if strings.Contains(tag_string, ":") {
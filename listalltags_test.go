@@ -0,0 +1,229 @@
+package gologix
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseTypeDescriptor(t *testing.T) {
+	var tests = []struct {
+		word     uint16
+		typ      CIPType
+		isStruct bool
+		handle   uint16
+		dims     int
+	}{
+		{0x00C4, CIPTypeDINT, false, 0, 0},
+		{0x20C4, CIPTypeDINT, false, 0, 1},      // DINT[10]
+		{0x60CA, CIPTypeREAL, false, 0, 3},      // REAL[2,3,4]
+		{0x02C1, CIPTypeBOOL, false, 0, 0},      // BOOL at bit 2
+		{0x8FCE, CIPTypeStruct, true, 0xFCE, 0}, // STRING
+		{0xA123, CIPTypeStruct, true, 0x123, 1}, // an array of some UDT
+		{0xC456, CIPTypeStruct, true, 0x456, 2},
+	}
+	for _, tt := range tests {
+		typ, isStruct, handle, dims := ParseTypeDescriptor(tt.word)
+		if typ != tt.typ || isStruct != tt.isStruct || handle != tt.handle || dims != tt.dims {
+			t.Errorf("%#04x: wanted %v %v %#x %d got %v %v %#x %d", tt.word, tt.typ, tt.isStruct, tt.handle, tt.dims, typ, isStruct, handle, dims)
+		}
+	}
+
+	info := TagInfo{Type: CIPTypeDINT, TypeInfo: 0x40, Dimension1: 2, Dimension2: 5}
+	if info.Dimensions() != 2 {
+		t.Errorf("wanted 2 dimensions got %d", info.Dimensions())
+	}
+	order := info.arrayOrder()
+	if len(order) != 2 || order[0] != 2 || order[1] != 5 {
+		t.Errorf("wanted array order [2 5] got %v", order)
+	}
+	if len(TagInfo{Type: CIPTypeDINT}.arrayOrder()) != 0 {
+		t.Errorf("a tag that isn't an array shouldn't have an array order")
+	}
+}
+
+// a browse can stop after any page and pick back up from the instance it was given, even on a new connection.
+func TestListTagsFrom(t *testing.T) {
+	pages := []struct {
+		start uint16
+		reply []byte
+	}{
+		{1, symbolListReply(CIPStatus_PartialTransfer,
+			map[uint32]string{1: "Alpha", 2: "__hidden", 3: "Program:Main"},
+			[]uint32{1, 2, 3},
+			map[uint32]CIPType{1: CIPTypeDINT, 2: CIPTypeDINT, 3: CIPTypeDINT})},
+		{4, symbolListReply(CIPStatus_OK,
+			map[uint32]string{5: "Beta"},
+			[]uint32{5},
+			map[uint32]CIPType{5: CIPTypeREAL})},
+	}
+
+	next := uint16(1)
+	for i, page := range pages {
+		client, remote := newPipeClient(t)
+		go func() {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			dat := buf.Bytes()[20:]
+			path := dat[4 : 4+2*int(dat[3])]
+			if path[2] != 0x24 || uint16(path[3]) != page.start {
+				t.Errorf("page %d: wanted a request from instance %d. got path % X", i, page.start, path)
+			}
+			items := []CIPItem{
+				newItem(cipItem_ConnectionAddress, uint32(0)),
+				{Header: cipItemHeader{ID: cipItem_ConnectedData}},
+			}
+			items[1].Serialize(page.reply)
+			b, err := serializeItems(items)
+			if err != nil {
+				t.Errorf("problem building reply: %v", err)
+				return
+			}
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}()
+
+		var tags []KnownTag
+		var err error
+		tags, next, err = client.ListTagsFrom(next)
+		if err != nil {
+			t.Fatalf("page %d: problem listing tags: %v", i, err)
+		}
+		if len(tags) != 1 {
+			t.Fatalf("page %d: wanted one controller tag. got %v", i, tags)
+		}
+		if _, ok := client.KnownTags[strings.ToLower(tags[0].Name)]; !ok {
+			t.Errorf("page %d: %s should be in KnownTags", i, tags[0].Name)
+		}
+	}
+	if next != 0 {
+		t.Errorf("wanted next to be 0 after the last page. got %d", next)
+	}
+}
+
+// ListAllTagsInfo follows the partial transfers to the end and returns every controller tag.
+func TestListAllTagsInfo(t *testing.T) {
+	client, remote := newPipeClient(t)
+	replies := [][]byte{
+		symbolListReply(CIPStatus_PartialTransfer,
+			map[uint32]string{1: "Alpha", 2: "__hidden", 3: "Program:Main"},
+			[]uint32{1, 2, 3},
+			map[uint32]CIPType{1: CIPTypeDINT, 2: CIPTypeDINT, 3: CIPTypeDINT}),
+		symbolListReply(CIPStatus_OK,
+			map[uint32]string{5: "Beta"},
+			[]uint32{5},
+			map[uint32]CIPType{5: CIPTypeREAL}),
+	}
+	go func() {
+		for i, reply := range replies {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				t.Errorf("page %d: problem reading request: %v", i, err)
+				return
+			}
+			dat := buf.Bytes()[20:]
+			path := dat[4 : 4+2*int(dat[3])]
+			if want := []byte{1, 4}[i]; path[3] != want {
+				t.Errorf("page %d: wanted a request from instance %d. got path % X", i, want, path)
+			}
+			items := []CIPItem{
+				newItem(cipItem_ConnectionAddress, uint32(0)),
+				{Header: cipItemHeader{ID: cipItem_ConnectedData}},
+			}
+			items[1].Serialize(reply)
+			b, err := serializeItems(items)
+			if err != nil {
+				t.Errorf("problem building reply: %v", err)
+				return
+			}
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}
+	}()
+
+	tags, err := client.ListAllTagsInfo()
+	if err != nil {
+		t.Fatalf("problem listing tags: %v", err)
+	}
+	if len(tags) != 2 || tags[0].Name != "Alpha" || tags[1].Name != "Beta" {
+		t.Fatalf("wanted Alpha and Beta. got %v", tags)
+	}
+	if tags[0].Info.Type != CIPTypeDINT || tags[1].Info.Type != CIPTypeREAL {
+		t.Errorf("wanted a DINT and a REAL. got %v and %v", tags[0].Info.Type, tags[1].Info.Type)
+	}
+	if _, ok := client.KnownTags["beta"]; !ok {
+		t.Errorf("Beta should be in KnownTags")
+	}
+}
+
+func TestListTagsExternalAccess(t *testing.T) {
+	client, remote := newPipeClient(t)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		dat := buf.Bytes()[20:]
+		attrs := dat[4+2*int(dat[3]):]
+		want := []byte{4, 0, 1, 0, 2, 0, 8, 0, 10, 0}
+		if !bytes.Equal(attrs, want) {
+			t.Errorf("wanted attributes % X. got % X", want, attrs)
+		}
+		items := []CIPItem{
+			newItem(cipItem_ConnectionAddress, uint32(0)),
+			{Header: cipItemHeader{ID: cipItem_ConnectedData}},
+		}
+		items[1].Serialize(symbolListReplyAccess(CIPStatus_OK,
+			map[uint32]string{1: "Open", 2: "Locked", 3: "Hidden"},
+			[]uint32{1, 2, 3},
+			map[uint32]CIPType{1: CIPTypeDINT, 2: CIPTypeDINT, 3: CIPTypeDINT},
+			map[uint32]ExternalAccess{2: ExternalAccessReadOnly, 3: ExternalAccessNone}))
+		b, err := serializeItems(items)
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	_, _, err := client.ListTagsFrom(1)
+	if err != nil {
+		t.Fatalf("problem listing tags: %v", err)
+	}
+	var tests = []struct {
+		tag      string
+		access   ExternalAccess
+		readable bool
+		writable bool
+	}{
+		{"open", ExternalAccessReadWrite, true, true},
+		{"locked", ExternalAccessReadOnly, true, false},
+		{"hidden", ExternalAccessNone, false, false},
+	}
+	for _, tt := range tests {
+		info := client.KnownTags[tt.tag].Info
+		if info.Type != CIPTypeDINT || info.ExternalAccess != tt.access {
+			t.Errorf("%s: wanted a DINT with %v access. got %+v", tt.tag, tt.access, info)
+		}
+		if info.ExternalAccess.Readable() != tt.readable || info.ExternalAccess.Writable() != tt.writable {
+			t.Errorf("%s: wanted readable %v writable %v", tt.tag, tt.readable, tt.writable)
+		}
+	}
+
+	// nothing is listening now so these have to fail before sending.
+	for _, tag := range []string{"Locked", "Hidden", "Locked.1"} {
+		err = client.Write(tag, int32(1))
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s: wanted ErrReadOnly. got %v", tag, err)
+		}
+	}
+	err = client.WriteMulti(struct {
+		V int32 `gologix:"Locked"`
+	}{1})
+	if !errors.Is(err, ErrReadOnly) {
+		t.Errorf("wanted ErrReadOnly from a multiple write. got %v", err)
+	}
+}
@@ -138,6 +138,8 @@ func (m msgMemberInfo) CIPType() CIPType {
 // Per my testing, this only works after a certain firmware version.  I don't know which one
 // exactly, but V32 it works and V20 it does not.  I suspect v24 or v28 since they were pretty substantial
 // changes, but v21 could also be the version since that is the swap from rslogix to studio
+//
+// Templates that don't fit in one reply are read in pieces with successive reads at increasing offsets.
 func (client *Client) ListMembers(str_instance uint32) (UDTDescriptor, error) {
 	client.Logger.Debug("list members", "instance", str_instance)
 
@@ -147,16 +149,51 @@ func (client *Client) ListMembers(str_instance uint32) (UDTDescriptor, error) {
 		return UDTDescriptor{}, fmt.Errorf("couldn't get template info. %w", err)
 	}
 
+	// this is the size of the template definition per page 53 of 1756-PM020H-EN-P
+	total := template_info.SizeWords*4 - 23
+	dat, err := readTemplateChunks(total, func(offset uint32, length uint16) (CIPStatus, []byte, error) {
+		return client.readTemplateChunk(str_instance, offset, length)
+	})
+	if err != nil {
+		return UDTDescriptor{}, err
+	}
+
+	return parseTemplate(str_instance, template_info, dat)
+}
+
+// keep reading pieces of the template definition until the controller stops reporting a partial transfer.
+func readTemplateChunks(total uint32, fetch func(offset uint32, length uint16) (CIPStatus, []byte, error)) ([]byte, error) {
+	dat := make([]byte, 0, total)
+	for uint32(len(dat)) < total {
+		offset := uint32(len(dat))
+		status, chunk, err := fetch(offset, uint16(total-offset))
+		if err != nil {
+			return nil, fmt.Errorf("problem reading template at offset %d: %w", offset, err)
+		}
+		if status != CIPStatus_OK && status != CIPStatus_PartialTransfer {
+			return nil, fmt.Errorf("problem reading template at offset %d: status 0x%X (%v)", offset, byte(status), status)
+		}
+		dat = append(dat, chunk...)
+		if status == CIPStatus_OK {
+			break
+		}
+		if len(chunk) == 0 {
+			return nil, fmt.Errorf("partial transfer at offset %d returned no data", offset)
+		}
+	}
+	return dat, nil
+}
+
+// read length bytes of the template definition starting at offset.
+func (client *Client) readTemplateChunk(str_instance uint32, offset uint32, length uint16) (CIPStatus, []byte, error) {
 	reqitems := make([]CIPItem, 2)
-	//reqitems[0] = cipItem{Header: cipItemHeader{ID: cipItem_Null}}
 	reqitems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
 
 	p, err := Serialize(
 		CipObject_Template, CIPInstance(str_instance),
-		//cipObject_Symbol, cipInstance(start_instance),
 	)
 	if err != nil {
-		return UDTDescriptor{}, fmt.Errorf("couldn't build path. %w", err)
+		return 0, nil, fmt.Errorf("couldn't build path. %w", err)
 	}
 
 	readmsg := msgCIPConnectedServiceReq{
@@ -167,47 +204,50 @@ func (client *Client) ListMembers(str_instance uint32) (UDTDescriptor, error) {
 
 	reqitems[1] = newItem(cipItem_ConnectedData, readmsg)
 	reqitems[1].Serialize(p.Bytes())
-	start_offset := uint32(0)
-	read_length := uint16(template_info.SizeWords*4 - 23)
-	reqitems[1].Serialize(start_offset)
-	reqitems[1].Serialize(read_length)
+	reqitems[1].Serialize(offset)
+	reqitems[1].Serialize(length)
 
 	itemdata, err := serializeItems(reqitems)
 	if err != nil {
-		return UDTDescriptor{}, fmt.Errorf("problem serializing item data: %w", err)
+		return 0, nil, fmt.Errorf("problem serializing item data: %w", err)
 	}
-	hdr, data, err := client.send_recv_data(cipCommandSendUnitData, itemdata)
+	_, data, err := client.send_recv_data(cipCommandSendUnitData, itemdata)
 	if err != nil {
-		return UDTDescriptor{}, err
+		return 0, nil, err
 	}
-	_ = hdr
-	_ = data
-	//data_hdr := ListInstanceHeader{}
-	//binary.Read(data, binary.LittleEndian, &data_hdr)
 
 	// first six bytes are zero.
 	padding := make([]byte, 6)
 	_, err = data.Read(padding)
 	if err != nil {
-		return UDTDescriptor{}, fmt.Errorf("couldn't read padding. %w", err)
+		return 0, nil, fmt.Errorf("couldn't read padding. %w", err)
 	}
 
 	resp_items, err := readItems(data)
 	if err != nil {
-		return UDTDescriptor{}, fmt.Errorf("couldn't parse items. %w", err)
+		return 0, nil, fmt.Errorf("couldn't parse items. %w", err)
+	}
+	if len(resp_items) < 2 {
+		return 0, nil, fmt.Errorf("expected 2 items in the response. got %d", len(resp_items))
 	}
 
-	// get ready to read tag info from item 1 data
 	data2 := bytes.NewBuffer(resp_items[1].Data)
-
 	mihdr := msgMemberInfoHdr{}
 	err = binary.Read(data2, binary.LittleEndian, &mihdr)
 	if err != nil {
-		return UDTDescriptor{}, fmt.Errorf("couldn't read member info header. %w", err)
+		return 0, nil, fmt.Errorf("couldn't read member info header. %w", err)
 	}
 
+	return CIPStatus(mihdr.Status & 0xFF), data2.Bytes(), nil
+}
+
+// parse a template definition.  The member infos come first, then the structure name up to a ';',
+// then a null terminated string we don't use, then the null terminated member names.
+func parseTemplate(str_instance uint32, template_info msgGetTemplateAttrListResponse, dat []byte) (UDTDescriptor, error) {
+	data2 := bytes.NewBuffer(dat)
+
 	memberInfos := make([]msgMemberInfo, template_info.MemberCount)
-	err = binary.Read(data2, binary.LittleEndian, &memberInfos)
+	err := binary.Read(data2, binary.LittleEndian, &memberInfos)
 	if err != nil {
 		return UDTDescriptor{}, fmt.Errorf("couldn't read memberinfos. %w", err)
 	}
@@ -253,18 +293,51 @@ type UDTDescriptor struct {
 	Members     []UDTMemberDescriptor
 }
 
-// This function is experimental and not accurate.  I suspect it is accurate only if the last field in the
-// udt is a simple atomic type (int, real, dint, etc...).  Use at your own risk.
+// The size of the structure in bytes, including padding.
+//
+// This is the template's definition size when it is known (it is for anything from ListMembers or GetTemplate).
+// Otherwise it is worked out from the end of the last member, which is only a guess when the last member is itself a
+// structure.
 func (u UDTDescriptor) Size() int {
-	maxsize := uint32(0)
+	if u.Info.SizeBytes > 0 {
+		return int(u.Info.SizeBytes)
+	}
+	maxsize := 0
 	for i := range u.Members {
 		m := u.Members[i]
-		end := m.Info.Offset + uint32(m.Info.CIPType().Size())
-		if end > maxsize {
-			maxsize = end
+		if m.Info.Type&memberTypeStruct != 0 {
+			// we don't have the nested template so all we know is where it starts.
+			maxsize = max(maxsize, int(m.Info.Offset))
+			continue
+		}
+		size := m.Info.CIPType().Size()
+		if m.Info.Type&memberTypeArray != 0 {
+			size *= int(m.Info.Info)
+		}
+		maxsize = max(maxsize, int(m.Info.Offset)+size)
+	}
+	return alignTo(maxsize, u.Align())
+}
+
+// The alignment of the structure: 8 if any atomic member is 64 bits and 4 otherwise.  Nested structures aren't
+// looked at since that needs their templates.
+func (u UDTDescriptor) Align() int {
+	a := 4
+	for _, m := range u.Members {
+		if m.Info.Type&memberTypeStruct != 0 {
+			continue
 		}
+		a = max(a, m.Info.CIPType().Align())
+	}
+	return a
+}
+
+// round n up to the next multiple of align.
+func alignTo(n, align int) int {
+	if align <= 1 {
+		return n
 	}
-	return int(maxsize)
+	return (n + align - 1) / align * align
 }
 
 type UDTMemberDescriptor struct {
@@ -0,0 +1,117 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func testTemplateDefinition() []byte {
+	b := bytes.Buffer{}
+	binary.Write(&b, binary.LittleEndian, []msgMemberInfo{
+		{Info: 0, Type: uint16(CIPTypeDINT), Offset: 0},
+		{Info: 3, Type: 0x2000 | uint16(CIPTypeREAL), Offset: 4},
+	})
+	b.WriteString("MyUDT;n\x00")
+	b.WriteString("Count\x00")
+	b.WriteString("Temps\x00")
+	return b.Bytes()
+}
+
+func TestParseTemplate(t *testing.T) {
+	info := msgGetTemplateAttrListResponse{MemberCount: 2, SizeBytes: 16, Handle: 0x1234}
+	desc, err := parseTemplate(55, info, testTemplateDefinition())
+	if err != nil {
+		t.Fatalf("problem parsing template: %v", err)
+	}
+	if desc.Name != "MyUDT" || desc.Instance_ID != 55 || desc.Info.Handle != 0x1234 {
+		t.Errorf("bad template header: %+v", desc)
+	}
+	if len(desc.Members) != 2 {
+		t.Fatalf("wanted 2 members got %d", len(desc.Members))
+	}
+	if desc.Members[0].Name != "Count" || desc.Members[0].Info.CIPType() != CIPTypeDINT || desc.Members[0].Info.Offset != 0 {
+		t.Errorf("bad first member: %+v", desc.Members[0])
+	}
+	if desc.Members[1].Name != "Temps" || desc.Members[1].Info.CIPType() != CIPTypeREAL || desc.Members[1].Info.Offset != 4 {
+		t.Errorf("bad second member: %+v", desc.Members[1])
+	}
+
+	_, err = parseTemplate(55, info, testTemplateDefinition()[:20])
+	if err == nil {
+		t.Errorf("a truncated template should be an error")
+	}
+}
+
+func TestReadTemplateChunks(t *testing.T) {
+	def := testTemplateDefinition()
+	total := uint32(len(def))
+
+	// the "controller" only sends back 7 bytes at a time.
+	var offsets []uint32
+	fetch := func(offset uint32, length uint16) (CIPStatus, []byte, error) {
+		offsets = append(offsets, offset)
+		if uint32(length) != total-offset {
+			t.Errorf("offset %d: asked for %d bytes. wanted %d", offset, length, total-offset)
+		}
+		end := min(offset+7, total)
+		if end == total {
+			return CIPStatus_OK, def[offset:end], nil
+		}
+		return CIPStatus_PartialTransfer, def[offset:end], nil
+	}
+
+	have, err := readTemplateChunks(total, fetch)
+	if err != nil {
+		t.Fatalf("problem reading chunks: %v", err)
+	}
+	if !bytes.Equal(have, def) {
+		t.Errorf("wanted %v\ngot    %v", def, have)
+	}
+	if want := int(total+6) / 7; len(offsets) != want {
+		t.Errorf("wanted %d reads got %d (%v)", want, len(offsets), offsets)
+	}
+
+	_, err = readTemplateChunks(total, func(offset uint32, length uint16) (CIPStatus, []byte, error) {
+		return CIPStatus_PathSegmentError, nil, nil
+	})
+	if err == nil {
+		t.Errorf("an error status should be an error")
+	}
+
+	_, err = readTemplateChunks(total, func(offset uint32, length uint16) (CIPStatus, []byte, error) {
+		return CIPStatus_PartialTransfer, nil, nil
+	})
+	if err == nil {
+		t.Errorf("an empty partial transfer should be an error instead of looping forever")
+	}
+}
+
+func TestUDTDescriptorSize(t *testing.T) {
+	u := UDTDescriptor{
+		Members: []UDTMemberDescriptor{
+			{Name: "A", Info: msgMemberInfo{Type: uint16(CIPTypeSINT), Offset: 0}},
+			{Name: "B", Info: msgMemberInfo{Info: 3, Type: 0x2000 | uint16(CIPTypeINT), Offset: 2}},
+		},
+	}
+	// 2 + 3*2 is 8 which is already aligned to 4
+	if have := u.Size(); have != 8 {
+		t.Errorf("wanted 8 got %d", have)
+	}
+
+	u.Members = append(u.Members, UDTMemberDescriptor{Name: "C", Info: msgMemberInfo{Type: uint16(CIPTypeLREAL), Offset: 8}})
+	u.Members = append(u.Members, UDTMemberDescriptor{Name: "D", Info: msgMemberInfo{Type: uint16(CIPTypeSINT), Offset: 16}})
+	// ends at 17 and the LREAL makes it 8 byte aligned
+	if u.Align() != 8 {
+		t.Errorf("wanted alignment 8 got %d", u.Align())
+	}
+	if have := u.Size(); have != 24 {
+		t.Errorf("wanted 24 got %d", have)
+	}
+
+	// the template's size wins when we have it
+	u.Info.SizeBytes = 32
+	if have := u.Size(); have != 32 {
+		t.Errorf("wanted the template size 32 got %d", have)
+	}
+}
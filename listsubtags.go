@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"log"
 	"strings"
 )
 
@@ -43,11 +42,13 @@ func (client *Client) ListSubTags(Program *KnownProgram, start_instance uint32)
 
 	reqitems[1] = newItem(cipItem_ConnectedData, readmsg)
 	reqitems[1].Serialize(p.Bytes())
-	number_of_attr_to_receive := 3
+	// the external access has to come last to line up with the end of TagInfo.
+	number_of_attr_to_receive := 4
 	attr1_symbol_name := 1
 	attr2_symbol_type := 2
 	attr8_arraydims := 8
-	reqitems[1].Serialize([4]uint16{uint16(number_of_attr_to_receive), uint16(attr1_symbol_name), uint16(attr2_symbol_type), uint16(attr8_arraydims)})
+	attr10_externalaccess := 10
+	reqitems[1].Serialize([5]uint16{uint16(number_of_attr_to_receive), uint16(attr1_symbol_name), uint16(attr2_symbol_type), uint16(attr8_arraydims), uint16(attr10_externalaccess)})
 
 	itemdata, err := serializeItems(reqitems)
 	if err != nil {
@@ -89,6 +90,7 @@ func (client *Client) ListSubTags(Program *KnownProgram, start_instance uint32)
 		if err != nil {
 			return nil, fmt.Errorf("problem reading tag header. %w", err)
 		}
+		start_instance = tag_hdr.InstanceID
 		newtag_bytes := make([]byte, tag_hdr.NameLength)
 		err = binary.Read(data2, binary.LittleEndian, &newtag_bytes)
 		if err != nil {
@@ -106,43 +108,25 @@ func (client *Client) ListSubTags(Program *KnownProgram, start_instance uint32)
 			return nil, fmt.Errorf("problem reading tag footer. %w", err)
 		}
 
-		if strings.ToLower(newtag_name) == "program:gologix_tests.testtimer" {
-			log.Printf("found it.")
-		}
-
 		kt := KnownTag{
 			Name:     newtag_name,
 			Info:     *tag_ftr,
 			Instance: CIPInstance(tag_hdr.InstanceID),
 			Parent:   Program,
 		}
-		if tag_ftr.Dimension3 != 0 {
-			kt.Array_Order = make([]int, 3)
-			kt.Array_Order[2] = int(tag_ftr.Dimension3)
-			kt.Array_Order[1] = int(tag_ftr.Dimension2)
-			kt.Array_Order[0] = int(tag_ftr.Dimension1)
-		} else if tag_ftr.Dimension2 != 0 {
-			kt.Array_Order = make([]int, 2)
-			kt.Array_Order[1] = int(tag_ftr.Dimension2)
-			kt.Array_Order[0] = int(tag_ftr.Dimension1)
-		} else if tag_ftr.Dimension1 != 0 {
-			kt.Array_Order = make([]int, 1)
-			kt.Array_Order[0] = int(tag_ftr.Dimension1)
-		} else {
-			kt.Array_Order = make([]int, 0)
-		}
+		kt.Array_Order = tag_ftr.arrayOrder()
 		if !isValidTag(string(newtag_bytes), *tag_ftr) {
 			continue
 		}
 		client.KnownTags[strings.ToLower(newtag_name)] = kt
 		new_kts = append(new_kts, kt)
 
-		start_instance = tag_hdr.InstanceID
-
 	}
 
 	if data_hdr.Status == uint16(CIPStatus_PartialTransfer) {
-		_, err = client.ListSubTags(Program, start_instance)
+		// pick up after the last instance we got.
+		more, err := client.ListSubTags(Program, start_instance+1)
+		new_kts = append(new_kts, more...)
 		if err != nil {
 			return new_kts, fmt.Errorf("problem listing subtags. %w", err)
 		}
@@ -150,3 +134,44 @@ func (client *Client) ListSubTags(Program *KnownProgram, start_instance uint32)
 
 	return new_kts, nil
 }
+
+// List the tags in a program by name.  The "Program:" prefix is optional.
+//
+// The tags are also added to KnownTags under their full "program:name.tag" name.
+func (client *Client) ListProgramTags(program string) ([]KnownTag, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return nil, fmt.Errorf("could not start program tag list: %w", err)
+	}
+	p, ok := client.findProgram(program)
+	if !ok {
+		// we may just not have listed the programs yet.
+		err = client.ListAllPrograms()
+		if err != nil {
+			return nil, fmt.Errorf("problem listing programs: %w", err)
+		}
+		p, ok = client.findProgram(program)
+		if !ok {
+			return nil, fmt.Errorf("program %s not found", program)
+		}
+	}
+	return client.ListSubTags(p, 1)
+}
+
+// look up a program in KnownPrograms ignoring case and the "Program:" prefix.
+func (client *Client) findProgram(program string) (*KnownProgram, bool) {
+	program = trimProgramPrefix(program)
+	for name, p := range client.KnownPrograms {
+		if strings.EqualFold(trimProgramPrefix(name), program) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func trimProgramPrefix(name string) string {
+	if strings.HasPrefix(strings.ToLower(name), "program:") {
+		return name[len("program:"):]
+	}
+	return name
+}
@@ -0,0 +1,28 @@
+package gologix
+
+import "testing"
+
+func TestFindProgram(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	main := &KnownProgram{ID: 3, Name: "Program:MainProgram"}
+	client.KnownPrograms = map[string]*KnownProgram{main.Name: main}
+
+	for _, name := range []string{"MainProgram", "mainprogram", "Program:MainProgram", "PROGRAM:mainprogram"} {
+		p, ok := client.findProgram(name)
+		if !ok || p != main {
+			t.Errorf("%s: should have found the program", name)
+		}
+	}
+	if _, ok := client.findProgram("Other"); ok {
+		t.Errorf("shouldn't have found a program that doesn't exist")
+	}
+}
+
+func TestTagInfoStruct(t *testing.T) {
+	if !(TagInfo{Type: 0xCE, TypeInfo: 0x8F}).Struct() {
+		t.Errorf("bit 15 set should be a struct")
+	}
+	if (TagInfo{Type: CIPTypeDINT}).Struct() {
+		t.Errorf("DINT shouldn't be a struct")
+	}
+}
@@ -41,12 +41,26 @@ type Logger struct {
 	internalLogger *slog.Logger
 }
 
+// NewLogger gives a logger that writes to slog's default logger.
 func NewLogger() LoggerInterface {
 	return &Logger{
 		internalLogger: slog.Default(),
 	}
 }
 
+// NewSlogLogger gives a logger that writes to logger.  Use this to send the library's diagnostics to your own
+// handler, such as a JSON one.
+func NewSlogLogger(logger *slog.Logger) LoggerInterfaceWith {
+	return &Logger{
+		internalLogger: logger,
+	}
+}
+
+// NewNopLogger gives a logger that throws everything away.  This is what clients use unless you set Client.Logger.
+func NewNopLogger() LoggerInterfaceWith {
+	return nopLogger{}
+}
+
 func (l *Logger) SetLogger(logger *slog.Logger) {
 	l.internalLogger = logger
 }
@@ -72,3 +86,12 @@ func (l *Logger) Warn(msg string, args ...any) {
 func (l *Logger) Info(msg string, args ...any) {
 	l.internalLogger.Info(msg, args...)
 }
+
+// nopLogger implements LoggerInterfaceWith and discards everything.
+type nopLogger struct{}
+
+func (nopLogger) With(args ...any) LoggerInterfaceWith { return nopLogger{} }
+func (nopLogger) Debug(msg string, args ...any)        {}
+func (nopLogger) Error(msg string, args ...any)        {}
+func (nopLogger) Warn(msg string, args ...any)         {}
+func (nopLogger) Info(msg string, args ...any)         {}
@@ -0,0 +1,30 @@
+package gologix
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDefaultLoggerIsSilent(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	if _, ok := client.Logger.(nopLogger); !ok {
+		t.Errorf("clients should default to the no-op logger. got %T", client.Logger)
+	}
+	cl, ok := client.Logger.(LoggerInterfaceWith)
+	if !ok {
+		t.Fatalf("the no-op logger should support With")
+	}
+	cl.With("controllerIp", "127.0.0.1").Error("nothing to see here")
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	l.With("controllerIp", "10.0.0.1").Warn("problem", "tag", "MyDint")
+	out := buf.String()
+	if !strings.Contains(out, `"controllerIp":"10.0.0.1"`) || !strings.Contains(out, `"tag":"MyDint"`) {
+		t.Errorf("expected contextual JSON output. got %s", out)
+	}
+}
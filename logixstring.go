@@ -0,0 +1,255 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// Read a STRING tag.
+//
+// The LEN field of the string structure is used to trim DATA so embedded nulls are kept.  Custom string types with
+// a capacity other than 82 can be read too since only LEN bytes of DATA are used.
+//
+// If the tag is in KnownTags (see ListAllTags) the layout of the string comes from its template so string types with
+// a LEN other than a DINT or with DATA of 2 byte UTF-16 characters read right too.  See ReadStringTemplate.
+func (client *Client) ReadString(tag string) (string, error) {
+	base, _ := splitTagBase(tag)
+	if _, ok := client.KnownTags[strings.ToLower(base)]; ok {
+		t, err := client.templateForTag(tag)
+		if err == nil {
+			return client.ReadStringTemplate(tag, t)
+		}
+	}
+	err := client.checkConnection()
+	if err != nil {
+		return "", fmt.Errorf("could not start string read: %w", err)
+	}
+	dat, err := client.readStringData(tag)
+	if err != nil {
+		return "", err
+	}
+	s, err := parseLogixStringOrder(dat, client.byteOrder())
+	if err != nil {
+		return "", fmt.Errorf("problem reading string %s: %w", tag, err)
+	}
+	return s, nil
+}
+
+// Read a string tag whose type has template t (see GetTemplate).  LEN can be any integer type and DATA an array of
+// SINTs or USINTs for 1 byte characters or of INTs or UINTs for UTF-16 characters, which are decoded into the go
+// string.  LEN counts characters, not bytes.
+func (client *Client) ReadStringTemplate(tag string, t Template) (string, error) {
+	layout, err := newStringLayout(t)
+	if err != nil {
+		return "", err
+	}
+	err = client.checkConnection()
+	if err != nil {
+		return "", fmt.Errorf("could not start string read: %w", err)
+	}
+	dat, err := client.readStringData(tag)
+	if err != nil {
+		return "", err
+	}
+	s, err := layout.parse(dat, client.byteOrder())
+	if err != nil {
+		return "", fmt.Errorf("problem reading string %s: %w", tag, err)
+	}
+	return s, nil
+}
+
+// read the raw structure data of a string tag.
+func (client *Client) readStringData(tag string) ([]byte, error) {
+	val, err := client.Read_single(tag, CIPTypeStruct, 1)
+	if err != nil {
+		return nil, err
+	}
+	dat, ok := val.([]byte)
+	if !ok {
+		return nil, typeMismatch(tag, val, CIPTypeSTRING)
+	}
+	return dat, nil
+}
+
+// Write s to a STRING tag.  LEN is set to the length of s and the rest of DATA is zeroed.
+//
+// If the tag is in KnownTags (see ListAllTags) the string type comes from its template so custom string types like a
+// STRING20 get the right DATA size.  This works for strings that are members of a UDT too.  Tags that aren't known,
+// or are known without a template, are written as the builtin STRING with room for 82 characters.  A string longer
+// than the type holds is an error.
+func (client *Client) WriteString(tag string, s string) error {
+	base, _ := splitTagBase(tag)
+	if known, ok := client.KnownTags[strings.ToLower(base)]; !ok || known.UDT == nil {
+		return Write(client, tag, s)
+	}
+	err := client.checkConnection()
+	if err != nil {
+		return fmt.Errorf("could not start string write: %w", err)
+	}
+	t, err := client.templateForTag(tag)
+	if err != nil {
+		return fmt.Errorf("problem finding the string type of %s: %w", tag, err)
+	}
+	return client.WriteStringTemplate(tag, s, t)
+}
+
+// Write s to a string tag whose type has template t (see GetTemplate).  Use this for custom string types when the tag
+// isn't in KnownTags.
+func (client *Client) WriteStringTemplate(tag string, s string, t Template) error {
+	capacity, err := stringCapacity(t)
+	if err != nil {
+		return err
+	}
+	if len(s) > capacity {
+		return fmt.Errorf("string is %d characters. %s can only hold %d", len(s), t.Name, capacity)
+	}
+	err = client.checkConnection()
+	if err != nil {
+		return fmt.Errorf("could not start string write: %w", err)
+	}
+	value := logixString{Value: s, Capacity: capacity, Handle: t.Info.Handle}
+	return client.withRetry(tag, func() error {
+		return client.write_udt(tag, value, 0)
+	})
+}
+
+// the number of characters a string type holds.  A string type is a DINT LEN and a SINT array DATA.
+func stringCapacity(t Template) (int, error) {
+	lenMember, ok := t.member("LEN")
+	if !ok || lenMember.Info.CIPType() != CIPTypeDINT || lenMember.Info.Type&memberTypeArray != 0 {
+		return 0, fmt.Errorf("%s isn't a string type. it has no DINT LEN", t.Name)
+	}
+	data, ok := t.member("DATA")
+	if !ok || data.Info.CIPType() != CIPTypeSINT || data.Info.Type&memberTypeArray == 0 {
+		return 0, fmt.Errorf("%s isn't a string type. it has no SINT array DATA", t.Name)
+	}
+	return int(data.Info.Info), nil
+}
+
+// parse the LEN + DATA structure of a string.  dat should start at LEN.
+func parseLogixString(dat []byte) (string, error) {
+	return parseLogixStringOrder(dat, binary.LittleEndian)
+}
+
+// parseLogixString for a LEN in the given byte order.  The characters are the same either way.
+func parseLogixStringOrder(dat []byte, order binary.ByteOrder) (string, error) {
+	if len(dat) < 4 {
+		return "", fmt.Errorf("need at least 4 bytes for LEN. got %d", len(dat))
+	}
+	l := int32(order.Uint32(dat))
+	if l < 0 || int(l) > len(dat)-4 {
+		return "", fmt.Errorf("LEN of %d doesn't fit in %d bytes of DATA", l, len(dat)-4)
+	}
+	return string(dat[4 : 4+l]), nil
+}
+
+// where the LEN and DATA of a string type are and what they hold, from its template.
+type stringLayout struct {
+	lenOffset  int
+	lenSize    int // bytes
+	dataOffset int
+	charSize   int // bytes.  2 for UTF-16
+	capacity   int // characters
+}
+
+// the layout of a string type.  LEN has to be an integer and DATA an array of 1 or 2 byte integers.
+func newStringLayout(t Template) (stringLayout, error) {
+	lenMember, ok := t.member("LEN")
+	if !ok || lenMember.Info.Type&(memberTypeArray|memberTypeStruct) != 0 || !isIntegerWord(lenMember.Info.CIPType()) {
+		return stringLayout{}, fmt.Errorf("%s isn't a string type. it has no integer LEN", t.Name)
+	}
+	data, ok := t.member("DATA")
+	if !ok || data.Info.Type&memberTypeStruct != 0 || data.Info.Type&memberTypeArray == 0 {
+		return stringLayout{}, fmt.Errorf("%s isn't a string type. it has no DATA array", t.Name)
+	}
+	layout := stringLayout{
+		lenOffset:  int(lenMember.Info.Offset),
+		lenSize:    lenMember.Info.CIPType().Size(),
+		dataOffset: int(data.Info.Offset),
+		capacity:   int(data.Info.Info),
+	}
+	switch data.Info.CIPType() {
+	case CIPTypeSINT, CIPTypeUSINT, CIPTypeBYTE:
+		layout.charSize = 1
+	case CIPTypeINT, CIPTypeUINT, CIPTypeWORD:
+		layout.charSize = 2
+	default:
+		return stringLayout{}, fmt.Errorf("%s isn't a string type. its DATA is %v", t.Name, data.Info.CIPType())
+	}
+	return layout, nil
+}
+
+// decode a string laid out per l from the start of the structure data dat.
+func (l stringLayout) parse(dat []byte, order binary.ByteOrder) (string, error) {
+	if len(dat) < l.lenOffset+l.lenSize {
+		return "", fmt.Errorf("need %d bytes for LEN. got %d", l.lenOffset+l.lenSize, len(dat))
+	}
+	var n uint64
+	switch l.lenSize {
+	case 1:
+		n = uint64(dat[l.lenOffset])
+	case 2:
+		n = uint64(order.Uint16(dat[l.lenOffset:]))
+	case 4:
+		n = uint64(order.Uint32(dat[l.lenOffset:]))
+	case 8:
+		n = order.Uint64(dat[l.lenOffset:])
+	}
+	if n > uint64(l.capacity) || l.dataOffset+int(n)*l.charSize > len(dat) {
+		return "", fmt.Errorf("LEN of %d doesn't fit the %d characters of DATA", n, l.capacity)
+	}
+	chars := dat[l.dataOffset : l.dataOffset+int(n)*l.charSize]
+	if l.charSize == 1 {
+		return string(chars), nil
+	}
+	units := make([]uint16, n)
+	for i := range units {
+		units[i] = order.Uint16(chars[i*2:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// number of characters in the DATA array of the builtin STRING type.
+const stringDataSize = 82
+
+// logixString is the LEN + DATA structure the controller uses for strings.  The zero Capacity and Handle are those of
+// the builtin STRING.  It packs itself so it can be written like any other UDT.
+type logixString struct {
+	Value    string
+	Capacity int    // number of characters in DATA
+	Handle   uint16 // structure handle of the string type
+}
+
+func (s logixString) capacity() int {
+	if s.Capacity == 0 {
+		return stringDataSize
+	}
+	return s.Capacity
+}
+
+func (s logixString) TypeAbbr() (string, uint16) {
+	if s.Handle == 0 {
+		return "STRING,DINT,SINT[82]", stringStructHandle
+	}
+	return fmt.Sprintf("STRING,DINT,SINT[%d]", s.capacity()), s.Handle
+}
+
+func (s logixString) Pack(w io.Writer) (int, error) {
+	return s.packOrder(w, binary.LittleEndian)
+}
+
+// Pack with LEN in the given byte order.
+func (s logixString) packOrder(w io.Writer, order binary.ByteOrder) (int, error) {
+	capacity := s.capacity()
+	if len(s.Value) > capacity {
+		return 0, fmt.Errorf("string is %d characters. the string type can only hold %d", len(s.Value), capacity)
+	}
+	// LEN, DATA, and padding to get back to DINT alignment.
+	b := make([]byte, alignTo(4+capacity, 4))
+	order.PutUint32(b, uint32(len(s.Value)))
+	copy(b[4:], s.Value)
+	return w.Write(b)
+}
@@ -0,0 +1,393 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// build a LEN + DATA structure with room for capacity characters.
+func testLogixString(length int32, data string, capacity int) []byte {
+	b := make([]byte, 4+capacity)
+	binary.LittleEndian.PutUint32(b, uint32(length))
+	copy(b[4:], data)
+	return b
+}
+
+func TestParseLogixString(t *testing.T) {
+	var tests = []struct {
+		name string
+		dat  []byte
+		want string
+		fail bool
+	}{
+		{"standard", testLogixString(5, "hello", 82), "hello", false},
+		{"empty", testLogixString(0, "", 82), "", false},
+		{"embedded nulls", testLogixString(5, "ab\x00\x00c", 82), "ab\x00\x00c", false},
+		{"trailing garbage ignored", testLogixString(2, "hello", 82), "he", false},
+		{"custom capacity", testLogixString(20, "abcdefghijklmnopqrst", 20), "abcdefghijklmnopqrst", false},
+		{"big custom capacity", testLogixString(100, string(bytes.Repeat([]byte{'x'}, 100)), 120), string(bytes.Repeat([]byte{'x'}, 100)), false},
+		{"len past data", testLogixString(21, "", 20), "", true},
+		{"negative len", testLogixString(-1, "", 82), "", true},
+		{"no len", []byte{1, 0}, "", true},
+	}
+
+	for _, tt := range tests {
+		have, err := parseLogixString(tt.dat)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("%s: expected an error. got %q", tt.name, have)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if have != tt.want {
+			t.Errorf("%s: wanted %q got %q", tt.name, tt.want, have)
+		}
+	}
+}
+
+func TestReadValueSTRING(t *testing.T) {
+	dat := append(testLogixString(3, "a\x00b", 82), 0, 0)
+	dat = append(dat, 0xAA) // start of whatever comes next
+	r := bytes.NewReader(dat)
+	v, err := readValue(CIPTypeSTRING, r)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if v != "a\x00b" {
+		t.Errorf("wanted %q got %q", "a\x00b", v)
+	}
+	if r.Len() != 1 {
+		t.Errorf("should have read exactly one STRING structure. %d bytes left", r.Len())
+	}
+}
+
+// the template of a custom string type with room for capacity characters.
+func testStringTemplate(name string, capacity int, handle uint16) Template {
+	return Template{
+		Name: name,
+		Info: msgGetTemplateAttrListResponse{Handle: handle, SizeBytes: uint32(alignTo(4+capacity, 4))},
+		Members: []UDTMemberDescriptor{
+			{Name: "LEN", Info: msgMemberInfo{Type: uint16(CIPTypeDINT)}},
+			{Name: "DATA", Info: msgMemberInfo{Info: uint16(capacity), Type: 0x2000 | uint16(CIPTypeSINT), Offset: 4}},
+		},
+	}
+}
+
+func TestLogixStringPackCapacity(t *testing.T) {
+	var tests = []struct {
+		name  string
+		value logixString
+		size  int
+		fail  bool
+	}{
+		{"builtin", logixString{Value: "abc"}, 88, false},
+		{"builtin full", logixString{Value: strings.Repeat("x", 82)}, 88, false},
+		{"builtin too long", logixString{Value: strings.Repeat("x", 83)}, 0, true},
+		{"string20", logixString{Value: "abc", Capacity: 20, Handle: 0x1234}, 24, false},
+		{"string20 full", logixString{Value: strings.Repeat("x", 20), Capacity: 20, Handle: 0x1234}, 24, false},
+		{"string20 too long", logixString{Value: strings.Repeat("x", 21), Capacity: 20, Handle: 0x1234}, 0, true},
+		{"padded", logixString{Value: "abc", Capacity: 5, Handle: 0x1234}, 12, false},
+	}
+	for _, tt := range tests {
+		b := bytes.Buffer{}
+		_, err := tt.value.Pack(&b)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if b.Len() != tt.size {
+			t.Errorf("%s: wanted %d bytes got %d", tt.name, tt.size, b.Len())
+		}
+		have, err := parseLogixString(b.Bytes())
+		if err != nil || have != tt.value.Value {
+			t.Errorf("%s: packed %q came back as %q (%v)", tt.name, tt.value.Value, have, err)
+		}
+	}
+}
+
+// a string written to a big endian controller has a big endian LEN and reads back the same.
+func TestWriteStringBigEndian(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.ByteOrder = binary.BigEndian
+	str20 := testStringTemplate("STRING20", 20, 0x1234)
+	client.KnownTags["msg"] = KnownTag{Name: "Msg", Info: TagInfo{Type: CIPTypeStruct}, UDT: &str20}
+
+	written := make(chan []byte, 1)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading write: %v", err)
+			return
+		}
+		req := buf.Bytes()
+		dat := append([]byte{}, req[len(req)-24:]...)
+		written <- dat
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgWriteResultHeader{Service: CIPService_Write.AsResponse()})
+		b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		if err != nil {
+			t.Errorf("problem building write reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *b)
+
+		hdr, _, err = recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading read: %v", err)
+			return
+		}
+		item = CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: CIPTypeStruct, Unknown: 2})
+		item.Serialize(uint16(0x1234)) // the structure handle
+		item.Serialize(dat)
+		b, err = serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		if err != nil {
+			t.Errorf("problem building read reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	err := client.WriteString("Msg", "hello")
+	if err != nil {
+		t.Fatalf("problem writing string: %v", err)
+	}
+	dat := <-written
+	if !check_bytes(dat[:4], []byte{0, 0, 0, 5}) {
+		t.Errorf("wanted a big endian LEN got %v", to_hex(dat[:4]))
+	}
+	have, err := client.ReadString("Msg")
+	if err != nil {
+		t.Fatalf("problem reading string: %v", err)
+	}
+	if have != "hello" {
+		t.Errorf("wanted hello got %q", have)
+	}
+}
+
+func TestStringCapacity(t *testing.T) {
+	capacity, err := stringCapacity(testStringTemplate("STRING20", 20, 0x1234))
+	if err != nil || capacity != 20 {
+		t.Errorf("wanted 20 got %d (%v)", capacity, err)
+	}
+	notString := Template{Name: "TIMER", Members: []UDTMemberDescriptor{{Name: "PRE", Info: msgMemberInfo{Type: uint16(CIPTypeDINT)}}}}
+	_, err = stringCapacity(notString)
+	if err == nil {
+		t.Errorf("a TIMER isn't a string type")
+	}
+}
+
+// a STRING20 member of a UDT should be written with its own handle and 24 bytes of data.
+func TestWriteStringCustomType(t *testing.T) {
+	client, remote := newPipeClient(t)
+	str20 := testStringTemplate("STRING20", 20, 0x1234)
+	str20.Instance_ID = 0x0123
+	client.KnownTypes["STRING20"] = str20
+	cfg := Template{Name: "Cfg", Members: []UDTMemberDescriptor{
+		{Name: "Msg", Info: msgMemberInfo{Type: memberTypeStruct | 0x0123}},
+	}}
+	client.KnownTags["cfg"] = KnownTag{Name: "Cfg", Info: TagInfo{Type: CIPTypeStruct}, UDT: &cfg}
+
+	requests := make(chan []byte, 1)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		requests <- buf.Bytes()[20:]
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgWriteResultHeader{Service: CIPService_Write.AsResponse()})
+		b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	err := client.WriteString("Cfg.Msg", strings.Repeat("y", 21))
+	if err == nil {
+		t.Fatalf("21 characters shouldn't fit in a STRING20")
+	}
+	err = client.WriteString("Cfg.Msg", "hello")
+	if err != nil {
+		t.Fatalf("problem writing string: %v", err)
+	}
+	req := <-requests
+	want := []byte{byte(CIPTypeStruct), 0x02, 0x34, 0x12, 0x01, 0x00, 0x05, 0x00, 0x00, 0x00, 'h', 'e', 'l', 'l', 'o'}
+	want = append(want, make([]byte, 15)...)
+	if !check_bytes(req[len(req)-len(want):], want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(req))
+	}
+}
+
+// a STRING tag listed without its template is written as the builtin STRING.
+func TestWriteStringNoTemplate(t *testing.T) {
+	client, remote := newPipeClient(t)
+	// the symbol type of a STRING is the structure bit and its handle.
+	client.KnownTags["msg"] = KnownTag{Name: "Msg", Info: TagInfo{Type: 0xCE, TypeInfo: 0x8F}}
+
+	requests := make(chan []byte, 1)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		requests <- buf.Bytes()[20:]
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgWriteResultHeader{Service: CIPService_Write.AsResponse()})
+		b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	err := client.WriteString("Msg", "hello")
+	if err != nil {
+		t.Fatalf("problem writing string: %v", err)
+	}
+	req := <-requests
+	want := []byte{byte(CIPTypeStruct), 0x02, 0xCE, 0x0F, 0x01, 0x00, 0x05, 0x00, 0x00, 0x00, 'h', 'e', 'l', 'l', 'o'}
+	want = append(want, make([]byte, 79)...)
+	if !check_bytes(req[len(req)-len(want):], want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(req))
+	}
+}
+
+// the template of a string type with the given LEN type and the DATA type as its element type and capacity.
+func testStringLayoutTemplate(lenType CIPType, dataType CIPType, capacity int) Template {
+	dataOffset := lenType.Size()
+	return Template{
+		Name: "WSTR",
+		Info: msgGetTemplateAttrListResponse{SizeBytes: uint32(alignTo(dataOffset+capacity*dataType.Size(), 4))},
+		Members: []UDTMemberDescriptor{
+			{Name: "LEN", Info: msgMemberInfo{Type: uint16(lenType)}},
+			{Name: "DATA", Info: msgMemberInfo{Info: uint16(capacity), Type: 0x2000 | uint16(dataType), Offset: uint32(dataOffset)}},
+		},
+	}
+}
+
+// the structure data of a string with LEN n followed by the characters in chars, padded out or cut off at size bytes.
+func testWideString(order binary.ByteOrder, lenType CIPType, n int, chars []uint16, size int) []byte {
+	dat := make([]byte, size)
+	switch lenType.Size() {
+	case 2:
+		order.PutUint16(dat, uint16(n))
+	case 4:
+		order.PutUint32(dat, uint32(n))
+	case 8:
+		order.PutUint64(dat, uint64(n))
+	}
+	for i, c := range chars {
+		if lenType.Size()+i*2+2 > size {
+			break
+		}
+		order.PutUint16(dat[lenType.Size()+i*2:], c)
+	}
+	return dat
+}
+
+func TestStringLayout(t *testing.T) {
+	wide := []uint16{'h', 0x00E9, 'y', 0xD83D, 0xDE00} // hé, y, and an emoji that takes a surrogate pair.
+	long := testStringLayoutTemplate(CIPTypeLINT, CIPTypeSINT, 10)
+	longDat := make([]byte, 20)
+	binary.LittleEndian.PutUint64(longDat, 3)
+	copy(longDat[8:], "abcdef")
+
+	var tests = []struct {
+		name  string
+		t     Template
+		dat   []byte
+		order binary.ByteOrder
+		want  string
+		fail  bool
+	}{
+		{"builtin", testStringTemplate("STRING", 82, 0), testLogixString(5, "hello", 82), binary.LittleEndian, "hello", false},
+		{"wide", testStringLayoutTemplate(CIPTypeDINT, CIPTypeUINT, 10), testWideString(binary.LittleEndian, CIPTypeDINT, 5, wide, 24), binary.LittleEndian, "héy😀", false},
+		{"wide INT LEN", testStringLayoutTemplate(CIPTypeINT, CIPTypeINT, 10), testWideString(binary.LittleEndian, CIPTypeINT, 2, wide, 24), binary.LittleEndian, "hé", false},
+		{"wide big endian", testStringLayoutTemplate(CIPTypeDINT, CIPTypeINT, 10), testWideString(binary.BigEndian, CIPTypeDINT, 3, wide, 24), binary.BigEndian, "héy", false},
+		{"LINT LEN", long, longDat, binary.LittleEndian, "abc", false},
+		{"len past capacity", testStringLayoutTemplate(CIPTypeDINT, CIPTypeUINT, 4), testWideString(binary.LittleEndian, CIPTypeDINT, 5, wide, 24), binary.LittleEndian, "", true},
+		{"len past data", testStringLayoutTemplate(CIPTypeDINT, CIPTypeUINT, 10), testWideString(binary.LittleEndian, CIPTypeDINT, 5, wide, 8), binary.LittleEndian, "", true},
+	}
+	for _, tt := range tests {
+		layout, err := newStringLayout(tt.t)
+		if err != nil {
+			t.Errorf("%s: problem with layout: %v", tt.name, err)
+			continue
+		}
+		have, err := layout.parse(tt.dat, tt.order)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("%s: expected an error. got %q", tt.name, have)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: problem parsing: %v", tt.name, err)
+			continue
+		}
+		if have != tt.want {
+			t.Errorf("%s: wanted %q got %q", tt.name, tt.want, have)
+		}
+	}
+
+	notStrings := []Template{
+		testStringLayoutTemplate(CIPTypeREAL, CIPTypeSINT, 10),
+		testStringLayoutTemplate(CIPTypeDINT, CIPTypeDINT, 10),
+		{Name: "Empty"},
+	}
+	for _, tt := range notStrings {
+		_, err := newStringLayout(tt)
+		if err == nil {
+			t.Errorf("%+v shouldn't be a string type", tt.Members)
+		}
+	}
+}
+
+func TestReadStringWide(t *testing.T) {
+	client, remote := newPipeClient(t)
+	wstr := testStringLayoutTemplate(CIPTypeDINT, CIPTypeUINT, 10)
+	client.KnownTags["msg"] = KnownTag{Name: "Msg", Info: TagInfo{Type: CIPTypeStruct}, UDT: &wstr}
+	go func() {
+		hdr, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: CIPTypeStruct, Unknown: 2})
+		item.Serialize(uint16(0x1234)) // the structure handle
+		item.Serialize(testWideString(binary.LittleEndian, CIPTypeDINT, 2, []uint16{0x00C4, 'b'}, 24))
+		b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	have, err := client.ReadString("Msg")
+	if err != nil {
+		t.Fatalf("problem reading string: %v", err)
+	}
+	if have != "Äb" {
+		t.Errorf("wanted Äb got %q", have)
+	}
+}
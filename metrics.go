@@ -0,0 +1,94 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// RequestStats describes one request to the controller and its response.
+type RequestStats struct {
+	Command CIPCommand // the encapsulation command
+	Service CIPService // the CIP service of the request.  0 for commands without one like RegisterSession.
+
+	Duration      time.Duration // from building the request to getting its response or giving up
+	BytesSent     int           // including the encapsulation header
+	BytesReceived int           // including the encapsulation header.  0 if there was no response.
+
+	// the general status of the CIP reply.  A bad status is a successful request as far as Err is concerned.
+	Status CIPStatus
+	Err    error // a problem sending the request or getting its response, like ErrTimeout.
+}
+
+// MetricsObserver gets told about every request the client sends so you can feed a metrics system.
+//
+// ObserveRequest is called on the goroutine that made the request after its response comes back, so it should be
+// quick and has to be safe to call from more than one goroutine at a time.
+type MetricsObserver interface {
+	ObserveRequest(stats RequestStats)
+}
+
+// the CIP service and general status of the first data item in the payload of a SendUnitData or SendRRData.  Other
+// commands, or payloads that can't be parsed, give zeros.
+func payloadServiceStatus(cmd CIPCommand, dat []byte) (CIPService, CIPStatus) {
+	if cmd != cipCommandSendUnitData && cmd != cipCommandSendRRData {
+		return 0, 0
+	}
+	// interface handle, timeout, item count
+	if len(dat) < 8 {
+		return 0, 0
+	}
+	count := int(binary.LittleEndian.Uint16(dat[6:]))
+	pos := 8
+	for i := 0; i < count && pos+4 <= len(dat); i++ {
+		id := CIPItemID(binary.LittleEndian.Uint16(dat[pos:]))
+		end := pos + 4 + int(binary.LittleEndian.Uint16(dat[pos+2:]))
+		if end > len(dat) {
+			end = len(dat)
+		}
+		body := dat[pos+4 : end]
+		pos = end
+		switch id {
+		case cipItem_ConnectedData:
+			// connected data starts with the sequence count.
+			if len(body) < 2 {
+				return 0, 0
+			}
+			body = body[2:]
+		case cipItem_UnconnectedData:
+		default:
+			continue
+		}
+		if len(body) == 0 {
+			return 0, 0
+		}
+		if len(body) < 3 {
+			return CIPService(body[0]), 0
+		}
+		return CIPService(body[0]), CIPStatus(body[2])
+	}
+	return 0, 0
+}
+
+// tell the metrics observer about a request.  sent is the whole request and received the payload of the response.
+func (client *Client) observeRequest(cmd CIPCommand, start time.Time, sent []byte, hdr eipHeader, received []byte, err error) {
+	if client.Metrics == nil {
+		return
+	}
+	stats := RequestStats{
+		Command:   cmd,
+		Duration:  time.Since(start),
+		BytesSent: len(sent),
+		Err:       err,
+	}
+	if len(sent) > eipHeaderSize {
+		stats.Service, _ = payloadServiceStatus(cmd, sent[eipHeaderSize:])
+	}
+	if received != nil || hdr.Command != 0 {
+		stats.BytesReceived = eipHeaderSize + len(received)
+		_, stats.Status = payloadServiceStatus(cmd, received)
+	}
+	client.Metrics.ObserveRequest(stats)
+}
+
+// the size of the encapsulation header on every request and response.
+const eipHeaderSize = 24
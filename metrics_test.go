@@ -0,0 +1,72 @@
+package gologix
+
+import (
+	"sync"
+	"testing"
+)
+
+type testObserver struct {
+	mu    sync.Mutex
+	stats []RequestStats
+}
+
+func (o *testObserver) ObserveRequest(stats RequestStats) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.stats = append(o.stats, stats)
+}
+
+func (o *testObserver) last() RequestStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.stats[len(o.stats)-1]
+}
+
+func TestMetrics(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{"count": int32(3)})
+	obs := &testObserver{}
+	client.Metrics = obs
+
+	var v int32
+	err := client.Read("Count", &v)
+	if err != nil {
+		t.Fatalf("problem reading: %v", err)
+	}
+	s := obs.last()
+	if s.Command != cipCommandSendUnitData || s.Service != CIPService_Read || s.Status != CIPStatus_OK || s.Err != nil {
+		t.Errorf("wanted a good read. got %+v", s)
+	}
+	if s.BytesSent <= eipHeaderSize || s.BytesReceived <= eipHeaderSize || s.Duration <= 0 {
+		t.Errorf("wanted sizes and a duration. got %+v", s)
+	}
+
+	err = client.Read("NotThere", &v)
+	if !IsTagNotFound(err) {
+		t.Fatalf("wanted tag not found. got %v", err)
+	}
+	s = obs.last()
+	if s.Service != CIPService_Read || s.Status != CIPStatus_PathDestinationUnknown || s.Err != nil {
+		t.Errorf("wanted a read with a bad status. got %+v", s)
+	}
+}
+
+func TestPayloadServiceStatus(t *testing.T) {
+	items := []CIPItem{{Header: cipItemHeader{ID: cipItem_Null}}, newItem(cipItem_UnconnectedData, nil)}
+	items[1].Serialize([]byte{byte(CIPService_GetAttributeAll.AsResponse()), 0, byte(CIPStatus_PrivilegeViolation), 0})
+	b, err := serializeItems(items)
+	if err != nil {
+		t.Fatalf("problem building items: %v", err)
+	}
+	service, status := payloadServiceStatus(cipCommandSendRRData, *b)
+	if service != CIPService_GetAttributeAll.AsResponse() || status != CIPStatus_PrivilegeViolation {
+		t.Errorf("wanted the unconnected service and status. got %v %v", service, status)
+	}
+
+	for _, dat := range [][]byte{nil, {1, 2, 3}, (*b)[:len(*b)-4]} {
+		payloadServiceStatus(cipCommandSendRRData, dat) // shouldn't panic
+	}
+	service, _ = payloadServiceStatus(cipCommandRegisterSession, *b)
+	if service != 0 {
+		t.Errorf("register session doesn't have a service. got %v", service)
+	}
+}
@@ -0,0 +1,163 @@
+package gologix
+
+import (
+	"fmt"
+	"net"
+)
+
+// Read every attribute of one instance of an object in a single request with the Get_Attributes_All service.
+//
+// The reply data comes back raw in the order and format the object's spec lists the attributes.  The TCP/IP Interface
+// and Ethernet Link objects can be decoded with ParseTCPIPInterface and ParseEthernetLink.  Use instance 0 to get the
+// class attributes.
+func (client *Client) GetAttributesAll(class CIPClass, instance CIPInstance) ([]byte, error) {
+	return client.GenericCIP(CIPService_GetAttributeAll, class, instance, 0, nil)
+}
+
+// TCPIPInterface is the network configuration of an ethernet port from its TCP/IP Interface object (class 0xF5).
+type TCPIPInterface struct {
+	Status           uint32
+	ConfigCapability uint32
+	ConfigControl    uint32
+
+	// the path to the Ethernet Link object of the port this interface is on.
+	PhysicalLink []byte
+
+	IP          net.IP
+	SubnetMask  net.IP
+	Gateway     net.IP
+	NameServer  net.IP
+	NameServer2 net.IP
+	DomainName  string
+	HostName    string
+}
+
+// Whether the interface gets its address from a BOOTP server.
+func (t TCPIPInterface) BOOTP() bool {
+	return t.ConfigControl&0x0F == 1
+}
+
+// Whether the interface gets its address from a DHCP server.
+func (t TCPIPInterface) DHCP() bool {
+	return t.ConfigControl&0x0F == 2
+}
+
+// Read the TCP/IP Interface object of the ethernet module at the client's IP address.
+func (client *Client) GetTCPIPInterface() (TCPIPInterface, error) {
+	dat, err := client.GenericCIPUnconnected(CIPService_GetAttributeAll, CipObject_TCPIP, 1, 0, nil)
+	if err != nil {
+		return TCPIPInterface{}, fmt.Errorf("problem reading tcp/ip interface object: %w", err)
+	}
+	return ParseTCPIPInterface(dat)
+}
+
+// Decode the Get_Attributes_All reply data of a TCP/IP Interface object.  Only attributes 1 through 6 are decoded.
+// Anything the device sends after the host name is ignored.
+func ParseTCPIPInterface(dat []byte) (TCPIPInterface, error) {
+	var t TCPIPInterface
+	item := CIPItem{Data: dat}
+	var err error
+	for _, v := range []*uint32{&t.Status, &t.ConfigCapability, &t.ConfigControl} {
+		*v, err = item.Uint32()
+		if err != nil {
+			return t, fmt.Errorf("problem reading interface status. %w", err)
+		}
+	}
+
+	// the path size is in words.
+	words, err := item.Uint16()
+	if err != nil {
+		return t, fmt.Errorf("problem reading physical link path size. %w", err)
+	}
+	t.PhysicalLink = make([]byte, int(words)*2)
+	err = item.DeSerialize(t.PhysicalLink)
+	if err != nil {
+		return t, fmt.Errorf("problem reading physical link path. %w", err)
+	}
+
+	for _, ip := range []*net.IP{&t.IP, &t.SubnetMask, &t.Gateway, &t.NameServer, &t.NameServer2} {
+		v, err := item.Uint32()
+		if err != nil {
+			return t, fmt.Errorf("problem reading interface configuration. %w", err)
+		}
+		*ip = net.IPv4(byte(v>>24), byte(v>>16), byte(v>>8), byte(v)).To4()
+	}
+
+	t.DomainName, err = readPaddedString(&item)
+	if err != nil {
+		return t, fmt.Errorf("problem reading domain name. %w", err)
+	}
+	t.HostName, err = readPaddedString(&item)
+	if err != nil {
+		return t, fmt.Errorf("problem reading host name. %w", err)
+	}
+	return t, nil
+}
+
+// read a STRING attribute: a UINT length then the characters padded to an even length.
+func readPaddedString(item *CIPItem) (string, error) {
+	size, err := item.Uint16()
+	if err != nil {
+		return "", err
+	}
+	str := make([]byte, size)
+	err = item.DeSerialize(str)
+	if err != nil {
+		return "", err
+	}
+	if size%2 == 1 {
+		if _, err = item.Byte(); err != nil {
+			return "", err
+		}
+	}
+	return string(str), nil
+}
+
+// EthernetLink is the state of an ethernet port from its Ethernet Link object (class 0xF6).
+type EthernetLink struct {
+	Speed uint32 // Mbps
+	Flags uint32
+	MAC   net.HardwareAddr
+}
+
+// Whether the port has a link.
+func (e EthernetLink) LinkUp() bool {
+	return e.Flags&0x01 != 0
+}
+
+// Whether the link is full duplex.  Only means something when LinkUp.
+func (e EthernetLink) FullDuplex() bool {
+	return e.Flags&0x02 != 0
+}
+
+// Read an Ethernet Link object of the ethernet module at the client's IP address.  Instance 1 is the first port.
+func (client *Client) GetEthernetLink(instance CIPInstance) (EthernetLink, error) {
+	dat, err := client.GenericCIPUnconnected(CIPService_GetAttributeAll, CipObject_EthernetLink, instance, 0, nil)
+	if err != nil {
+		return EthernetLink{}, fmt.Errorf("problem reading ethernet link object %d: %w", instance, err)
+	}
+	return ParseEthernetLink(dat)
+}
+
+// Decode the Get_Attributes_All reply data of an Ethernet Link object.  Only the speed, flags, and MAC address
+// (attributes 1 through 3) are decoded.  The counters and the rest that follow them vary by device.
+func ParseEthernetLink(dat []byte) (EthernetLink, error) {
+	var e EthernetLink
+	item := CIPItem{Data: dat}
+	var err error
+	e.Speed, err = item.Uint32()
+	if err != nil {
+		return e, fmt.Errorf("problem reading interface speed. %w", err)
+	}
+	e.Flags, err = item.Uint32()
+	if err != nil {
+		return e, fmt.Errorf("problem reading interface flags. %w", err)
+	}
+	mac := make([]byte, 6)
+	err = item.DeSerialize(mac)
+	if err != nil {
+		return e, fmt.Errorf("problem reading mac address. %w", err)
+	}
+	e.MAC = mac
+	return e, nil
+}
@@ -0,0 +1,94 @@
+package gologix
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseTCPIPInterface(t *testing.T) {
+	item := CIPItem{}
+	item.Serialize([3]uint32{1, 0x94, 0x02}) // status, capability, dhcp
+	item.Serialize(uint16(2))
+	item.Serialize([]byte{0x20, 0xF6, 0x24, 0x01})
+	item.Serialize([5]uint32{0xC0A8010A, 0xFFFFFF00, 0xC0A80101, 0x08080808, 0})
+	item.Serialize(uint16(3))
+	item.Serialize([]byte("lan\x00"))
+	item.Serialize(uint16(4))
+	item.Serialize([]byte("plc1"))
+	item.Serialize([]byte{0x01, 0x02}) // ttl and the rest that isn't decoded
+
+	have, err := ParseTCPIPInterface(item.Data)
+	if err != nil {
+		t.Fatalf("problem parsing: %v", err)
+	}
+	if have.IP.String() != "192.168.1.10" || have.SubnetMask.String() != "255.255.255.0" || have.Gateway.String() != "192.168.1.1" {
+		t.Errorf("wrong addresses %v %v %v", have.IP, have.SubnetMask, have.Gateway)
+	}
+	if have.NameServer.String() != "8.8.8.8" || have.NameServer2.String() != "0.0.0.0" {
+		t.Errorf("wrong name servers %v %v", have.NameServer, have.NameServer2)
+	}
+	if have.DomainName != "lan" || have.HostName != "plc1" {
+		t.Errorf("wrong names %q %q", have.DomainName, have.HostName)
+	}
+	if !check_bytes(have.PhysicalLink, []byte{0x20, 0xF6, 0x24, 0x01}) {
+		t.Errorf("wrong physical link %v", to_hex(have.PhysicalLink))
+	}
+	if !have.DHCP() || have.BOOTP() {
+		t.Errorf("should be dhcp")
+	}
+
+	_, err = ParseTCPIPInterface(item.Data[:30])
+	if err == nil {
+		t.Errorf("a short reply should be an error")
+	}
+}
+
+func TestParseEthernetLink(t *testing.T) {
+	dat := []byte{
+		100, 0, 0, 0, // 100 Mbps
+		0x03, 0, 0, 0, // link up, full duplex
+		0x00, 0x1D, 0x9C, 0x01, 0x02, 0x03,
+		0xFF, 0xFF, // counters that aren't decoded
+	}
+	have, err := ParseEthernetLink(dat)
+	if err != nil {
+		t.Fatalf("problem parsing: %v", err)
+	}
+	if have.Speed != 100 || !have.LinkUp() || !have.FullDuplex() {
+		t.Errorf("wrong link %+v", have)
+	}
+	if have.MAC.String() != "00:1d:9c:01:02:03" {
+		t.Errorf("wrong mac %v", have.MAC)
+	}
+	_, err = ParseEthernetLink(dat[:10])
+	if err == nil {
+		t.Errorf("a short reply should be an error")
+	}
+}
+
+func TestGetAttributesAll(t *testing.T) {
+	client, remote := newPipeClient(t)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		req := buf.Bytes()[20:]
+		if want := []byte{byte(CIPService_GetAttributeAll), 2, 0x20, 0xF6, 0x24, 0x02}; !bytes.Equal(req[2:], want) {
+			t.Errorf("wanted request % X got % X", want, req[2:])
+		}
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize([]byte{0, 0, byte(CIPService_GetAttributeAll.AsResponse()), 0, 0, 0, 0xAA, 0xBB})
+		b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	have, err := client.GetAttributesAll(CipObject_EthernetLink, 2)
+	if err != nil {
+		t.Fatalf("problem getting attributes: %v", err)
+	}
+	if !bytes.Equal(have, []byte{0xAA, 0xBB}) {
+		t.Errorf("wanted AA BB got % X", have)
+	}
+}
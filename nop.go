@@ -0,0 +1,26 @@
+package gologix
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Send an encapsulation NOP (command 0x00).  The controller doesn't reply to it, it only resets the controller's
+// encapsulation inactivity timer so an otherwise idle TCP session isn't closed.
+//
+// A NOP isn't traffic on the cip connection so it doesn't keep a forward open connection alive, only a connected
+// message does that.  Some controllers don't count a NOP for anything at all.  See HeartbeatNop.
+func (client *Client) Nop() error {
+	if !client.connected {
+		return errors.New("not connected")
+	}
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	buf := getSendBuffer()
+	defer putSendBuffer(buf)
+	err := client.sendMsgBuild(buf, cipCommand_NOP)
+	if err != nil {
+		return fmt.Errorf("problem building nop: %w", err)
+	}
+	return client.sendData(buf.Bytes())
+}
@@ -0,0 +1,26 @@
+package gologix
+
+import (
+	"testing"
+)
+
+func TestNop(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{"count": int32(3)})
+	defer client.Disconnect()
+
+	err := client.Nop()
+	if err != nil {
+		t.Fatalf("problem sending nop: %v", err)
+	}
+	// there's no reply to the nop so the next request gets its own response.
+	var v int32
+	err = client.Read("Count", &v)
+	if err != nil || v != 3 {
+		t.Errorf("wanted 3 after the nop. got %d and %v", v, err)
+	}
+
+	client.Disconnect()
+	if client.Nop() == nil {
+		t.Errorf("a nop should fail when not connected")
+	}
+}
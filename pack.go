@@ -11,12 +11,18 @@ import (
 )
 
 type cipPack struct {
+	order binary.ByteOrder // nil is little endian
 }
 
 type Packable interface {
 	Pack(w io.Writer) (int, error)
 }
 
+// a Packable whose scalars follow the byte order of the cipPack packing it.
+type orderPackable interface {
+	packOrder(w io.Writer, order binary.ByteOrder) (int, error)
+}
+
 type Unpackable interface {
 	Unpack(r io.Reader) (n int, err error)
 }
@@ -44,7 +50,10 @@ func (p cipPack) Align(t reflect.Type) int {
 }
 
 func (p cipPack) Order() binary.ByteOrder {
-	return binary.LittleEndian
+	if p.order == nil {
+		return binary.LittleEndian
+	}
+	return p.order
 }
 
 type Serializable interface {
@@ -94,9 +103,14 @@ func Serialize(strs ...any) (*bytes.Buffer, error) {
 // serialize data into w appropriately for CIP messaging
 // obeys alignment and padding rules
 func Pack(w io.Writer, data any) (int, error) {
-	p := cipPack{}
+	return cipPack{}.pack(w, data)
+}
 
+// Pack with the scalar fields in p's byte order.
+func (p cipPack) pack(w io.Writer, data any) (int, error) {
 	switch d := data.(type) {
+	case orderPackable:
+		return d.packOrder(w, p.Order())
 	case Packable:
 		return d.Pack(w)
 	case Serializable:
@@ -216,7 +230,7 @@ func Pack(w io.Writer, data any) (int, error) {
 
 		} else {
 			var err error
-			s, err = Pack(w, refVal.Field(i).Interface())
+			s, err = p.pack(w, refVal.Field(i).Interface())
 			if err != nil {
 				return pos, fmt.Errorf("problem packing interface: %w", err)
 			}
@@ -239,8 +253,11 @@ func Pack(w io.Writer, data any) (int, error) {
 // deserialize data from r appropriately for CIP messaging
 // obeys alignment and padding rules
 func Unpack(r io.Reader, data any) (n int, err error) {
-	p := cipPack{}
+	return cipPack{}.unpack(r, data)
+}
 
+// Unpack with the scalar fields in p's byte order.
+func (p cipPack) unpack(r io.Reader, data any) (n int, err error) {
 	switch d := data.(type) {
 	case Unpackable:
 		return d.Unpack(r)
@@ -348,7 +365,7 @@ func Unpack(r io.Reader, data any) (n int, err error) {
 			}
 		} else {
 			val := refVal.Field(i).Addr().Interface()
-			s, err = Unpack(r, val)
+			s, err = p.unpack(r, val)
 			if err != nil {
 				return
 			}
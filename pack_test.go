@@ -2,6 +2,7 @@ package gologix
 
 import (
 	"bytes"
+	"encoding/binary"
 	"testing"
 
 	"github.com/danomagnum/gologix/lgxtypes"
@@ -82,6 +83,41 @@ func TestPack(t *testing.T) {
 	}
 }
 
+func TestPackBigEndian(t *testing.T) {
+	type S struct {
+		Flag bool
+		I16  int16
+		Name [3]byte
+		U32  uint32
+	}
+	s := S{Flag: true, I16: 0x0102, Name: [3]byte{'a', 'b', 'c'}, U32: 0x03040506}
+	p := cipPack{order: binary.BigEndian}
+	b := bytes.Buffer{}
+	_, err := p.pack(&b, s)
+	if err != nil {
+		t.Fatalf("problem packing: %v", err)
+	}
+	want := []byte{
+		1, 0, // flag and padding
+		0x01, 0x02, // I16
+		'a', 'b', 'c', // Name stays in order
+		0,                      // padding
+		0x03, 0x04, 0x05, 0x06, // U32
+	}
+	if !check_bytes(b.Bytes(), want) {
+		t.Errorf("ResultMismatch.\n Have %v\n Want %v\n", b.Bytes(), want)
+	}
+
+	have := S{}
+	_, err = p.unpack(bytes.NewBuffer(b.Bytes()), &have)
+	if err != nil {
+		t.Fatalf("problem unpacking: %v", err)
+	}
+	if have != s {
+		t.Errorf("wanted %+v got %+v", s, have)
+	}
+}
+
 func TestPack2(t *testing.T) {
 	type S2 struct {
 		Flag1    bool
@@ -1,9 +1,13 @@
 package gologix
 
 import (
-	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // Read a list of tags of specified types.
@@ -35,11 +39,15 @@ func (client *Client) ReadList(tagnames []string, types []CIPType, elements []in
 		if err != nil {
 			return nil, err
 		}
-		subresults, err := client.readList(tags[n : n+n_new])
+		subresults, errs, err := client.readList(tags[n : n+n_new])
 		n += n_new
 		if err != nil {
 			return nil, err
 		}
+		err = errors.Join(errs...)
+		if err != nil {
+			return nil, err
+		}
 		results = append(results, subresults...)
 
 	}
@@ -48,76 +56,373 @@ func (client *Client) ReadList(tagnames []string, types []CIPType, elements []in
 	return results, nil
 }
 
-func (client *Client) countIOIsThatFit(tags []tagDesc) (int, error) {
-	// first generate IOIs for each tag
-	qty := len(tags)
+// Read a list of tags without having to know their types ahead of time.
+//
+// The reads are packed into as few multi-service requests as will fit in the connection size.  The value and
+// error slices are the same length as tags and in the same order.  A tag that can't be read gets a nil value and
+// a non-nil error at its index without affecting the rest of the tags.
+//
+// Values come back as the go type that matches the controller's type for the tag.  STRING tags come back as a
+// string and other structures come back as their raw bytes.
+//
+// Elements of the same array next to each other (ex: "Arr[0]", "Arr[1]", "Arr[2]") are read together as one read with
+// an element count and split back out to their own indexes.  If that read fails, like when one of the elements is past
+// the end of the array, the elements are read one at a time instead so each still gets its own result.
+func (client *Client) ReadMultiple(tags []string) ([]any, []error) {
+	return client.readMultipleTags(tags, nil)
+}
 
-	ioi_header := msgCIPConnectedMultiServiceReq{
-		Sequence:     uint16(sequencer()),
-		Service:      CIPService_MultipleService,
-		PathSize:     2,
-		Path:         [4]byte{0x20, 0x02, 0x24, 0x01},
-		ServiceCount: uint16(qty),
+// Read a list of tags whose types are known ahead of time.
+//
+// This is ReadMultiple with each value checked against its type in types before it is returned.  The value at index
+// i is always the go type for types[i] (see GoVarToCIPType) so it can be type asserted without checking.  BYTE and
+// USINT are both a byte.  A tag that turns out to be some other type gets a type mismatch error and a nil value.
+// Structures other than STRING come back as their raw bytes.
+func (client *Client) ReadMultipleTyped(tags []string, types []CIPType) ([]any, []error) {
+	if len(types) != len(tags) {
+		results := make([]any, len(tags))
+		errs := make([]error, len(tags))
+		err := fmt.Errorf("got %d types for %d tags", len(types), len(tags))
+		for i := range errs {
+			errs[i] = err
+		}
+		return results, errs
 	}
+	results, errs := client.readMultipleTags(tags, types)
+	for i := range results {
+		if errs[i] != nil {
+			continue
+		}
+		want := types[i].GoType()
+		if want == nil || reflect.TypeOf(results[i]) != want {
+			errs[i] = typeMismatch(tags[i], results[i], types[i])
+			results[i] = nil
+		}
+	}
+	return results, errs
+}
 
-	mainhdr_size := binary.Size(ioi_header)
-	ioihdr_size := binary.Size(msgCIPMultiIOIHeader{})
-	ioiftr_size := binary.Size(msgCIPIOIFooter{})
-
-	b := bytes.Buffer{}
-	// we now have to build up the jump table for each IOI.
-	// and pack all the IOIs together into b
-	jump_table := make([]uint16, qty)
-
-	// how many ioi's fit in the message
-	n := 1
+// the reads behind ReadMultiple and ReadMultipleTyped.  types can be nil when the types aren't known.
+func (client *Client) readMultipleTags(tags []string, types []CIPType) ([]any, []error) {
+	results := make([]any, len(tags))
+	errs := make([]error, len(tags))
 
-	response_size := 0
+	err := client.checkConnection()
+	if err != nil {
+		err = fmt.Errorf("could not start multiple read: %w", err)
+		for i := range errs {
+			errs[i] = err
+		}
+		return results, errs
+	}
 
+	// tags that we can't build an IOI for get their error now and are left out of the requests.
+	reads := make([]multiRead, 0, len(tags))
 	for i, tag := range tags {
-		ioi, err := client.newIOI(tag.TagName, tag.TagType)
+		ct := CIPTypeUnknown
+		if types != nil {
+			ct = types[i]
+		}
+		_, err := client.newIOI(tag, ct)
 		if err != nil {
-			return 0, err
+			errs[i] = fmt.Errorf("problem building ioi for %s: %w", tag, err)
+			continue
 		}
+		reads = append(reads, singleRead(tag, ct, i))
+	}
+	reads = client.collapseArrayReads(reads)
+
+	msgs, retry := client.readMultiple(reads, results, errs)
+	if len(retry) > 0 {
+		singles := make([]multiRead, 0, len(retry))
+		for _, r := range retry {
+			for k, idx := range r.indexes {
+				singles = append(singles, singleRead(fmt.Sprintf("%s[%d]", r.base, r.first+r.offsets[k]), r.desc.TagType, idx))
+			}
+		}
+		more, _ := client.readMultiple(singles, results, errs)
+		msgs += more
+	}
+
+	client.Logger.Debug("Multiple Read", "messages", msgs, "reads", len(reads), "tags", len(tags))
+	return results, errs
+}
 
-		jump_table[i] = uint16(b.Len())
+// one read in a ReadMultiple.  A collapsed read of several elements of an array has the array's base name and the
+// index it starts at.  The value of element offsets[k] goes to the caller's tag at indexes[k].
+type multiRead struct {
+	desc    tagDesc
+	base    string
+	first   int
+	indexes []int
+	offsets []int
+}
+
+func singleRead(tag string, ct CIPType, index int) multiRead {
+	return multiRead{
+		desc:    tagDesc{TagName: tag, TagType: ct, Elements: 1},
+		indexes: []int{index},
+		offsets: []int{0},
+	}
+}
+
+// do the reads in as few requests as fit and put the values and errors at the caller's indexes.  Collapsed reads that
+// can't be split back out are returned so their elements can be read one at a time.
+func (client *Client) readMultiple(reads []multiRead, results []any, errs []error) (int, []multiRead) {
+	descs := make([]tagDesc, len(reads))
+	for i := range reads {
+		descs[i] = reads[i].desc
+	}
+	retry := make([]multiRead, 0)
 
-		h := msgCIPMultiIOIHeader{
-			Service: CIPService_Read,
-			Size:    byte(len(ioi.Buffer) / 2),
+	n := 0
+	msgs := 0
+	for n < len(descs) {
+		msgs += 1
+		n_new, err := client.countIOIsThatFit(descs[n:])
+		if err != nil {
+			for _, r := range reads[n:] {
+				r.fail(errs, err)
+			}
+			break
 		}
-		f := msgCIPIOIFooter{
-			Elements: 1,
+		subresults, suberrs, err := client.readList(descs[n : n+n_new])
+		for j, r := range reads[n : n+n_new] {
+			if err != nil {
+				// the whole request failed so every tag in it failed.
+				r.fail(errs, err)
+				continue
+			}
+			if !r.scatter(subresults[j], suberrs[j], results, errs) {
+				retry = append(retry, r)
+			}
 		}
+		n += n_new
+	}
+	return msgs, retry
+}
 
-		// Calculate the size of the data once we add this ioi to the list.
-		newSize := mainhdr_size                                // length of the multi-read header
-		newSize += 2 * n                                       // add in the jump table
-		newSize += b.Len()                                     // everything we have so far
-		newSize += ioihdr_size + len(ioi.Buffer) + ioiftr_size // the new ioi data
+func (r multiRead) fail(errs []error, err error) {
+	for _, idx := range r.indexes {
+		errs[idx] = err
+	}
+}
 
-		response_size += tags[i].TagType.Size() * tags[i].Elements
-		if newSize > int(client.ConnectionSize) || response_size > int(client.ConnectionSize) {
-			// break before adding this ioi to the list since it will push us over.
-			// we'll continue with n iois (n only increments after an IOI is added)
-			break
+// put the result of the read at its tags' indexes.  false means a collapsed read failed or came back in a way that
+// can't be split up by element.
+func (r multiRead) scatter(val any, err error, results []any, errs []error) bool {
+	if r.desc.Elements == 1 {
+		for _, idx := range r.indexes {
+			results[idx] = val
+			errs[idx] = err
 		}
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	vals, ok := val.([]any)
+	if !ok || len(vals) != r.desc.Elements {
+		return false
+	}
+	if _, ok := vals[0].(uint32); ok {
+		// BOOL arrays come back as DWORDs of packed bits so there's no telling them apart from a DWORD array here.
+		return false
+	}
+	for k, idx := range r.indexes {
+		results[idx] = vals[r.offsets[k]]
+		errs[idx] = nil
+	}
+	return true
+}
 
-		err = binary.Write(&b, binary.LittleEndian, h)
-		if err != nil {
-			return 0, fmt.Errorf("problem writing cip IO header to buffer. %w", err)
+// combine single reads of elements of the same array that are next to each other into one read with a count.  A run
+// is cut short if its reply might not fit in the connection.  Reads that aren't array elements are left as they are.
+// Elements asked for as different types aren't combined.
+func (client *Client) collapseArrayReads(reads []multiRead) []multiRead {
+	type element struct {
+		index int // in the array
+		slot  int // in the caller's tags
+	}
+	out := make([]multiRead, 0, len(reads))
+	groups := make(map[string][]element)
+	bases := make(map[string]string)
+	types := make(map[string]CIPType)
+	order := make([]string, 0)
+	for _, r := range reads {
+		base, index, ok := arrayElement(r.desc.TagName)
+		if !ok {
+			out = append(out, r)
+			continue
+		}
+		if known, ok := client.KnownTags[strings.ToLower(base)]; ok && known.Info.Type == CIPTypeBOOL {
+			out = append(out, r)
+			continue
+		}
+		key := fmt.Sprintf("%s/%v", strings.ToLower(base), r.desc.TagType)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+			bases[key] = base
+			types[key] = r.desc.TagType
+		}
+		groups[key] = append(groups[key], element{index: index, slot: r.indexes[0]})
+	}
+
+	// replies are planned for 8 bytes an element when the type isn't known.  see countIOIsThatFit.
+	maxRun := max((int(client.ConnectionSize)-64)/8, 1)
+	for _, key := range order {
+		elements := groups[key]
+		sort.SliceStable(elements, func(a, b int) bool { return elements[a].index < elements[b].index })
+		start := 0
+		for start < len(elements) {
+			first := elements[start].index
+			end := start + 1
+			for end < len(elements) && elements[end].index-elements[end-1].index <= 1 && elements[end].index-first < maxRun {
+				end++
+			}
+			run := elements[start:end]
+			r := multiRead{
+				desc: tagDesc{
+					TagName:  fmt.Sprintf("%s[%d]", bases[key], first),
+					TagType:  types[key],
+					Elements: run[len(run)-1].index - first + 1,
+				},
+				base:  bases[key],
+				first: first,
+			}
+			for _, e := range run {
+				r.indexes = append(r.indexes, e.slot)
+				r.offsets = append(r.offsets, e.index-first)
+			}
+			out = append(out, r)
+			start = end
 		}
-		b.Write(ioi.Buffer)
-		err = binary.Write(&b, binary.LittleEndian, f)
+	}
+	return out
+}
+
+// split "Arr[3]" into "Arr" and 3.  Tags that aren't a single element of a one dimensional array give false.
+func arrayElement(tag string) (string, int, bool) {
+	pos := array_access_regex.FindStringIndex(tag)
+	if pos == nil || pos[0] == 0 {
+		return "", 0, false
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(tag[pos[0]+1 : pos[1]-1]))
+	if err != nil || index < 0 {
+		return "", 0, false
+	}
+	return tag[:pos[0]], index, true
+}
+
+// the bytes a multi-service read reply takes before the replies (sequence count, service, status, reply count) and
+// for each reply on top of its data (an offset table entry, service, status, and type).  A structure also has its
+// handle after the type.
+const (
+	multiReadReplyHeaderSize = 8
+	multiReadReplyEntrySize  = 8
+)
+
+// how many of the tags can be read in one multi-service request.  At least one is always taken so the caller can make
+// progress.
+//
+// Both the request and the reply have to fit in the connection size.  The request size comes from the actual IOI of
+// each tag so long symbolic names take more room than short ones or tags we know the instance of.  The reply size is
+// planned from the tag's type, or 8 bytes an element when that isn't known.  A single tag whose reply won't fit is
+// still sent on its own and readList gets the rest of it with fragmented reads.
+func (client *Client) countIOIsThatFit(tags []tagDesc) (int, error) {
+	ioihdr_size := binary.Size(msgCIPMultiIOIHeader{})
+	ioiftr_size := binary.Size(msgCIPIOIFooter{})
+
+	request_size := binary.Size(msgCIPConnectedMultiServiceReq{})
+	response_size := multiReadReplyHeaderSize
+
+	for i, tag := range tags {
+		ioi, err := client.newIOI(tag.TagName, tag.TagType)
 		if err != nil {
-			return 0, fmt.Errorf("problem writing ioi buffer to msg buffer. %w", err)
+			return 0, err
+		}
+		// the jump table entry and the read itself.
+		request_size += 2 + ioihdr_size + len(ioi.Buffer) + ioiftr_size
+
+		var template *UDTDescriptor
+		if known, ok := client.KnownTags[strings.ToLower(tag.TagName)]; ok {
+			template = known.UDT
+		}
+		size := tag.TagType.SizeWithTemplate(template)
+		if size == 0 {
+			// we don't know what the controller will send back so plan for the biggest atomic type.
+			size = 8
+		}
+		response_size += multiReadReplyEntrySize + size*tag.Elements
+		if tag.TagType == CIPTypeStruct {
+			response_size += 2
 		}
 
-		n = i + 1
+		if i > 0 && (request_size > int(client.ConnectionSize) || response_size > int(client.ConnectionSize)) {
+			// this one pushes us over so it starts the next request.
+			client.Logger.Debug("Packed Efficiency", "tags", i, "bytes", client.ConnectionSize)
+			return i, nil
+		}
 	}
 
-	client.Logger.Debug("Packed Efficiency", "tags", n, "bytes", client.ConnectionSize)
+	return len(tags), nil
+}
+
+// Read the exported fields of a T from the controller in as few requests as fit.
+//
+// Each field is read from the tag with the same name as the field, or from the tag in its `gologix:"TAGNAME"` struct
+// tag if it has one.  `gologix:"-"` leaves a field out.  Fields have to be one of the atomic go types or a string.
+// Types are checked like ReadMultipleTyped so a field is only set when the tag matches it.  A field that can't be
+// read keeps its zero value and the error names it, but the rest of the fields are still filled in.
+//
+// Example:
+//
+//	type Poll struct {
+//		Speed   float32
+//		Count   int32 `gologix:"Line1.Count"`
+//		Running bool
+//	}
+//	p, err := gologix.ReadFields[Poll](client)
+func ReadFields[T any](client *Client) (T, error) {
+	var out T
+	v := reflect.ValueOf(&out).Elem()
+	if v.Kind() != reflect.Struct {
+		return out, fmt.Errorf("ReadFields needs a struct type. got %T", out)
+	}
 
-	return n, nil
+	fieldErrs := make([]error, 0)
+	fields := make([]int, 0, v.NumField())
+	tags := make([]string, 0, v.NumField())
+	types := make([]CIPType, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Name
+		if name, ok := field.Tag.Lookup("gologix"); ok {
+			if name == "-" {
+				continue
+			}
+			tag = name
+		}
+		ct, _ := GoVarToCIPType(v.Field(i).Interface())
+		if ct.GoType() != field.Type {
+			fieldErrs = append(fieldErrs, fmt.Errorf("field %s: can't read into a %v", field.Name, field.Type))
+			continue
+		}
+		fields = append(fields, i)
+		tags = append(tags, tag)
+		types = append(types, ct)
+	}
 
+	results, errs := client.ReadMultipleTyped(tags, types)
+	for j, i := range fields {
+		if errs[j] != nil {
+			fieldErrs = append(fieldErrs, fmt.Errorf("field %s: %w", v.Type().Field(i).Name, errs[j]))
+			continue
+		}
+		v.Field(i).Set(reflect.ValueOf(results[j]))
+	}
+	return out, errors.Join(fieldErrs...)
 }
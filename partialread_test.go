@@ -0,0 +1,209 @@
+package gologix
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCollapseArrayReads(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.KnownTags["flags"] = KnownTag{Name: "Flags", Info: TagInfo{Type: CIPTypeBOOL}}
+	tags := []string{"Arr[2]", "Count", "arr[0]", "Arr[1]", "Arr[5]", "Flags[0]", "Flags[1]", "Arr[2]", "Grid[1,2]"}
+	reads := make([]multiRead, len(tags))
+	for i, tag := range tags {
+		reads[i] = singleRead(tag, CIPTypeUnknown, i)
+	}
+	reads = client.collapseArrayReads(reads)
+
+	// Count, the two Flags, and Grid aren't collapsed.  Arr[0]-Arr[2] is one read and Arr[5] is on its own.
+	if len(reads) != 6 {
+		t.Fatalf("wanted 6 reads got %d: %+v", len(reads), reads)
+	}
+	var run multiRead
+	for _, r := range reads {
+		if r.desc.Elements > 1 {
+			run = r
+		}
+	}
+	if run.desc.TagName != "Arr[0]" || run.desc.Elements != 3 {
+		t.Fatalf("wanted 3 elements from Arr[0]. got %+v", run.desc)
+	}
+	want := map[int]int{0: 2, 2: 0, 3: 1, 7: 2} // caller's index -> element offset
+	if len(run.indexes) != len(want) {
+		t.Fatalf("wanted %d indexes got %v", len(want), run.indexes)
+	}
+	for k, idx := range run.indexes {
+		if want[idx] != run.offsets[k] {
+			t.Errorf("tag %d should get element %d. got %d", idx, want[idx], run.offsets[k])
+		}
+	}
+}
+
+func TestReadMultipleCollapsed(t *testing.T) {
+	// the map tag provider takes "arr[0]" literally and a count gives the first elements of the slice there.
+	_, client := newTestServer(t, map[string]any{
+		"arr[0]":   []int32{10, 11, 12},
+		"count":    int32(7),
+		"other[0]": int32(20), // not really an array so reading 2 elements from here fails
+		"other[1]": int32(21),
+	})
+	defer client.Disconnect()
+
+	tags := []string{"Arr[0]", "Count", "Arr[2]", "Arr[1]", "Arr[9]", "Other[0]", "Other[1]"}
+	values, errs := client.ReadMultiple(tags)
+	want := []any{int32(10), int32(7), int32(12), int32(11), nil, int32(20), int32(21)}
+	for i := range tags {
+		if i == 4 {
+			if !IsTagNotFound(errs[i]) {
+				t.Errorf("%s: wanted tag not found. got %v", tags[i], errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("%s: unexpected error %v", tags[i], errs[i])
+			continue
+		}
+		if values[i] != want[i] {
+			t.Errorf("%s: wanted %v got %v", tags[i], want[i], values[i])
+		}
+	}
+}
+
+func TestReadMultipleTyped(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{"setpoint": float32(1.5), "count": int32(7), "name": "abc"})
+	defer client.Disconnect()
+
+	tags := []string{"Setpoint", "Count", "Name", "Count", "Missing"}
+	types := []CIPType{CIPTypeREAL, CIPTypeDINT, CIPTypeSTRING, CIPTypeREAL, CIPTypeDINT}
+	values, errs := client.ReadMultipleTyped(tags, types)
+	if values[0].(float32) != 1.5 || values[1].(int32) != 7 || values[2].(string) != "abc" {
+		t.Errorf("wrong values %v (errors %v)", values, errs)
+	}
+	if errs[3] == nil || values[3] != nil {
+		t.Errorf("reading a DINT as a REAL should be a type mismatch. got %v %v", values[3], errs[3])
+	}
+	if !IsTagNotFound(errs[4]) {
+		t.Errorf("wanted tag not found. got %v", errs[4])
+	}
+
+	_, errs = client.ReadMultipleTyped(tags, types[:2])
+	if errs[0] == nil {
+		t.Errorf("a type for each tag should be required")
+	}
+}
+
+func TestReadFields(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{"setpoint": float32(1.5), "line1.count": int32(7), "name": "abc"})
+	defer client.Disconnect()
+
+	type poll struct {
+		Setpoint float32
+		Count    int32 `gologix:"Line1.Count"`
+		Name     string
+		Skipped  int32 `gologix:"-"`
+		internal int32
+	}
+	p, err := ReadFields[poll](client)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if p.Setpoint != 1.5 || p.Count != 7 || p.Name != "abc" {
+		t.Errorf("wrong values %+v", p)
+	}
+
+	type bad struct {
+		Setpoint float32
+		Missing  int32
+		Wrong    map[string]int
+	}
+	b, err := ReadFields[bad](client)
+	if err == nil || !strings.Contains(err.Error(), "Missing") || !strings.Contains(err.Error(), "Wrong") {
+		t.Errorf("wanted errors naming Missing and Wrong. got %v", err)
+	}
+	if b.Setpoint != 1.5 {
+		t.Errorf("good fields should still be read. got %+v", b)
+	}
+}
+
+func TestCountIOIsThatFit(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.knownFirmware = 32
+	client.ConnectionSize = 200
+
+	fit := func(name string) int {
+		tags := make([]tagDesc, 50)
+		for i := range tags {
+			tags[i] = tagDesc{TagName: fmt.Sprintf("%s%02d", name, i), TagType: CIPTypeDINT, Elements: 1}
+		}
+		n, err := client.countIOIsThatFit(tags)
+		if err != nil {
+			t.Fatalf("problem counting: %v", err)
+		}
+		return n
+	}
+	short := fit("T")
+	long := fit(strings.Repeat("Long_Symbolic_Name_", 3))
+	if long >= short {
+		t.Errorf("fewer tags with long names should fit. got %d long and %d short", long, short)
+	}
+	// "Txx" is a 6 byte path so each read is 12 bytes of request with its jump table entry after the 10 byte header.
+	// 16 would be 202 bytes.
+	if short != 15 {
+		t.Errorf("wanted 15 short tags to fit. got %d", short)
+	}
+
+	// a tag that won't fit at all is still taken on its own so the caller can make progress.
+	big := []tagDesc{{TagName: "Big", TagType: CIPTypeDINT, Elements: 100}, {TagName: "Small", TagType: CIPTypeDINT, Elements: 1}}
+	if n, _ := client.countIOIsThatFit(big); n != 1 {
+		t.Errorf("wanted the big read on its own. got %d", n)
+	}
+}
+
+// every multi-service request and reply has to fit in the connection size and the values still come back in the
+// order asked for.
+func TestReadMultipleSplits(t *testing.T) {
+	data := make(map[string]any)
+	tags := make([]string, 0)
+	for i := 0; i < 60; i++ {
+		name := fmt.Sprintf("Tag%d", i)
+		if i%3 == 0 {
+			name = fmt.Sprintf("A_Much_Longer_Symbolic_Tag_Name_%d", i)
+		}
+		data[strings.ToLower(name)] = int32(i)
+		tags = append(tags, name)
+	}
+	_, client := newTestServer(t, data)
+	defer client.Disconnect()
+	client.ConnectionSize = 200
+	obs := &testObserver{}
+	client.Metrics = obs
+
+	values, errs := client.ReadMultiple(tags)
+	for i := range tags {
+		if errs[i] != nil {
+			t.Errorf("%s: unexpected error %v", tags[i], errs[i])
+			continue
+		}
+		if values[i] != int32(i) {
+			t.Errorf("%s: wanted %d got %v", tags[i], i, values[i])
+		}
+	}
+
+	// the encapsulation header, the interface handle, timeout, and item count, the address item, and the data item
+	// header come before the connected data.
+	const overhead = 24 + 8 + 8 + 4
+	requests := 0
+	for _, s := range obs.stats {
+		if s.Service != CIPService_MultipleService {
+			continue
+		}
+		requests++
+		if s.BytesSent-overhead > int(client.ConnectionSize) || s.BytesReceived-overhead > int(client.ConnectionSize) {
+			t.Errorf("request of %d bytes and reply of %d bytes don't fit in %d", s.BytesSent-overhead, s.BytesReceived-overhead, client.ConnectionSize)
+		}
+	}
+	if requests < 2 {
+		t.Errorf("wanted the reads split into more than one request. got %d", requests)
+	}
+}
@@ -56,7 +56,11 @@ func (p CIPPort) Bytes() []byte {
 
 // This function takes a CIP path in the format of 0,1,192.168.2.1,0,1 and converts it into the proper equivalent byte slice.
 //
-// The most common use is probably setting up the communication path on a new client.
+// The most common use is probably setting up the communication path on a new client.  Each hop is a port and a link
+// address.  Port 1 is the backplane of a chassis where the link is the slot number, and port 2 is the ethernet port of
+// a bridge module where the link is an ip address.  For example "1,2" is the processor in slot 2 and
+// "1,0,2,10.0.0.5,1,0" goes through the bridge in slot 0 out to another chassis at 10.0.0.5 and the processor in its
+// slot 0.
 func ParsePath(path string) (*bytes.Buffer, error) {
 	if path == "" {
 		return new(bytes.Buffer), nil
@@ -83,6 +87,10 @@ func ParsePath(path string) (*bytes.Buffer, error) {
 			byte_path = append(byte_path, byte(l))
 			string_bytes := []byte(part)
 			byte_path = append(byte_path, string_bytes...)
+			// the address is padded out to a whole number of words.
+			if l%2 == 1 {
+				byte_path = append(byte_path, 0)
+			}
 			continue
 		}
 		// not an IP address
@@ -95,6 +103,9 @@ func ParsePath(path string) (*bytes.Buffer, error) {
 		}
 		byte_path = append(byte_path, byte(val))
 	}
+	if len(byte_path)%2 != 0 {
+		return nil, fmt.Errorf("path %v is %d bytes. it needs to be a whole number of 16 bit words", path, len(byte_path))
+	}
 
 	return bytes.NewBuffer(byte_path), nil
 }
@@ -22,6 +22,15 @@ func TestPath(t *testing.T) {
 			"1,0",
 			[]byte{0x01, 0x00},
 		},
+		{
+			"1,2",
+			[]byte{0x01, 0x02},
+		},
+		{
+			// odd length addresses get a pad byte
+			"1,0,2,10.1.2.33,1,2",
+			[]byte{0x01, 0x00, 0x12, 0x09, 0x31, 0x30, 0x2E, 0x31, 0x2E, 0x32, 0x2E, 0x33, 0x33, 0x00, 0x01, 0x02},
+		},
 	}
 
 	for _, tt := range tests {
@@ -40,6 +49,41 @@ func TestPath(t *testing.T) {
 
 }
 
+func TestPathErrors(t *testing.T) {
+	for _, path := range []string{"1", "1,0,1", "1,256", "1,x"} {
+		_, err := ParsePath(path)
+		if err == nil {
+			t.Errorf("%q: expected an error", path)
+		}
+	}
+}
+
+// the route should end up in front of the message router in the forward open's connection path.
+func TestNewClientWithPath(t *testing.T) {
+	client, err := NewClientWithPath("127.0.0.1", "1,0,1,2")
+	if err != nil {
+		t.Fatalf("problem creating client: %v", err)
+	}
+	item, err := client.newForwardOpenLarge()
+	if err != nil {
+		t.Fatalf("problem building forward open: %v", err)
+	}
+	var msg cipForwardOpen[uint32]
+	err = item.DeSerialize(&msg)
+	if err != nil {
+		t.Fatalf("problem reading forward open: %v", err)
+	}
+	want := []byte{0x01, 0x00, 0x01, 0x02, 0x20, 0x02, 0x24, 0x01}
+	if int(msg.ConnPathSize) != len(want)/2 || !check_bytes(item.Rest(), want) {
+		t.Errorf("wanted path %v got %d words %v", want, msg.ConnPathSize, item.Rest())
+	}
+
+	_, err = NewClientWithPath("127.0.0.1", "1,0,1")
+	if err == nil {
+		t.Errorf("odd length path should fail")
+	}
+}
+
 func check_bytes(s0, s1 []byte) bool {
 	if len(s1) != len(s0) {
 		return false
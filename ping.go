@@ -0,0 +1,58 @@
+package gologix
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Check that the session and cip connection to the controller are still up.  This reads the vendor ID of the identity
+// object, which is the same small request the connection heartbeat sends, so nothing on the controller changes.
+//
+// If the ping fails and AutoReconnect is set the client reconnects and pings again so a nil error means the connection
+// is good now.  Without AutoReconnect the error is returned and the client is left as it was.  Ping never connects a
+// client that hasn't been connected yet unless AutoReconnect is set.
+func (client *Client) Ping() error {
+	if !client.connected && !client.AutoReconnect {
+		return errors.New("ping failed: not connected")
+	}
+	gen := client.connGeneration.Load()
+	err := client.ping()
+	if err == nil {
+		return nil
+	}
+	if !client.AutoReconnect {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	// the send path may have reconnected already.  If so this is a no-op.
+	rerr := client.reconnect(gen, err)
+	if rerr != nil {
+		return fmt.Errorf("ping failed: %w: reconnect failed: %w", err, rerr)
+	}
+	err = client.ping()
+	if err != nil {
+		return fmt.Errorf("ping failed after reconnecting: %w", err)
+	}
+	return nil
+}
+
+// send one ping over the current connection without trying to connect.
+func (client *Client) ping() error {
+	if !client.connected {
+		return errors.New("not connected")
+	}
+	item, err := client.GetAttrSingle(CipObject_Identity, 1, 1)
+	if err != nil {
+		return err
+	}
+	item.Reset()
+	var hdr cipAttributeResponseHdr
+	err = item.DeSerialize(&hdr)
+	if err != nil {
+		return fmt.Errorf("problem reading ping reply header. %w", err)
+	}
+	// the low byte is the general status and the high byte the extended status size.
+	if CIPStatus(byte(hdr.Status)) != CIPStatus_OK {
+		return &CIPError{General: byte(hdr.Status)}
+	}
+	return nil
+}
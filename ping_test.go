@@ -0,0 +1,33 @@
+package gologix
+
+import "testing"
+
+func TestPing(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{})
+	defer client.Disconnect()
+
+	err := client.Ping()
+	if err != nil {
+		t.Fatalf("problem pinging: %v", err)
+	}
+
+	// without AutoReconnect a dropped connection is just reported.
+	client.Disconnect()
+	client.AutoReconnect = false
+	err = client.Ping()
+	if err == nil {
+		t.Fatalf("ping should fail when not connected")
+	}
+	if client.connected {
+		t.Fatalf("ping shouldn't connect without AutoReconnect")
+	}
+
+	client.AutoReconnect = true
+	err = client.Ping()
+	if err != nil {
+		t.Fatalf("ping should have reconnected: %v", err)
+	}
+	if !client.connected {
+		t.Errorf("not connected after ping reconnected")
+	}
+}
@@ -0,0 +1,115 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// attributes of the WallClockTime object (class 0x8B instance 1) for the controller's clock.
+const (
+	wallClockLocal  CIPAttribute = 0x01 // microseconds since 1970 in the controller's local time (CurrentValue)
+	wallClockSetUTC CIPAttribute = 0x06 // microseconds since 1970 UTC.  This is the one to set
+	wallClockGetUTC CIPAttribute = 0x0B // microseconds since 1970 UTC.  This is the one to read
+)
+
+// Read the controller's clock from its WallClockTime object.
+//
+// The controller keeps its clock as microseconds since 1970 UTC so that is what you get back, as a time.Time in UTC.
+// What the controller shows as its local time depends on the time zone and daylight saving settings in the project
+// which don't change this value.
+func (client *Client) GetPLCTime() (time.Time, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not get plc time: %w", err)
+	}
+	item, err := client.GetAttrList(CipObject_TIME, 1, wallClockGetUTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("problem reading wall clock: %w", err)
+	}
+	return parseWallClockReply(item)
+}
+
+// Set the controller's clock in its WallClockTime object.
+//
+// t is sent as microseconds since 1970 UTC no matter what location it is in.  The controller turns that into local
+// time with its own time zone settings.  You need to be connected with write access to the controller.
+func (client *Client) SetPLCTime(t time.Time) error {
+	err := client.checkConnection()
+	if err != nil {
+		return fmt.Errorf("could not set plc time: %w", err)
+	}
+	err = client.SetAttrList(CipObject_TIME, 1, AttributeValue{Attribute: wallClockSetUTC, Data: wallClockValue(t)})
+	if err != nil {
+		return fmt.Errorf("problem setting wall clock: %w", err)
+	}
+	return nil
+}
+
+// Read how far the controller's local time is ahead of UTC, daylight saving included, from its WallClockTime object.
+//
+// The local and UTC clocks are read in one request and the difference is rounded to the minute.  Use it to put a UTC
+// time in the controller's time zone
+//
+//	plc := time.FixedZone("PLC", int(offset.Seconds()))
+//	local := t.In(plc)
+//
+// or to turn a timestamp the controller kept in local time into UTC with LogixWallClock.Time(v).Add(-offset).  The
+// offset changes when daylight saving starts or ends so read it again rather than keeping it forever.
+func (client *Client) GetPLCTimeZoneOffset() (time.Duration, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return 0, fmt.Errorf("could not get plc time zone: %w", err)
+	}
+	item, err := client.GetAttrList(CipObject_TIME, 1, wallClockLocal, wallClockGetUTC)
+	if err != nil {
+		return 0, fmt.Errorf("problem reading wall clock: %w", err)
+	}
+	return parseTimeZoneReply(item)
+}
+
+// parse the GetAttrList reply for the local and UTC wall clocks into the offset between them.  The item should be
+// positioned after the attribute count.
+func parseTimeZoneReply(item *CIPItem) (time.Duration, error) {
+	local, err := readWallClockAttr(item)
+	if err != nil {
+		return 0, err
+	}
+	utc, err := readWallClockAttr(item)
+	if err != nil {
+		return 0, err
+	}
+	offset := time.Duration(local-utc) * time.Microsecond
+	return offset.Round(time.Minute), nil
+}
+
+// the wall clock value for t.
+func wallClockValue(t time.Time) []byte {
+	return binary.LittleEndian.AppendUint64(nil, uint64(LogixWallClock.Value(t)))
+}
+
+// parse the GetAttrList reply for the wall clock.  The item should be positioned after the attribute count.
+func parseWallClockReply(item *CIPItem) (time.Time, error) {
+	v, err := readWallClockAttr(item)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return LogixWallClock.Time(v).UTC(), nil
+}
+
+// read one attribute of a GetAttrList reply for the wall clock.  They are all microseconds since 1970.
+func readWallClockAttr(item *CIPItem) (int64, error) {
+	var reply struct {
+		Attribute CIPAttribute
+		Status    uint16
+		Value     int64
+	}
+	err := item.DeSerialize(&reply)
+	if err != nil {
+		return 0, fmt.Errorf("problem reading wall clock reply. %w", err)
+	}
+	if reply.Status != 0 {
+		return 0, fmt.Errorf("problem reading wall clock attribute %d: %w", reply.Attribute, &CIPError{General: byte(reply.Status)})
+	}
+	return reply.Value, nil
+}
@@ -0,0 +1,82 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestParseWallClockReply(t *testing.T) {
+	when := time.Date(2024, 3, 10, 14, 30, 0, 123456000, time.UTC)
+	item := CIPItem{}
+	item.Serialize(uint16(wallClockGetUTC))
+	item.Serialize(uint16(0))
+	item.Serialize(when.UnixMicro())
+	have, err := parseWallClockReply(&item)
+	if err != nil {
+		t.Fatalf("problem parsing reply: %v", err)
+	}
+	if !have.Equal(when) || have.Location() != time.UTC {
+		t.Errorf("wanted %v got %v", when, have)
+	}
+
+	item = CIPItem{}
+	item.Serialize(uint16(wallClockGetUTC))
+	item.Serialize(uint16(CIPStatus_AttributeNotSupported))
+	item.Serialize(int64(0))
+	_, err = parseWallClockReply(&item)
+	if general, ok := cipGeneral(err); !ok || general != CIPStatus_AttributeNotSupported {
+		t.Errorf("wanted attribute not supported. got %v", err)
+	}
+}
+
+func TestWallClockValue(t *testing.T) {
+	// the location doesn't matter.  it always goes as UTC.
+	est := time.FixedZone("EST", -5*60*60)
+	when := time.Date(2024, 3, 10, 9, 30, 0, 0, est)
+	have := binary.LittleEndian.Uint64(wallClockValue(when))
+	if have != uint64(when.UnixMicro()) {
+		t.Errorf("wanted %d got %d", when.UnixMicro(), have)
+	}
+}
+
+func TestParseTimeZoneReply(t *testing.T) {
+	utc := time.Date(2024, 7, 4, 18, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		name   string
+		local  time.Time
+		status uint16
+		want   time.Duration
+		fail   bool
+	}{
+		{"eastern daylight", utc.Add(-4 * time.Hour), 0, -4 * time.Hour, false},
+		{"india", utc.Add(5*time.Hour + 30*time.Minute), 0, 5*time.Hour + 30*time.Minute, false},
+		// the two clocks are read a moment apart.
+		{"a few microseconds late", utc.Add(time.Hour + 7*time.Microsecond), 0, time.Hour, false},
+		{"utc", utc, 0, 0, false},
+		{"not supported", utc, uint16(CIPStatus_AttributeNotSupported), 0, true},
+	}
+	for _, tt := range tests {
+		item := CIPItem{}
+		item.Serialize(uint16(wallClockLocal))
+		item.Serialize(tt.status)
+		item.Serialize(tt.local.UnixMicro())
+		item.Serialize(uint16(wallClockGetUTC))
+		item.Serialize(uint16(0))
+		item.Serialize(utc.UnixMicro())
+		have, err := parseTimeZoneReply(&item)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("%s: expected an error. got %v", tt.name, have)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: problem parsing: %v", tt.name, err)
+			continue
+		}
+		if have != tt.want {
+			t.Errorf("%s: wanted %v got %v", tt.name, tt.want, have)
+		}
+	}
+}
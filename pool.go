@@ -0,0 +1,109 @@
+package gologix
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Pool is a set of independent sessions to one controller.  Every session has its own registered session and
+// forward open connection so requests on different sessions are handled by the controller in parallel.
+//
+// Read and Write grab whichever session is idle, waiting for one if they are all busy.  Use NewPool to create one.
+type Pool struct {
+	clients []*Client
+	idle    chan *Client
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// Open up to size sessions to the controller at ip.
+//
+// Controllers only allow so many connections.  If the controller refuses a connection after the first one came up
+// the pool just has fewer sessions than requested.  See Size.  If not even the first one can connect the error from
+// that attempt is returned.
+func NewPool(ip string, size int) (*Pool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("pool size must be at least 1. got %d", size)
+	}
+	p := &Pool{
+		idle: make(chan *Client, size),
+		done: make(chan struct{}),
+	}
+	for i := 0; i < size; i++ {
+		// Connect gives each client its own random originator serial so the connections don't look like duplicates
+		// to the controller.
+		client := NewClient(ip)
+		err := client.Connect()
+		if err != nil {
+			if i == 0 {
+				return nil, fmt.Errorf("couldn't open first pool connection: %w", err)
+			}
+			client.Logger.Warn("controller refused another pool connection", "wanted", size, "have", i, "error", err)
+			break
+		}
+		p.clients = append(p.clients, client)
+		p.idle <- client
+	}
+	return p, nil
+}
+
+// The number of sessions in the pool.  This can be less than the size asked for in NewPool.
+func (p *Pool) Size() int {
+	return len(p.clients)
+}
+
+// Read a tag using an idle session.  See Client.Read
+func (p *Pool) Read(tag string, data any) error {
+	client, err := p.acquire()
+	if err != nil {
+		return err
+	}
+	defer p.release(client)
+	return client.Read(tag, data)
+}
+
+// Write a tag using an idle session.  See Client.Write
+func (p *Pool) Write(tag string, value any) error {
+	client, err := p.acquire()
+	if err != nil {
+		return err
+	}
+	defer p.release(client)
+	return client.Write(tag, value)
+}
+
+// wait for an idle session.
+func (p *Pool) acquire() (*Client, error) {
+	select {
+	case <-p.done:
+		return nil, errors.New("pool is closed")
+	default:
+	}
+	select {
+	case client := <-p.idle:
+		return client, nil
+	case <-p.done:
+		return nil, errors.New("pool is closed")
+	}
+}
+
+func (p *Pool) release(client *Client) {
+	p.idle <- client
+}
+
+// Disconnect every session in the pool.  Reads and writes waiting on a session get an error.
+func (p *Pool) Close() error {
+	var errs []error
+	p.closeOnce.Do(func() {
+		close(p.done)
+		for _, client := range p.clients {
+			err := client.Disconnect()
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
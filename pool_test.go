@@ -0,0 +1,42 @@
+package gologix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPoolErrors(t *testing.T) {
+	_, err := NewPool("127.0.0.1", 0)
+	if err == nil {
+		t.Errorf("a pool of size 0 should be an error")
+	}
+}
+
+// somebody waiting on a session when the pool closes should get an error instead of hanging.
+func TestPoolCloseUnblocks(t *testing.T) {
+	p := &Pool{idle: make(chan *Client, 1), done: make(chan struct{})}
+
+	result := make(chan error)
+	go func() {
+		var v int32
+		result <- p.Read("TestDint", &v)
+	}()
+
+	time.Sleep(time.Millisecond * 10)
+	err := p.Close()
+	if err != nil {
+		t.Errorf("problem closing empty pool: %v", err)
+	}
+	select {
+	case err = <-result:
+		if err == nil {
+			t.Errorf("read on a closed pool should fail")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("read didn't return after the pool closed")
+	}
+
+	if p.Close() != nil {
+		t.Errorf("closing twice should be harmless")
+	}
+}
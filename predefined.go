@@ -0,0 +1,46 @@
+package gologix
+
+import (
+	"fmt"
+
+	"github.com/danomagnum/gologix/lgxtypes"
+)
+
+// The predefined TIMER, COUNTER, and CONTROL structures.  The status bits (EN, TT, DN, and so on) are packed into the
+// control DINT at the start of the structure with EN in bit 31 and the rest counting down from there.  Write takes
+// any of them and packs the bits back the same way.
+type (
+	Timer   = lgxtypes.TIMER
+	Counter = lgxtypes.COUNTER
+	Control = lgxtypes.CONTROL
+)
+
+// Read a TIMER tag.
+func (client *Client) ReadTimer(tag string) (Timer, error) {
+	var t Timer
+	err := client.Read(tag, &t)
+	if err != nil {
+		return Timer{}, fmt.Errorf("problem reading timer %s: %w", tag, err)
+	}
+	return t, nil
+}
+
+// Read a COUNTER tag.
+func (client *Client) ReadCounter(tag string) (Counter, error) {
+	var c Counter
+	err := client.Read(tag, &c)
+	if err != nil {
+		return Counter{}, fmt.Errorf("problem reading counter %s: %w", tag, err)
+	}
+	return c, nil
+}
+
+// Read a CONTROL tag.
+func (client *Client) ReadControl(tag string) (Control, error) {
+	var c Control
+	err := client.Read(tag, &c)
+	if err != nil {
+		return Control{}, fmt.Errorf("problem reading control %s: %w", tag, err)
+	}
+	return c, nil
+}
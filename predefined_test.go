@@ -0,0 +1,124 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// each status bit should land in its own bit of the control DINT and come back out of it.
+func TestPredefinedBits(t *testing.T) {
+	var tests = []struct {
+		name  string
+		value any
+		bit   int
+	}{
+		{"timer EN", Timer{EN: true}, 31},
+		{"timer TT", Timer{TT: true}, 30},
+		{"timer DN", Timer{DN: true}, 29},
+		{"timer FS", Timer{FS: true}, 28},
+		{"timer ER", Timer{ER: true}, 25},
+		{"counter CU", Counter{CU: true}, 31},
+		{"counter CD", Counter{CD: true}, 30},
+		{"counter DN", Counter{DN: true}, 29},
+		{"counter OV", Counter{OV: true}, 28},
+		{"counter UN", Counter{UN: true}, 27},
+		{"control EN", Control{EN: true}, 31},
+		{"control EU", Control{EU: true}, 30},
+		{"control DN", Control{DN: true}, 29},
+		{"control IN", Control{IN: true}, 25},
+		{"control FD", Control{FD: true}, 24},
+	}
+	for _, tt := range tests {
+		b := bytes.Buffer{}
+		_, err := Pack(&b, tt.value)
+		if err != nil {
+			t.Errorf("%s: problem packing: %v", tt.name, err)
+			continue
+		}
+		if b.Len() != 12 {
+			t.Errorf("%s: wanted 12 bytes got %d", tt.name, b.Len())
+			continue
+		}
+		if have := binary.LittleEndian.Uint32(b.Bytes()); have != 1<<tt.bit {
+			t.Errorf("%s: wanted control word %08X got %08X", tt.name, uint32(1)<<tt.bit, have)
+		}
+
+		var back any
+		switch tt.value.(type) {
+		case Timer:
+			var v Timer
+			_, err = Unpack(&b, &v)
+			back = v
+		case Counter:
+			var v Counter
+			_, err = Unpack(&b, &v)
+			back = v
+		case Control:
+			var v Control
+			_, err = Unpack(&b, &v)
+			back = v
+		}
+		if err != nil || back != tt.value {
+			t.Errorf("%s: wanted %+v back got %+v (%v)", tt.name, tt.value, back, err)
+		}
+	}
+}
+
+func TestReadWriteTimer(t *testing.T) {
+	client, remote := newPipeClient(t)
+
+	requests := make(chan []byte, 2)
+	go func() {
+		for {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				return
+			}
+			req := buf.Bytes()[20:]
+			requests <- req
+			item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+			if CIPService(req[2]) == CIPService_Write {
+				item.Serialize(msgWriteResultHeader{Service: CIPService_Write.AsResponse()})
+			} else {
+				item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: CIPTypeStruct, Unknown: 0x02})
+				item.Serialize(uint16(0x0F83))
+				item.Serialize([]uint32{1<<31 | 1<<29, 5000, 1234})
+			}
+			b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+			if err != nil {
+				t.Errorf("problem building reply: %v", err)
+				return
+			}
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}
+	}()
+
+	done := make(chan error)
+	go func() {
+		tmr, err := client.ReadTimer("Delay")
+		if err == nil && (tmr != Timer{PRE: 5000, ACC: 1234, EN: true, DN: true}) {
+			t.Errorf("wrong timer %+v", tmr)
+		}
+		if err == nil {
+			err = client.Write("Delay", Timer{PRE: 300, TT: true})
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("problem with timer: %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("timed out")
+	}
+
+	<-requests
+	req := <-requests
+	want := []byte{byte(CIPTypeStruct), 0x02, 0x83, 0x0F, 0x01, 0x00, 0x00, 0x00, 0x00, 0x40, 0x2C, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	if !check_bytes(req[len(req)-len(want):], want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(req))
+	}
+}
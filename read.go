@@ -5,7 +5,10 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
+	"time"
 )
 
 // Read a single tag into data.  Data should be a pointer to the variable where the data will be deposited.
@@ -16,90 +19,98 @@ import (
 //
 // If the data type does not match what is returned by the controller you will get an error.
 func (client *Client) Read(tag string, data any) error {
+	return client.ReadWithTimeout(tag, data, 0)
+}
+
+// Read a single tag into data like Read but wait at most timeout for the controller to respond.
+//
+// A timeout of 0 uses ReadTimeout.  If the controller doesn't respond in time you get ErrTimeout and the
+// session stays up.
+func (client *Client) ReadWithTimeout(tag string, data any, timeout time.Duration) error {
 	err := client.checkConnection()
 	if err != nil {
 		return fmt.Errorf("could not start read: %w", err)
 	}
 	switch data := data.(type) {
 	case *bool:
-		v, err := read[bool](client, tag)
+		v, err := read[bool](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *byte:
-		v, err := read[byte](client, tag)
+		v, err := read[byte](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *int8:
-		v, err := read[int8](client, tag)
+		v, err := read[int8](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *int16:
-		v, err := read[int16](client, tag)
+		v, err := read[int16](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *uint16:
-		v, err := read[uint16](client, tag)
+		v, err := read[uint16](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *int32:
-		v, err := read[int32](client, tag)
+		v, err := read[int32](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *uint32:
-		v, err := read[uint32](client, tag)
+		v, err := read[uint32](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *int64:
-		v, err := read[int64](client, tag)
+		v, err := read[int64](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *uint64:
-		v, err := read[uint64](client, tag)
+		v, err := read[uint64](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *float32:
-		v, err := read[float32](client, tag)
+		v, err := read[float32](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *float64:
-		v, err := read[float64](client, tag)
+		v, err := read[float64](client, tag, timeout)
 		if err != nil {
 			return err
 		}
 		*data = v
 		return nil
 	case *string:
-		v, err := read[string](client, tag)
+		v, err := read[string](client, tag, timeout)
 		if err != nil {
 			return err
 		}
@@ -113,7 +124,7 @@ func (client *Client) Read(tag string, data any) error {
 			return fmt.Errorf("slice length must be a multiple of 32 for []bool, got %d", elements)
 		}
 		if count == 1 { // special case for 1 element slice - have to read it as an atomic uint32
-			v, err := read[uint32](client, tag)
+			v, err := read[uint32](client, tag, timeout)
 			if err != nil {
 				return err
 			}
@@ -122,7 +133,7 @@ func (client *Client) Read(tag string, data any) error {
 			}
 			return nil
 		}
-		v, err := readArray[uint32](client, tag, uint16(count))
+		v, err := readArray[uint32](client, tag, uint16(count), timeout)
 		if err != nil {
 			return err
 		}
@@ -138,7 +149,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []byte:
 		elements := len(data)
-		v, err := readArray[byte](client, tag, uint16(elements))
+		v, err := readArray[byte](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -151,7 +162,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []int8:
 		elements := len(data)
-		v, err := readArray[int8](client, tag, uint16(elements))
+		v, err := readArray[int8](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -164,7 +175,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []int16:
 		elements := len(data)
-		v, err := readArray[int16](client, tag, uint16(elements))
+		v, err := readArray[int16](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -177,7 +188,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []uint16:
 		elements := len(data)
-		v, err := readArray[uint16](client, tag, uint16(elements))
+		v, err := readArray[uint16](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -190,7 +201,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []int32:
 		elements := len(data)
-		v, err := readArray[int32](client, tag, uint16(elements))
+		v, err := readArray[int32](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -203,7 +214,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []uint32:
 		elements := len(data)
-		v, err := readArray[uint32](client, tag, uint16(elements))
+		v, err := readArray[uint32](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -216,7 +227,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []int64:
 		elements := len(data)
-		v, err := readArray[int64](client, tag, uint16(elements))
+		v, err := readArray[int64](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -229,7 +240,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []uint64:
 		elements := len(data)
-		v, err := readArray[uint64](client, tag, uint16(elements))
+		v, err := readArray[uint64](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -242,7 +253,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []float32:
 		elements := len(data)
-		v, err := readArray[float32](client, tag, uint16(elements))
+		v, err := readArray[float32](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -255,7 +266,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []float64:
 		elements := len(data)
-		v, err := readArray[float64](client, tag, uint16(elements))
+		v, err := readArray[float64](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -268,7 +279,7 @@ func (client *Client) Read(tag string, data any) error {
 		return nil
 	case []string:
 		elements := len(data)
-		v, err := readArray[string](client, tag, uint16(elements))
+		v, err := readArray[string](client, tag, uint16(elements), timeout)
 		if err != nil {
 			return err
 		}
@@ -282,7 +293,7 @@ func (client *Client) Read(tag string, data any) error {
 
 	case []interface{}:
 		// a pointer to a struct.
-		val, err := client.Read_single(tag, CIPTypeStruct, 1)
+		val, err := client.read_single(tag, CIPTypeStruct, 1, timeout)
 		if err != nil {
 			return err
 		}
@@ -292,7 +303,7 @@ func (client *Client) Read(tag string, data any) error {
 		}
 		b := bytes.NewBuffer(cast)
 		//err = binary.Read(b, binary.LittleEndian, data)
-		_, err = Unpack(b, data)
+		_, err = cipPack{order: client.byteOrder()}.unpack(b, data)
 		if err != nil {
 			return fmt.Errorf("couldn't parse str data. %w", err)
 		}
@@ -302,7 +313,7 @@ func (client *Client) Read(tag string, data any) error {
 	switch v.Kind() {
 	case reflect.Pointer:
 		// a pointer to a struct.
-		val, err := client.Read_single(tag, CIPTypeStruct, 1)
+		val, err := client.read_single(tag, CIPTypeStruct, 1, timeout)
 		if err != nil {
 			return err
 		}
@@ -313,7 +324,7 @@ func (client *Client) Read(tag string, data any) error {
 		b := bytes.NewBuffer(cast)
 
 		//err = binary.Read(b, binary.LittleEndian, data)
-		_, err = Unpack(b, data)
+		_, err = cipPack{order: client.byteOrder()}.unpack(b, data)
 		if err != nil {
 			return fmt.Errorf("couldn't parse str data. %w", err)
 		}
@@ -321,7 +332,7 @@ func (client *Client) Read(tag string, data any) error {
 	case reflect.Slice:
 		// slice of structs.
 		elements := uint16(v.Len())
-		val, err := client.Read_single(tag, CIPTypeStruct, elements)
+		val, err := client.read_single(tag, CIPTypeStruct, elements, timeout)
 		if err != nil {
 			return err
 		}
@@ -332,7 +343,7 @@ func (client *Client) Read(tag string, data any) error {
 
 		b := bytes.NewBuffer(dat)
 		//TODO: unpack here instead of just a read.
-		err = binary.Read(b, binary.LittleEndian, data)
+		err = binary.Read(b, client.byteOrder(), data)
 		if err != nil {
 			return fmt.Errorf("couldn't parse str data element %w", err)
 		}
@@ -346,137 +357,316 @@ func (client *Client) Read(tag string, data any) error {
 //
 // To read data of an unknown type, use CIPTypeUnknown for the data type.
 //
-// The data is returned as an interface{} so you'll probably have to type assert it.
+// The data is returned as an interface{} so you'll probably have to type assert it.  Whether it is one value or a
+// slice goes by elements, not by whether the tag is an array:
+//
+//	elements 1      one value.  "Arr[3]" is element 3 and a bare "Arr" is its first element.
+//	elements n > 1  a []any of n elements starting at the one the tag names ([]string for STRINGs).
+//	elements 0      the whole tag.  A bare array name gives a []any of every element, even if there is only one
+//	                ([]bool for a BOOL array and []string for STRINGs).  Anything else gives one value.
+//
+// Structures always come back as their raw bytes.  For elements 0 the size of the array comes from KnownTags or, for a
+// tag that isn't there, from GetTagAttributes.  The typed reads take their count from what they read into so Read
+// with a *int32 is one element and with a []int32 is len elements.
 func (client *Client) Read_single(tag string, datatype CIPType, elements uint16) (any, error) {
 
 	err := client.checkConnection()
 	if err != nil {
 		return nil, fmt.Errorf("could not start single read: %w", err)
 	}
+	if elements == 0 {
+		return client.readWholeTag(tag, datatype)
+	}
+	return client.read_single(tag, datatype, elements, 0)
+}
 
-	ioi, err := client.newIOI(tag, datatype)
-
+// read every element of an array tag as a slice or a tag that isn't an array as one value.  See Read_single.
+func (client *Client) readWholeTag(tag string, datatype CIPType) (any, error) {
+	count, err := client.arrayLength(tag)
 	if err != nil {
 		return nil, err
 	}
-
-	reqItems := make([]CIPItem, 2)
-	reqItems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
-
-	readMsg := msgCIPConnectedServiceReq{
-		SequenceCount: uint16(sequencer()),
-		Service:       CIPService_Read,
-		PathLength:    byte(len(ioi.Bytes()) / 2),
+	if count == 0 {
+		return client.read_single(tag, datatype, 1, 0)
 	}
-	// setup item
-	reqItems[1] = newItem(cipItem_ConnectedData, readMsg)
-	// add path
-	reqItems[1].Serialize(ioi.Bytes())
-	// add service specific data
-	reqItems[1].Serialize(elements)
-
-	itemData, err := serializeItems(reqItems)
-	if err != nil {
-		return nil, err
+	if count > 0xFFFF {
+		return nil, fmt.Errorf("%s has %d elements which is more than one read can get. use ReadArray", tag, count)
 	}
-	hdr, data, err := client.send_recv_data(cipCommandSendUnitData, itemData)
-	if err != nil {
-		return nil, err
+	if datatype == CIPTypeBOOL {
+		// BOOL arrays are packed into DWORDs so they get read differently.
+		return client.ReadBoolArray(tag, count)
 	}
-	_ = hdr
+	val, err := client.read_single(tag, datatype, uint16(count), 0)
+	if err != nil || count > 1 {
+		return val, err
+	}
+	// a read of one element comes back as the element on its own.
+	switch v := val.(type) {
+	case []byte:
+		if datatype == CIPTypeSTRING {
+			return []string{string(v)}, nil
+		}
+		return v, nil
+	case []any:
+		return v, nil
+	}
+	return []any{val}, nil
+}
 
-	read_result_header := msgCIPResultHeader{}
-	err = binary.Read(data, binary.LittleEndian, &read_result_header)
-	if err != nil {
-		client.Logger.Warn("Problem reading read result header", "error", err)
+// the number of elements in the array a bare tag name is.  0 for a tag that isn't an array or that has an index or a
+// bit on the end.  Members of structures aren't listed on their own so they count as not being arrays.
+func (client *Client) arrayLength(tag string) (int, error) {
+	base, rest := splitTagBase(tag)
+	if len(rest) > 0 || !strings.EqualFold(base, tag) {
+		return 0, nil
 	}
-	items, err := readItems(data)
-	if err != nil {
-		client.Logger.Warn("Problem reading items", "error", err)
-		return 0, err
+	var dims []int
+	if known, ok := client.KnownTags[strings.ToLower(tag)]; ok {
+		dims = known.Array_Order
+	} else {
+		attrs, err := client.GetTagAttributes(tag)
+		if err != nil {
+			return 0, fmt.Errorf("problem finding the size of %s: %w", tag, err)
+		}
+		dims = attrs.Dimensions
 	}
-	if len(items) != 2 {
-		return 0, fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(items))
+	if len(dims) == 0 {
+		return 0, nil
 	}
-	var hdr2 msgCIPReadResultData
-	err = items[1].DeSerialize(&hdr2)
+	count := 1
+	for _, d := range dims {
+		count *= d
+	}
+	return count, nil
+}
+
+// timeout is how long to wait for the response.  0 uses ReadTimeout.
+func (client *Client) read_single(tag string, datatype CIPType, elements uint16, timeout time.Duration) (any, error) {
+	ioi, hdr2, item, err := client.read_reply(tag, datatype, elements, timeout)
 	if err != nil {
-		return 0, fmt.Errorf("problem reading item 2's header. %w", err)
+		return nil, err
 	}
+	return client.decodeRead(tag, datatype, elements, ioi, hdr2, item)
+}
 
+// turn the reply to a read of elements of datatype into go values.  item is positioned right after the read header.
+func (client *Client) decodeRead(tag string, datatype CIPType, elements uint16, ioi *tagIOI, hdr2 msgCIPReadResultData, item *CIPItem) (any, error) {
+	var err error
 	if hdr2.Type == CIPTypeStruct {
 		if datatype == CIPTypeSTRING {
 			if elements == 1 {
-				str_hdr := cipStringHeader{}
-				err = items[1].DeSerialize(&str_hdr)
+				str_hdr, err := readStringHeader(item, client.byteOrder())
 				if err != nil {
 					return nil, fmt.Errorf("couldn't unpack struct header. %w", err)
 				}
 				str := make([]byte, str_hdr.Length)
-				err = items[1].DeSerialize(&str)
+				err = item.DeSerialize(&str)
 				if err != nil {
 					return nil, fmt.Errorf("couldn't unpack struct data. %w", err)
 				}
 				return str, nil
 			}
-			response := make([]any, elements)
-
-			for i := 0; i < int(elements); i++ {
-				str_hdr := cipStringHeader{}
-				err = items[1].DeSerialize(&str_hdr)
-				if err != nil {
-					return nil, fmt.Errorf("couldn't unpack struct header. %w", err)
-				}
-				str := make([]byte, 82)
-				err = items[1].DeSerialize(&str)
-				if err != nil {
-					return nil, fmt.Errorf("couldn't unpack struct data. %w", err)
-				}
-				response[i] = str[:str_hdr.Length]
-			}
-			return response, nil
+			return client.decodeStringArray(tag, elements, item)
 		}
-		str_hdr := cipStructHeader{}
-		err = items[1].DeSerialize(&str_hdr)
+		_, err = readStructHandle(item, hdr2.Unknown)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't unpack struct header. %w", err)
 		}
-		str := items[1].Data[items[1].Pos:]
+		str := item.Data[item.Pos:]
 		return str, nil
 	}
 	if elements == 1 {
 		if datatype == CIPTypeBOOL && hdr2.Type != CIPTypeBOOL && ioi.BitAccess {
 			// we have requested a bool from some other type.  Maybe a bit access?
-			value, err := readValue(hdr2.Type, &items[1])
+			value, err := readValueOrder(hdr2.Type, item, client.byteOrder())
 			if err != nil {
 				return nil, fmt.Errorf("problem reading bool tag %s: %w", tag, err)
 			}
 			return getBit(hdr2.Type, value, ioi.BitPosition)
 		}
 		// not a struct so we can read the value directly
-		value, err := readValue(hdr2.Type, &items[1])
+		value, err := readValueOrder(hdr2.Type, item, client.byteOrder())
 		if err != nil {
 			return nil, fmt.Errorf("problem reading tag %s: %w", tag, err)
 		}
+		if client.TreatFloatSpecialsAsError {
+			err = checkFloatSpecial(tag, value)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return value, nil
 	} else {
 		value := make([]any, elements)
 		for i := 0; i < int(elements); i++ {
-			value[i], err = readValue(hdr2.Type, &items[1])
+			value[i], err = readValueOrder(hdr2.Type, item, client.byteOrder())
 			if err != nil {
 				return nil, fmt.Errorf("problem reading element %d of %s: %w", i, tag, err)
 			}
 
 		}
+		if client.TreatFloatSpecialsAsError {
+			err = checkFloatSpecial(tag, value)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return value, nil
 
 	}
 }
 
-func readArray[T GoLogixTypes](client *Client, tag string, elements uint16) ([]T, error) {
+// send a read request for tag and check the reply status.  A reply that is too big for one message is read again
+// with fragmented reads so the item always has all the data.  The item is left positioned right after the read
+// result header.
+//
+// Errors RetryPolicy says to retry are sent again.
+func (client *Client) read_reply(tag string, datatype CIPType, elements uint16, timeout time.Duration) (*tagIOI, msgCIPReadResultData, *CIPItem, error) {
+	var ioi *tagIOI
+	var hdr msgCIPReadResultData
+	var item *CIPItem
+	err := client.withRetry(tag, func() error {
+		var err error
+		ioi, hdr, item, err = client.read_reply_once(tag, datatype, elements, timeout)
+		return err
+	})
+	return ioi, hdr, item, err
+}
+
+func (client *Client) read_reply_once(tag string, datatype CIPType, elements uint16, timeout time.Duration) (*tagIOI, msgCIPReadResultData, *CIPItem, error) {
+	ioi, err := client.newIOI(tag, datatype)
+	if err != nil {
+		return nil, msgCIPReadResultData{}, nil, err
+	}
+	hdr2, item, err := client.read_ioi_once(tag, ioi, elements, timeout)
+	if err != nil {
+		return nil, hdr2, nil, err
+	}
+	return ioi, hdr2, item, nil
+}
+
+// send one read request for the path in ioi.  tag is only used in errors.
+func (client *Client) read_ioi_once(tag string, ioi *tagIOI, elements uint16, timeout time.Duration) (msgCIPReadResultData, *CIPItem, error) {
+	var hdr2 msgCIPReadResultData
+	reqItems := make([]CIPItem, 2)
+	reqItems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
+
+	readMsg := msgCIPConnectedServiceReq{
+		SequenceCount: uint16(sequencer()),
+		Service:       CIPService_Read,
+		PathLength:    byte(len(ioi.Bytes()) / 2),
+	}
+	// setup item
+	reqItems[1] = newItem(cipItem_ConnectedData, readMsg)
+	// add path
+	reqItems[1].Serialize(ioi.Bytes())
+	// add service specific data
+	reqItems[1].Serialize(elements)
+
+	itemData, err := serializeItems(reqItems)
+	if err != nil {
+		return hdr2, nil, err
+	}
+	hdr, data, err := client.send_recv_data_timeout(client.readTimeout(timeout), cipCommandSendUnitData, itemData)
+	if err != nil {
+		return hdr2, nil, err
+	}
+	_ = hdr
+
+	read_result_header := msgCIPResultHeader{}
+	err = binary.Read(data, binary.LittleEndian, &read_result_header)
+	if err != nil {
+		client.Logger.Warn("Problem reading read result header", "error", err)
+	}
+	items, err := readItems(data)
+	if err != nil {
+		client.Logger.Warn("Problem reading items", "error", err)
+		return hdr2, nil, err
+	}
+	if len(items) != 2 {
+		return hdr2, nil, fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(items))
+	}
+	hdr2, err = readResultHeader(&items[1])
+	if err != nil {
+		return hdr2, nil, fmt.Errorf("problem reading item 2's header. %w", err)
+	}
+	switch CIPStatus(hdr2.Status[1]) {
+	case CIPStatus_OK:
+	case CIPStatus_PartialTransfer:
+		// too big for one reply.  Read the whole thing again in pieces.
+		items[1], err = client.readFragmented(ioi, elements, timeout)
+		if err != nil {
+			return hdr2, nil, fmt.Errorf("problem with fragmented read of %s: %w", tag, err)
+		}
+		err = items[1].DeSerialize(&hdr2)
+		if err != nil {
+			return hdr2, nil, fmt.Errorf("problem reading fragmented read header. %w", err)
+		}
+	default:
+		return hdr2, nil, fmt.Errorf("problem reading %s: %w", tag, readReplyError(hdr2))
+	}
+
+	return hdr2, &items[1], nil
+}
+
+// read the header of a read reply.  A failed read with no extended status ends right after the status so it is two
+// bytes short of the whole header.  The type is left at zero for those.
+func readResultHeader(item *CIPItem) (msgCIPReadResultData, error) {
+	var hdr msgCIPReadResultData
+	rest := item.Rest()
+	if len(rest) >= 6 && len(rest) < binary.Size(hdr) && CIPStatus(rest[4]) != CIPStatus_OK {
+		hdr.SequenceCounter = binary.LittleEndian.Uint16(rest)
+		hdr.Service = CIPService(rest[2])
+		copy(hdr.Status[:], rest[3:6])
+		item.Pos = len(item.Data)
+		return hdr, nil
+	}
+	err := item.DeSerialize(&hdr)
+	return hdr, err
+}
+
+// read elements of a SINT, USINT, or BYTE array straight into a byte slice.  Single byte types have no byte order and
+// no padding so the reply data already is the array and there's no need to decode each element on its own.
+func (client *Client) readBytes(tag string, datatype CIPType, elements uint16, timeout time.Duration) ([]byte, error) {
+	_, hdr2, item, err := client.read_reply(tag, datatype, elements, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if !isByteType(hdr2.Type) {
+		return nil, fmt.Errorf("type mismatch reading %s: expected a single byte type but got %v", tag, hdr2.Type)
+	}
+	dat := item.Data[item.Pos:]
+	if len(dat) < int(elements) {
+		return nil, fmt.Errorf("asked for %d elements of %s but got %d", elements, tag, len(dat))
+	}
+	b := make([]byte, elements)
+	copy(b, dat)
+	return b, nil
+}
+
+func isByteType(t CIPType) bool {
+	return t == CIPTypeSINT || t == CIPTypeUSINT || t == CIPTypeBYTE
+}
+
+func readArray[T GoLogixTypes](client *Client, tag string, elements uint16, timeout time.Duration) ([]T, error) {
 	t := make([]T, elements)
 	ct, _ := GoVarToCIPType(t[0])
-	val, err := client.Read_single(tag, ct, elements)
+	if isByteType(ct) {
+		b, err := client.readBytes(tag, ct, elements, timeout)
+		if err != nil {
+			return t, err
+		}
+		switch out := any(t).(type) {
+		case []byte:
+			copy(out, b)
+		case []int8:
+			for i := range b {
+				out[i] = int8(b[i])
+			}
+		}
+		return t, nil
+	}
+	val, err := client.read_single(tag, ct, elements, timeout)
 	if err != nil {
 		return t, err
 	}
@@ -486,9 +676,25 @@ func readArray[T GoLogixTypes](client *Client, tag string, elements uint16) ([]T
 		if !ok {
 			return t, fmt.Errorf("couldn't cast to bytes. %w", err)
 		}
-		return parseArrayStruct[T](b, elements)
+		return parseArrayStruct[T](b, elements, client.byteOrder())
 	}
 
+	if strs, ok := val.([]string); ok {
+		for i := range strs {
+			t[i], ok = any(strs[i]).(T)
+			if !ok {
+				return t, errors.New("couldn't convert to correct type")
+			}
+		}
+		return t, nil
+	}
+
+	if elements == 1 {
+		if _, ok := val.([]any); !ok {
+			// a read of one element comes back as the element on its own.
+			val = []any{val}
+		}
+	}
 	//cast, ok := val.([]T)
 	cast, ok := val.([]any)
 	if !ok {
@@ -518,10 +724,27 @@ func readArray[T GoLogixTypes](client *Client, tag string, elements uint16) ([]T
 
 }
 
-func read[T GoLogixTypes](client *Client, tag string) (T, error) {
+// Read a single tag and return it as the go type T.
+//
+// The CIP type requested from the controller is picked from T (see GoTypeToCIPType).  If the controller
+// returns a different type than T corresponds to you will get a type mismatch error instead of a value.
+//
+// Example:
+//
+//	v, err := gologix.Read[int32](client, "TestDint")
+func Read[T GoLogixTypes](client *Client, tag string) (T, error) {
+	err := client.checkConnection()
+	if err != nil {
+		var t T
+		return t, fmt.Errorf("could not start read: %w", err)
+	}
+	return read[T](client, tag, 0)
+}
+
+func read[T GoLogixTypes](client *Client, tag string, timeout time.Duration) (T, error) {
 	var t T
-	ct, _ := GoVarToCIPType(t)
-	val, err := client.Read_single(tag, ct, 1)
+	ct := GoTypeToCIPType[T]()
+	val, err := client.read_single(tag, ct, 1, timeout)
 	if err != nil {
 		return t, err
 	}
@@ -529,10 +752,10 @@ func read[T GoLogixTypes](client *Client, tag string) (T, error) {
 		// val should be a byte slice
 		cast, ok := val.([]byte)
 		if !ok {
-			return t, errors.New("couldn't convert to byte slice")
+			return t, typeMismatch(tag, val, ct)
 		}
 		b := bytes.NewBuffer(cast)
-		err := binary.Read(b, binary.LittleEndian, &t)
+		err := binary.Read(b, client.byteOrder(), &t)
 		if err != nil {
 			return t, fmt.Errorf("couldn't parse str data. %w", err)
 		}
@@ -542,7 +765,7 @@ func read[T GoLogixTypes](client *Client, tag string) (T, error) {
 		// val should be a byte slice
 		cast, ok := val.([]byte)
 		if !ok {
-			return t, errors.New("couldn't convert to byte slice")
+			return t, typeMismatch(tag, val, ct)
 		}
 		s := string(cast)
 		t, ok = any(s).(T)
@@ -551,22 +774,120 @@ func read[T GoLogixTypes](client *Client, tag string) (T, error) {
 		}
 		return t, nil
 	}
+	if i, ok := val.(int8); ok && ct == CIPTypeUSINT {
+		// a SINT used as flags reads as a byte with the same bits.
+		val = byte(i)
+	}
 	cast, ok := val.(T)
 	if !ok {
-		return t, errors.New("couldn't convert to correct type")
+		return t, typeMismatch(tag, val, ct)
 	}
 	return cast, nil
 
 }
 
+// build the error for when the controller sent back a value that doesn't match what was asked for.
+func typeMismatch(tag string, val any, want CIPType) error {
+	have, _ := GoVarToCIPType(val)
+	if _, ok := val.([]byte); ok {
+		// structures come back as raw bytes.
+		have = CIPTypeStruct
+	}
+	return fmt.Errorf("type mismatch: tag %s is %v but you asked for %v", tag, have, want)
+}
+
 type cipStringHeader struct {
 	Unknown uint16
 	Length  uint32
 }
+
+// read the structure handle and LEN at the start of a string.  The handle is part of the CIP reply so it is always
+// little endian but LEN is data so it is in the tag's byte order.
+func readStringHeader(item *CIPItem, order binary.ByteOrder) (cipStringHeader, error) {
+	var hdr cipStringHeader
+	err := item.DeSerialize(&hdr.Unknown)
+	if err != nil {
+		return hdr, err
+	}
+	err = binary.Read(item, order, &hdr.Length)
+	return hdr, err
+}
+
 type cipStructHeader struct {
 	Unknown uint16
 }
 
+// read the handle of a structure from the type at the start of a read reply.  Atomic types are only the type code and
+// a zero byte.  Structures are the abbreviated structure type (0xA0), the size of the handle that follows them, and the
+// handle.  size is that second byte.  Logix handles are always 2 bytes so a 0 is taken as 2 and anything else is an
+// error instead of being taken as part of the data.
+func readStructHandle(r io.Reader, size byte) (uint16, error) {
+	if size != 2 && size != 0 {
+		return 0, fmt.Errorf("expected a 2 byte structure handle but the reply says it is %d bytes", size)
+	}
+	var b [2]byte
+	_, err := io.ReadFull(r, b[:])
+	if err != nil {
+		return 0, fmt.Errorf("problem reading structure handle. %w", err)
+	}
+	return binary.LittleEndian.Uint16(b[:]), nil
+}
+
+// parse the reply to a read of an array of strings.  The structure handle comes once at the start and then each
+// element is a LEN and DATA padded out to the size of the string type.  That is 88 bytes for the builtin STRING but
+// other string types have other sizes so the size comes from the template when we know it and from splitting the data
+// evenly between the elements when we don't.
+func (client *Client) decodeStringArray(tag string, elements uint16, item *CIPItem) ([]string, error) {
+	var handle uint16
+	err := item.DeSerialize(&handle)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't unpack struct header. %w", err)
+	}
+	dat := item.Data[item.Pos:]
+	size := 0
+	var layout *stringLayout
+	if handle == stringStructHandle {
+		size = 4 + stringDataSize + 2
+	} else {
+		for _, u := range client.KnownTypes {
+			if u.Info.Handle == handle {
+				size = u.Size()
+				if l, err := newStringLayout(u); err == nil {
+					layout = &l
+				}
+				break
+			}
+		}
+	}
+	if size == 0 {
+		if len(dat)%int(elements) != 0 {
+			return nil, fmt.Errorf("%d bytes of %s don't split into %d strings", len(dat), tag, elements)
+		}
+		size = len(dat) / int(elements)
+	}
+	if size < 4 || len(dat) < size*int(elements) {
+		return nil, fmt.Errorf("%d bytes isn't enough for %d strings of %d bytes in %s", len(dat), elements, size, tag)
+	}
+
+	response := make([]string, elements)
+	for i := range response {
+		element := dat[i*size : (i+1)*size]
+		if layout != nil {
+			response[i], err = layout.parse(element, client.byteOrder())
+		} else {
+			response[i], err = parseLogixStringOrder(element, client.byteOrder())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("problem reading element %d of %s: %w", i, tag, err)
+		}
+	}
+	item.Pos += size * int(elements)
+	return response, nil
+}
+
+// structure handle the controller reports for the builtin STRING type.
+const stringStructHandle = 0x0FCE
+
 // Tag_str is a pointer to a struct with each field tagged with a `gologix:"TAGNAME"` tag that specifies the tag on the client.
 // The types of each field need to correspond to the correct CIP type as mapped in types.go
 //
@@ -637,7 +958,28 @@ type tagDesc struct {
 	Elements int
 }
 
-func (client *Client) readList(tags []tagDesc) ([]any, error) {
+// read the tags in a single multi-service request.
+//
+// The error return is for problems with the request as a whole.  A problem with one of the tags
+// is returned in the error slice at that tag's index and doesn't affect the others.
+//
+// A request that fails as a whole with an error RetryPolicy says to retry is sent again.
+func (client *Client) readList(tags []tagDesc) ([]any, []error, error) {
+	names := make([]string, len(tags))
+	for i := range tags {
+		names[i] = tags[i].TagName
+	}
+	var results []any
+	var errs []error
+	err := client.withRetry(strings.Join(names, ","), func() error {
+		var err error
+		results, errs, err = client.readListOnce(tags)
+		return err
+	})
+	return results, errs, err
+}
+
+func (client *Client) readListOnce(tags []tagDesc) ([]any, []error, error) {
 
 	// first generate IOIs for each tag
 	qty := len(tags)
@@ -646,7 +988,7 @@ func (client *Client) readList(tags []tagDesc) ([]any, error) {
 		var err error
 		iois[i], err = client.newIOI(tag.TagName, tag.TagType)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -678,19 +1020,16 @@ func (client *Client) readList(tags []tagDesc) ([]any, error) {
 		}
 		err := binary.Write(&b, binary.LittleEndian, h)
 		if err != nil {
-			return nil, fmt.Errorf("problem writing cip IO header to buffer. %w", err)
+			return nil, nil, fmt.Errorf("problem writing cip IO header to buffer. %w", err)
 		}
 		b.Write(ioi.Buffer)
 		err = binary.Write(&b, binary.LittleEndian, f)
 		if err != nil {
-			return nil, fmt.Errorf("problem writing ioi buffer to msg buffer. %w", err)
+			return nil, nil, fmt.Errorf("problem writing ioi buffer to msg buffer. %w", err)
 		}
-		// TODO: calculate the actual message size, not just the IOI data size.
-		// TODO: We also need to calculate the response size we expect from the PLC and split
-		//       into multiple messages on that also.
+		// callers split their tags up with countIOIsThatFit so this only happens to a single tag whose path is too long.
 		if b.Len() > int(client.ConnectionSize) {
-			// TODO: split this read up into multiple messages.
-			return nil, fmt.Errorf("maximum read message size is %d", client.ConnectionSize)
+			return nil, nil, fmt.Errorf("maximum read message size is %d", client.ConnectionSize)
 		}
 	}
 
@@ -704,16 +1043,16 @@ func (client *Client) readList(tags []tagDesc) ([]any, error) {
 
 	itemData, err := serializeItems(reqItems)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	hdr, data, err := client.send_recv_data(cipCommandSendUnitData, itemData)
+	hdr, data, err := client.send_recv_data_timeout(client.readTimeout(0), cipCommandSendUnitData, itemData)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	_ = hdr
 
 	if hdr.Status != 0 {
-		return nil, fmt.Errorf("problem reading tags. Status %v", CIPStatus(hdr.Status))
+		return nil, nil, fmt.Errorf("problem reading tags. Status %v", CIPStatus(hdr.Status))
 	}
 
 	read_result_header := msgCIPResultHeader{}
@@ -723,125 +1062,204 @@ func (client *Client) readList(tags []tagDesc) ([]any, error) {
 	}
 	items, err := readItems(data)
 	if err != nil {
-		return nil, fmt.Errorf("problem reading items. %w", err)
+		return nil, nil, fmt.Errorf("problem reading items. %w", err)
 	}
 	if len(items) != 2 {
-		return nil, fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(items))
+		return nil, nil, fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(items))
 	}
 	rItem := items[1]
 	var reply_hdr msgMultiReadResultHeader
 	//err = binary.Read(&rItem, binary.LittleEndian, &reply_hdr)
 	reply_hdr.SequenceCount, err = rItem.Uint16()
 	if err != nil {
-		return nil, fmt.Errorf("problem reading reply header sequence count. %w", err)
+		return nil, nil, fmt.Errorf("problem reading reply header sequence count. %w", err)
 	}
 	_ = reply_hdr.SequenceCount
 	byt, err := rItem.Byte()
 	reply_hdr.Service = CIPService(byt)
 	if err != nil {
-		return nil, fmt.Errorf("problem reading reply header service code. %w", err)
+		return nil, nil, fmt.Errorf("problem reading reply header service code. %w", err)
 	}
 	_ = reply_hdr.Service
 	_, err = rItem.Byte()
 	if err != nil {
-		return nil, fmt.Errorf("problem reading reply header padding byte. %w", err)
+		return nil, nil, fmt.Errorf("problem reading reply header padding byte. %w", err)
 	}
 	reply_hdr.Status, err = rItem.Uint16()
 	if err != nil {
-		return nil, fmt.Errorf("problem reading reply header status. %w", err)
+		return nil, nil, fmt.Errorf("problem reading reply header status. %w", err)
 	}
-	if reply_hdr.Status != uint16(CIPStatus_OK) {
-		return nil, fmt.Errorf("service returned status %v", CIPStatus(reply_hdr.Status))
+	// an embedded service error means at least one of the reads failed.  We'll find out which below.
+	if reply_hdr.Status != uint16(CIPStatus_OK) && reply_hdr.Status != uint16(CIPStatus_EmbeddedServiceError) {
+		// the low byte is the general status and the high byte the number of extended status words after it.
+		cerr := &CIPError{General: byte(reply_hdr.Status)}
+		for i := 0; i < int(reply_hdr.Status>>8); i++ {
+			ext, err := rItem.Uint16()
+			if err != nil {
+				break
+			}
+			cerr.Extended = append(cerr.Extended, ext)
+		}
+		return nil, nil, fmt.Errorf("multiple service request failed: %w", cerr)
 	}
 	reply_hdr.Reply_Count, err = rItem.Uint16()
 	if err != nil {
-		return nil, fmt.Errorf("problem reading reply header item count. %w", err)
+		return nil, nil, fmt.Errorf("problem reading reply header item count. %w", err)
+	}
+
+	if int(reply_hdr.Reply_Count) != qty {
+		return nil, nil, fmt.Errorf("expected %d replies but got %d", qty, reply_hdr.Reply_Count)
 	}
 
 	offset_table := make([]uint16, reply_hdr.Reply_Count)
 	err = binary.Read(&rItem, binary.LittleEndian, &offset_table)
 	if err != nil {
-		return nil, fmt.Errorf("problem reading offset table. %w", err)
+		return nil, nil, fmt.Errorf("problem reading offset table. %w", err)
 	}
 	rb, err := rItem.Bytes()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	result_values := make([]interface{}, reply_hdr.Reply_Count)
-	for i := 0; i < int(reply_hdr.Reply_Count); i++ {
-		offset := offset_table[i] + 10 // offset doesn't start at 0 in the item
-		myBytes := bytes.NewBuffer(rb[offset:])
-		rHdr := msgMultiReadResult{}
-		err = binary.Read(myBytes, binary.LittleEndian, &rHdr)
-		if err != nil {
-			return nil, fmt.Errorf("problem reading multi result header. %w", err)
+	result_values := make([]any, qty)
+	errs := make([]error, qty)
+	for i := 0; i < qty; i++ {
+		offset := int(offset_table[i]) + 10 // offset doesn't start at 0 in the item
+		end := len(rb)
+		if i+1 < qty {
+			end = int(offset_table[i+1]) + 10
+		}
+		if offset > end || end > len(rb) {
+			return nil, nil, fmt.Errorf("bad offset %d for reply %d", offset_table[i], i)
+		}
+		result_values[i], errs[i] = parseMultiReadReply(tags[i], iois[i], rb[offset:end], client.byteOrder())
+		if status, ok := cipGeneral(errs[i]); ok && status == CIPStatus_PartialTransfer {
+			// this one didn't fit in what was left of the reply.  Get all of it with fragmented reads.
+			result_values[i], errs[i] = client.readListFragmented(tags[i], iois[i])
+		}
+		if errs[i] == nil && client.TreatFloatSpecialsAsError {
+			errs[i] = checkFloatSpecial(tags[i].TagName, result_values[i])
+			if errs[i] != nil {
+				result_values[i] = nil
+			}
 		}
+	}
 
-		// bit 8 of the service indicates whether it is a response service
-		if !rHdr.Service.IsResponse() {
-			return nil, fmt.Errorf("wasn't a response service. Got %v", rHdr.Service)
-		}
-		rHdr.Service = rHdr.Service.UnResponse()
-		if rHdr.Status != uint16(CIPStatus_OK) {
-			return nil, fmt.Errorf("problem reading %v. Status %v", tags[i], rHdr.Status)
+	return result_values, errs, nil
+
+}
+
+// read one tag of a multi-service read that came back as a partial transfer with fragmented reads.
+func (client *Client) readListFragmented(tag tagDesc, ioi *tagIOI) (any, error) {
+	item, err := client.readFragmented(ioi, uint16(tag.Elements), 0)
+	if err != nil {
+		return nil, fmt.Errorf("problem with fragmented read of %s: %w", tag.TagName, err)
+	}
+	// past the sequence count the assembled reply is laid out the same as one reply of a multi-service read.
+	return parseMultiReadReply(tag, ioi, item.Data[2:], client.byteOrder())
+}
+
+// parse one reply of a multi-service read. dat should be only the bytes for this reply.  order is the byte order of
+// the values.  The reply headers are always little endian.
+func parseMultiReadReply(tag tagDesc, ioi *tagIOI, dat []byte, order binary.ByteOrder) (any, error) {
+	if len(dat) < 4 {
+		return nil, fmt.Errorf("reply for %v too short. got %d bytes", tag.TagName, len(dat))
+	}
+	// bit 8 of the service indicates whether it is a response service
+	service := CIPService(dat[0])
+	if !service.IsResponse() {
+		return nil, fmt.Errorf("wasn't a response service. Got %v", service)
+	}
+	// a failed read doesn't have the type info after the status so we have to check it first.
+	if CIPStatus(dat[2]) != CIPStatus_OK {
+		cerr, _ := parseCIPStatus(dat[2:])
+		return nil, fmt.Errorf("problem reading %v: %w", tag.TagName, cerr)
+	}
+
+	myBytes := bytes.NewBuffer(dat)
+	rHdr := msgMultiReadResult{}
+	err := binary.Read(myBytes, binary.LittleEndian, &rHdr)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading multi result header. %w", err)
+	}
+
+	if tag.Elements != 1 {
+		if rHdr.Type == CIPTypeStruct {
+			// an array of structures.  The handle is only there once so the elements are left as one block of data.
+			_, err = readStructHandle(myBytes, rHdr.Reserved2)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't unpack struct header. %w", err)
+			}
+			return bytes.Clone(myBytes.Bytes()), nil
 		}
-		if tags[i].Elements == 1 {
-			if tags[i].TagType == CIPTypeBOOL && rHdr.Type != CIPTypeBOOL && iois[i].BitAccess {
-				// we have requested a bool from some other type.  Maybe a bit access?
-				value, err := readValue(rHdr.Type, myBytes)
-				if err != nil {
-					return nil, fmt.Errorf("problem reading tag %v: %w", tags[i], err)
-				}
-				val, err := getBit(rHdr.Type, value, iois[i].BitPosition)
-				if err != nil {
-					client.Logger.Warn("problem reading value for this guy")
-					continue
-				}
-				result_values[i] = val
-			} else if tags[i].TagType == CIPTypeSTRING {
-				str_hdr := cipStringHeader{}
-				err = binary.Read(myBytes, binary.LittleEndian, &str_hdr)
-				if err != nil {
-					return nil, fmt.Errorf("couldn't unpack string struct header. %w", err)
-				}
-				str := make([]byte, str_hdr.Length)
-				err = binary.Read(myBytes, binary.LittleEndian, str)
-				if err != nil {
-					return nil, fmt.Errorf("couldn't unpack struct data. %w", err)
-				}
-				result_values[i] = string(str)
-			} else {
-				result_values[i], err = rHdr.Type.readValue(myBytes)
-				if err != nil {
-					return nil, fmt.Errorf("problem reading tag %v: %w", tags[i], err)
-				}
+		// multi-element type.
+		val := make([]any, tag.Elements)
+		for respIndex := 0; respIndex < tag.Elements; respIndex++ {
+			value, err := readValueOrder(rHdr.Type, myBytes, order)
+			if err != nil {
+				return nil, fmt.Errorf("problem reading tag %v: %w", tag, err)
 			}
+			val[respIndex] = value
+		}
+		return val, nil
+	}
 
-		} else {
-			// multi-element type.
-			val := make([]any, tags[i].Elements)
-			for respIndex := 0; respIndex < tags[i].Elements; respIndex++ {
-				value, err := readValue(rHdr.Type, myBytes)
-				if err != nil {
-					return nil, fmt.Errorf("problem reading tag %v: %w", tags[i], err)
-				}
-				val[respIndex] = value
+	switch {
+	case (tag.TagType == CIPTypeBOOL || tag.TagType == CIPTypeUnknown) && rHdr.Type != CIPTypeBOOL && ioi.BitAccess:
+		// we have requested a bool from some other type.  Maybe a bit access?
+		value, err := readValueOrder(rHdr.Type, myBytes, order)
+		if err != nil {
+			return nil, fmt.Errorf("problem reading tag %v: %w", tag, err)
+		}
+		return getBit(rHdr.Type, value, ioi.BitPosition)
+	case tag.TagType == CIPTypeSTRING:
+		str_hdr := cipStringHeader{}
+		err = binary.Read(myBytes, binary.LittleEndian, &str_hdr.Unknown)
+		if err == nil {
+			err = binary.Read(myBytes, order, &str_hdr.Length)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("couldn't unpack string struct header. %w", err)
+		}
+		str := make([]byte, str_hdr.Length)
+		err = binary.Read(myBytes, binary.LittleEndian, str)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't unpack struct data. %w", err)
+		}
+		return string(str), nil
+	case rHdr.Type == CIPTypeStruct && (tag.TagType == CIPTypeUnknown || tag.TagType == CIPTypeStruct):
+		// we didn't say what type we wanted so go by the structure handle.
+		handle, err := readStructHandle(myBytes, rHdr.Reserved2)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't unpack struct header. %w", err)
+		}
+		if handle == stringStructHandle && tag.TagType == CIPTypeUnknown {
+			var length uint32
+			err = binary.Read(myBytes, order, &length)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't unpack string length. %w", err)
+			}
+			if int(length) > myBytes.Len() {
+				return nil, fmt.Errorf("string length %d longer than the %d bytes of data", length, myBytes.Len())
 			}
-			result_values[i] = val
+			return string(myBytes.Next(int(length))), nil
 		}
+		return bytes.Clone(myBytes.Bytes()), nil
 	}
 
-	return result_values, nil
-
+	value, err := readValueOrder(rHdr.Type, myBytes, order)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading tag %v: %w", tag, err)
+	}
+	return value, nil
 }
 
-func parseArrayStruct[T GoLogixTypes](dat []byte, elements uint16) ([]T, error) {
+func parseArrayStruct[T GoLogixTypes](dat []byte, elements uint16, order binary.ByteOrder) ([]T, error) {
 	t := make([]T, elements)
 	// val should be a byte slice
 	b := bytes.NewBuffer(dat)
 	for i := 0; i < int(elements); i++ {
 		//err := binary.Read(b, binary.LittleEndian, &t[i])
-		_, err := Unpack(b, &t[i])
+		_, err := cipPack{order: order}.unpack(b, &t[i])
 		if err != nil {
 			return t, fmt.Errorf("couldn't parse str data. %w", err)
 		}
@@ -905,11 +1323,15 @@ func (client *Client) ReadMap(m map[string]any) error {
 		if err != nil {
 			return err
 		}
-		subResults, err := client.readList(tags[n : n+n_new])
+		subResults, errs, err := client.readList(tags[n : n+n_new])
 		n += n_new
 		if err != nil {
 			return err
 		}
+		err = errors.Join(errs...)
+		if err != nil {
+			return err
+		}
 		result_values = append(result_values, subResults...)
 
 	}
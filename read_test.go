@@ -0,0 +1,370 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGoTypeToCIPType(t *testing.T) {
+	if have := GoTypeToCIPType[int32](); have != CIPTypeDINT {
+		t.Errorf("int32: wanted %v got %v", CIPTypeDINT, have)
+	}
+	if have := GoTypeToCIPType[float32](); have != CIPTypeREAL {
+		t.Errorf("float32: wanted %v got %v", CIPTypeREAL, have)
+	}
+	if have := GoTypeToCIPType[string](); have != CIPTypeSTRING {
+		t.Errorf("string: wanted %v got %v", CIPTypeSTRING, have)
+	}
+}
+
+func TestTypeMismatch(t *testing.T) {
+	err := typeMismatch("TestDint", int32(5), CIPTypeREAL)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "type mismatch") || !strings.Contains(msg, "DINT") || !strings.Contains(msg, "REAL") {
+		t.Errorf("error should name both types. got %q", msg)
+	}
+}
+
+func TestParseMultiReadReply(t *testing.T) {
+	unknown := tagDesc{TagName: "x", TagType: CIPTypeUnknown, Elements: 1}
+	var tests = []struct {
+		name string
+		tag  tagDesc
+		ioi  tagIOI
+		dat  []byte
+		want any
+		fail bool
+	}{
+		{"dint", unknown, tagIOI{}, []byte{0xCC, 0, 0, 0, byte(CIPTypeDINT), 0, 42, 0, 0, 0}, int32(42), false},
+		{"real", unknown, tagIOI{}, []byte{0xCC, 0, 0, 0, byte(CIPTypeREAL), 0, 0, 0, 0x80, 0x3F}, float32(1), false},
+		{"bad tag", unknown, tagIOI{}, []byte{0xCC, 0, byte(CIPStatus_PathDestinationUnknown), 0}, nil, true},
+		{"not a response", unknown, tagIOI{}, []byte{0x4C, 0, 0, 0, byte(CIPTypeDINT), 0, 42, 0, 0, 0}, nil, true},
+		{"too short", unknown, tagIOI{}, []byte{0xCC, 0}, nil, true},
+		{"bit", unknown, tagIOI{BitAccess: true, BitPosition: 3}, []byte{0xCC, 0, 0, 0, byte(CIPTypeDINT), 0, 0b1000, 0, 0, 0}, true, false},
+		{"string", unknown, tagIOI{}, []byte{0xCC, 0, 0, 0, byte(CIPTypeStruct), 0x02, 0xCE, 0x0F, 3, 0, 0, 0, 'a', 'b', 'c'}, "abc", false},
+	}
+
+	for _, tt := range tests {
+		have, err := parseMultiReadReply(tt.tag, &tt.ioi, tt.dat, binary.LittleEndian)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("%s: expected an error. got %v", tt.name, have)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if have != tt.want {
+			t.Errorf("%s: wanted %v (%T) got %v (%T)", tt.name, tt.want, tt.want, have, have)
+		}
+	}
+}
+
+// a SINT[1024] is too big for one reply so it comes back as a partial transfer and then in fragments.  The bytes
+// should land in the slice as is no matter which single byte type the slice is.
+func TestReadByteArrayFragmented(t *testing.T) {
+	const elements = 1024
+	const fragment = 400
+	client, remote := newPipeClient(t)
+
+	blob := make([]byte, elements)
+	for i := range blob {
+		blob[i] = byte(i * 7)
+	}
+	go func() {
+		for {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				return
+			}
+			// items header, connection address item, connected data item header, then the request
+			req := buf.Bytes()[20:]
+			service := CIPService(req[2])
+			path_end := 4 + 2*int(req[3])
+			offset := 0
+			if service == CIPService_FragRead {
+				offset = int(binary.LittleEndian.Uint32(req[path_end+2:]))
+			}
+			end := min(offset+fragment, len(blob))
+			status := CIPStatus_PartialTransfer
+			if end == len(blob) {
+				status = CIPStatus_OK
+			}
+			item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+			item.Serialize(msgCIPReadResultData{Service: service.AsResponse(), Status: [3]byte{0, byte(status), 0}, Type: CIPTypeSINT})
+			item.Serialize(blob[offset:end])
+			items := []CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item}
+			b, err := serializeItems(items)
+			if err != nil {
+				t.Errorf("problem building reply: %v", err)
+				return
+			}
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}
+	}()
+
+	signed := make([]int8, elements)
+	err := client.Read("Blob", signed)
+	if err != nil {
+		t.Fatalf("problem reading []int8: %v", err)
+	}
+	unsigned := make([]byte, elements)
+	err = client.Read("Blob", unsigned)
+	if err != nil {
+		t.Fatalf("problem reading []byte: %v", err)
+	}
+	for i := range blob {
+		if signed[i] != int8(blob[i]) || unsigned[i] != blob[i] {
+			t.Fatalf("element %d: wanted %d got %d and %d", i, blob[i], signed[i], unsigned[i])
+		}
+	}
+}
+
+// a multi-service read of a DINT and a STRING.  The 88 bytes of the STRING fit in the reply when the controller has room
+// for them.  When it doesn't they come back as a partial transfer and have to be read again in fragments.
+func TestReadMultiplePartialTransfer(t *testing.T) {
+	const fragment = 40
+	client, remote := newPipeClient(t)
+
+	var str []byte // the STRING's LEN, DATA, and padding
+	room := 0      // how much of str fits in the multi-service reply
+	setString := func(s string) {
+		str = make([]byte, 88)
+		binary.LittleEndian.PutUint32(str, uint32(len(s)))
+		copy(str[4:], s)
+	}
+	fragReads := 0
+	go func() {
+		for {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				return
+			}
+			req := buf.Bytes()[20:]
+			service := CIPService(req[2])
+			item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+			switch service {
+			case CIPService_MultipleService:
+				dint := []byte{byte(CIPService_Read.AsResponse()), 0, 0, 0, byte(CIPTypeDINT), 0, 7, 0, 0, 0}
+				// the string reply only gets as many bytes as are left in the "connection"
+				status := CIPStatus_OK
+				dat := str
+				if len(str) > room {
+					status = CIPStatus_PartialTransfer
+					dat = str[:room]
+				}
+				text := []byte{byte(CIPService_Read.AsResponse()), 0, byte(status), 0, byte(CIPTypeStruct), 0, 0xCE, 0x0F}
+				text = append(text, dat...)
+				item.Serialize(uint16(0))
+				item.Serialize(msgUnconnWriteResultHeader{Service: CIPService_MultipleService.AsResponse()})
+				item.Serialize(uint16(2))
+				item.Serialize([]uint16{6, uint16(6 + len(dint))})
+				item.Serialize(dint)
+				item.Serialize(text)
+			case CIPService_FragRead:
+				fragReads++
+				path_end := 4 + 2*int(req[3])
+				offset := int(binary.LittleEndian.Uint32(req[path_end+2:]))
+				end := min(offset+fragment, len(str))
+				status := CIPStatus_PartialTransfer
+				if end == len(str) {
+					status = CIPStatus_OK
+				}
+				item = fragmentReply(status, CIPTypeStruct, stringStructHandle, str[offset:end])
+			default:
+				t.Errorf("unexpected service %v", service)
+				return
+			}
+			items := []CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item}
+			b, err := serializeItems(items)
+			if err != nil {
+				t.Errorf("problem building reply: %v", err)
+				return
+			}
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}
+	}()
+
+	tags := []string{"Count", "Name"}
+	tests := []struct {
+		value string
+		room  int
+	}{
+		{"short", 100},
+		{strings.Repeat("long string ", 6), 100},
+		{"short", 87},
+		{strings.Repeat("long string ", 6), 50},
+	}
+	for _, tt := range tests {
+		want := tt.value
+		setString(want)
+		room = tt.room
+		fragReads = 0
+		values, errs := client.ReadMultiple(tags)
+		if errs[0] != nil || errs[1] != nil {
+			t.Fatalf("%q: unexpected errors %v", want, errs)
+		}
+		if values[0] != int32(7) || values[1] != want {
+			t.Errorf("wanted 7 and %q. got %v and %q", want, values[0], values[1])
+		}
+		if fits := len(str) <= room; fits != (fragReads == 0) {
+			t.Errorf("%q in %d bytes: %d fragmented reads", want, room, fragReads)
+		}
+	}
+}
+
+// the reply to a read of a string array: the handle once and then each element padded out to size bytes.
+func stringArrayReply(t *testing.T, handle uint16, size int, strs []string) []byte {
+	item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+	item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: CIPTypeStruct, Unknown: 0x02})
+	item.Serialize(handle)
+	for _, s := range strs {
+		element := make([]byte, size)
+		binary.LittleEndian.PutUint32(element, uint32(len(s)))
+		copy(element[4:], s)
+		item.Serialize(element)
+	}
+	b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+	if err != nil {
+		t.Fatalf("problem building reply: %v", err)
+	}
+	return *b
+}
+
+func TestReadStringArray(t *testing.T) {
+	want := []string{"", "a", "hello world", strings.Repeat("x", 20), "end"}
+	var tests = []struct {
+		name   string
+		handle uint16
+		size   int
+	}{
+		{"builtin STRING", stringStructHandle, 88},
+		{"STRING20 not in KnownTypes", 0x1234, 24},
+		{"STRING20 in KnownTypes", 0x4321, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, remote := newPipeClient(t)
+			client.KnownTypes["STRING20"] = UDTDescriptor{Name: "STRING20", Info: msgGetTemplateAttrListResponse{SizeBytes: 24, Handle: 0x4321}}
+			go func() {
+				for i := 0; i < 2; i++ {
+					hdr, _, err := recvData(remote)
+					if err != nil {
+						t.Errorf("problem reading request: %v", err)
+						return
+					}
+					writeTestResponse(t, remote, hdr.Context, stringArrayReply(t, tt.handle, tt.size, want))
+				}
+			}()
+
+			have := make([]string, len(want))
+			err := client.Read("Names", have)
+			if err != nil {
+				t.Fatalf("problem reading: %v", err)
+			}
+			if !reflect.DeepEqual(have, want) {
+				t.Errorf("wanted %q got %q", want, have)
+			}
+
+			val, err := client.Read_single("Names", CIPTypeSTRING, uint16(len(want)))
+			if err != nil {
+				t.Fatalf("problem reading: %v", err)
+			}
+			if !reflect.DeepEqual(val, want) {
+				t.Errorf("wanted %q got %#v", want, val)
+			}
+		})
+	}
+}
+
+func TestReadSingleCounts(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{
+		"arr": []int32{1, 2, 3},
+		"one": []int32{7},
+		"x":   int32(5),
+	})
+	// so the reads go by name, which is all the test server understands.
+	client.knownFirmware = 20
+	client.KnownTags = map[string]KnownTag{
+		"arr": {Name: "Arr", Info: TagInfo{Type: CIPTypeDINT, TypeInfo: 0x20, Dimension1: 3}, Array_Order: []int{3}},
+		"one": {Name: "One", Info: TagInfo{Type: CIPTypeDINT, TypeInfo: 0x20, Dimension1: 1}, Array_Order: []int{1}},
+		"x":   {Name: "X", Info: TagInfo{Type: CIPTypeDINT}, Array_Order: []int{}},
+	}
+
+	var tests = []struct {
+		tag      string
+		elements uint16
+		want     any
+	}{
+		{"Arr", 0, []any{int32(1), int32(2), int32(3)}},
+		{"Arr", 2, []any{int32(1), int32(2)}},
+		{"Arr", 1, int32(1)},
+		{"One", 0, []any{int32(7)}},
+		{"One", 1, int32(7)},
+		{"X", 0, int32(5)},
+		{"X", 1, int32(5)},
+	}
+	for _, tt := range tests {
+		have, err := client.Read_single(tt.tag, CIPTypeDINT, tt.elements)
+		if err != nil {
+			t.Errorf("%s with %d elements: %v", tt.tag, tt.elements, err)
+			continue
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("%s with %d elements: wanted %#v got %#v", tt.tag, tt.elements, tt.want, have)
+		}
+	}
+
+	// a one element slice is still a slice.
+	one := make([]int32, 1)
+	err := client.Read("One", one)
+	if err != nil {
+		t.Fatalf("problem reading into a one element slice: %v", err)
+	}
+	if one[0] != 7 {
+		t.Errorf("wanted 7 got %v", one)
+	}
+}
+
+// the size of an array that isn't in KnownTags comes from its attributes.
+func TestReadSingleWholeUnknownTag(t *testing.T) {
+	client, remote := newPipeClient(t)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading attributes request: %v", err)
+			return
+		}
+		if CIPService(buf.Bytes()[22]) != CIPService_GetAttributeList {
+			t.Errorf("wanted a get attribute list first. got %v", CIPService(buf.Bytes()[22]))
+		}
+		writeTestResponse(t, remote, hdr.Context, tagAttributesReply(t, "Pair", 0x20C4, 4, [3]uint32{1, 0, 0}))
+
+		hdr, buf, err = recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading read request: %v", err)
+			return
+		}
+		req := buf.Bytes()[20:]
+		elements := binary.LittleEndian.Uint16(req[4+int(req[3])*2:])
+		if CIPService(req[2]) != CIPService_Read || elements != 1 {
+			t.Errorf("wanted a read of 1 element. got %v of %d", CIPService(req[2]), elements)
+		}
+		writeTestResponse(t, remote, hdr.Context, dintReadReply(t, 9))
+	}()
+
+	have, err := client.Read_single("Pair", CIPTypeDINT, 0)
+	if err != nil {
+		t.Fatalf("problem reading: %v", err)
+	}
+	if !reflect.DeepEqual(have, []any{int32(9)}) {
+		t.Errorf("wanted a one element slice. got %#v", have)
+	}
+}
@@ -0,0 +1,153 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Read count elements of an array starting at element start into out, which must be a pointer to a slice of the go
+// type matching the array (ex: *[]int32 for a DINT array).  The slice is replaced with one of length count.
+//
+// tag is the array without an index.  The read asks the controller for count elements starting at tag[start] so
+// only that window of the array comes across.  For a multi-dimensional array in KnownTags (see ListAllTags) start
+// counts elements in row-major order, the way the controller stores them, so element 7 of a DINT[3,4] is [1,3].  If
+// the window runs past the end of the array the controller's error comes back as a *CIPError.  A count of 0 gives an
+// empty slice without talking to the controller.
+func (client *Client) ReadArray(tag string, start, count int, out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("out must be a pointer to a slice. got %T", out)
+	}
+	if start < 0 || count < 0 {
+		return fmt.Errorf("start and count can't be negative. got %d and %d", start, count)
+	}
+	if count > 0xFFFF {
+		return fmt.Errorf("can read at most %d elements at once. got %d", 0xFFFF, count)
+	}
+	if strings.HasSuffix(tag, "]") {
+		return fmt.Errorf("tag %s should be the array without an index", tag)
+	}
+	slice := v.Elem()
+	if count == 0 {
+		slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+		return nil
+	}
+
+	err := client.checkConnection()
+	if err != nil {
+		return fmt.Errorf("could not start array read: %w", err)
+	}
+
+	first, err := client.arrayStart(tag, start)
+	if err != nil {
+		return err
+	}
+	if slice.Type().Elem().Kind() == reflect.Bool {
+		// BOOL arrays are packed into DWORDs so they get read differently.
+		bools, err := client.ReadBoolArray(first, count)
+		if err != nil {
+			return err
+		}
+		slice.Set(reflect.ValueOf(bools).Convert(slice.Type()))
+		return nil
+	}
+
+	ct, _ := GoVarToCIPType(reflect.Zero(slice.Type().Elem()).Interface())
+	var val any
+	if isByteType(ct) {
+		val, err = client.readBytes(first, ct, uint16(count), 0)
+	} else {
+		val, err = client.read_single(first, ct, uint16(count), 0)
+	}
+	if err != nil {
+		return fmt.Errorf("problem reading %d elements of %s from %d: %w", count, tag, start, err)
+	}
+	return setArrayResultOrder(val, count, slice, client.byteOrder())
+}
+
+// the element of tag to start reading from.  Arrays we know have more than one dimension get an index for each.
+func (client *Client) arrayStart(tag string, start int) (string, error) {
+	known, ok := client.KnownTags[strings.ToLower(tag)]
+	if !ok || len(known.Array_Order) < 2 {
+		return fmt.Sprintf("%s[%d]", tag, start), nil
+	}
+	dims := known.Array_Order
+	indexes := make([]string, len(dims))
+	rest := start
+	for d := len(dims) - 1; d >= 0; d-- {
+		if dims[d] <= 0 {
+			return "", fmt.Errorf("dimension %d of %s has no elements", d, tag)
+		}
+		indexes[d] = strconv.Itoa(rest % dims[d])
+		rest /= dims[d]
+	}
+	if rest != 0 {
+		return "", fmt.Errorf("element %d is past the end of %s", start, tag)
+	}
+	return fmt.Sprintf("%s[%s]", tag, strings.Join(indexes, ",")), nil
+}
+
+// put the value read_single gave back for count elements into slice, replacing what's there.  Structures are
+// decoded with their values in the given byte order.
+func setArrayResultOrder(val any, count int, slice reflect.Value, order binary.ByteOrder) error {
+	result := reflect.MakeSlice(slice.Type(), count, count)
+	switch x := val.(type) {
+	case []byte:
+		if count == 1 && result.Type().Elem().Kind() == reflect.String {
+			return setArrayResultOrder([]any{x}, count, slice, order)
+		}
+		// structures come back as raw bytes.
+		err := binary.Read(bytes.NewReader(x), order, result.Interface())
+		if err != nil {
+			return fmt.Errorf("couldn't unpack %d elements into %v: %w", count, slice.Type(), err)
+		}
+	case []string:
+		if len(x) != count {
+			return fmt.Errorf("asked for %d elements but got %d", count, len(x))
+		}
+		for i := range x {
+			err := setArrayElement(x[i], result.Index(i))
+			if err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+	case []any:
+		if len(x) != count {
+			return fmt.Errorf("asked for %d elements but got %d", count, len(x))
+		}
+		for i := range x {
+			err := setArrayElement(x[i], result.Index(i))
+			if err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+	default:
+		// a single element comes back on its own.
+		if count != 1 {
+			return fmt.Errorf("asked for %d elements but got %T", count, val)
+		}
+		err := setArrayElement(val, result.Index(0))
+		if err != nil {
+			return err
+		}
+	}
+	slice.Set(result)
+	return nil
+}
+
+func setArrayElement(v any, ev reflect.Value) error {
+	if b, ok := v.([]byte); ok && ev.Kind() == reflect.String {
+		// strings have the LEN already applied when they get here.
+		v = string(b)
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.Type().AssignableTo(ev.Type()) {
+		return fmt.Errorf("got %T which can't go in %v", v, ev.Type())
+	}
+	ev.Set(rv)
+	return nil
+}
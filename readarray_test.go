@@ -0,0 +1,104 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestReadArrayNoRequest(t *testing.T) {
+	// not connected and not allowed to connect, so anything that hits the network fails.
+	client := NewClient("127.0.0.1")
+	client.AutoConnect = false
+
+	out := []int32{1, 2, 3}
+	err := client.ReadArray("MyDints", 100, 0, &out)
+	if err != nil {
+		t.Errorf("count of 0 shouldn't need the controller: %v", err)
+	}
+	if out == nil || len(out) != 0 {
+		t.Errorf("wanted an empty slice got %v", out)
+	}
+
+	if client.ReadArray("MyDints", 0, 5, out) == nil {
+		t.Errorf("out that isn't a pointer should be an error")
+	}
+	if client.ReadArray("MyDints[3]", 0, 5, &out) == nil {
+		t.Errorf("a tag that already has an index should be an error")
+	}
+	if client.ReadArray("MyDints", -1, 5, &out) == nil {
+		t.Errorf("a negative start should be an error")
+	}
+}
+
+func TestSetArrayResult(t *testing.T) {
+	var dints []int32
+	err := setArrayResultOrder([]any{int32(5), int32(6), int32(7)}, 3, reflect.ValueOf(&dints).Elem(), binary.LittleEndian)
+	if err != nil || !slices.Equal(dints, []int32{5, 6, 7}) {
+		t.Errorf("wanted [5 6 7] got %v (%v)", dints, err)
+	}
+
+	err = setArrayResultOrder(int32(9), 1, reflect.ValueOf(&dints).Elem(), binary.LittleEndian)
+	if err != nil || !slices.Equal(dints, []int32{9}) {
+		t.Errorf("wanted [9] got %v (%v)", dints, err)
+	}
+
+	var strs []string
+	err = setArrayResultOrder([]any{[]byte("ab"), []byte("cde")}, 2, reflect.ValueOf(&strs).Elem(), binary.LittleEndian)
+	if err != nil || !slices.Equal(strs, []string{"ab", "cde"}) {
+		t.Errorf("wanted [ab cde] got %v (%v)", strs, err)
+	}
+	err = setArrayResultOrder([]byte("xyz"), 1, reflect.ValueOf(&strs).Elem(), binary.LittleEndian)
+	if err != nil || !slices.Equal(strs, []string{"xyz"}) {
+		t.Errorf("wanted [xyz] got %v (%v)", strs, err)
+	}
+
+	type pair struct {
+		A int16
+		B int16
+	}
+	var pairs []pair
+	err = setArrayResultOrder([]byte{1, 0, 2, 0, 3, 0, 4, 0}, 2, reflect.ValueOf(&pairs).Elem(), binary.LittleEndian)
+	if err != nil || !slices.Equal(pairs, []pair{{1, 2}, {3, 4}}) {
+		t.Errorf("wanted [{1 2} {3 4}] got %v (%v)", pairs, err)
+	}
+
+	var reals []float32
+	if setArrayResultOrder([]any{int32(1)}, 1, reflect.ValueOf(&reals).Elem(), binary.LittleEndian) == nil {
+		t.Errorf("a DINT shouldn't go in a []float32")
+	}
+	if setArrayResultOrder([]any{float32(1)}, 2, reflect.ValueOf(&reals).Elem(), binary.LittleEndian) == nil {
+		t.Errorf("the wrong number of elements should be an error")
+	}
+}
+
+// a start index into a multi-dimensional array is split up in row-major order.
+func TestArrayStart(t *testing.T) {
+	client := NewClient("localhost")
+	client.KnownTags["grid"] = KnownTag{Array_Order: []int{3, 4}}
+	client.KnownTags["cube"] = KnownTag{Array_Order: []int{2, 3, 4}}
+
+	var tests = []struct {
+		tag   string
+		start int
+		want  string
+	}{
+		{"Arr", 5, "Arr[5]"},
+		{"Grid", 0, "Grid[0,0]"},
+		{"Grid", 7, "Grid[1,3]"},
+		{"Grid", 11, "Grid[2,3]"},
+		{"Cube", 23, "Cube[1,2,3]"},
+		{"Cube", 13, "Cube[1,0,1]"},
+	}
+	for _, tt := range tests {
+		have, err := client.arrayStart(tt.tag, tt.start)
+		if err != nil || have != tt.want {
+			t.Errorf("%s from %d: wanted %s got %s %v", tt.tag, tt.start, tt.want, have, err)
+		}
+	}
+	_, err := client.arrayStart("Grid", 12)
+	if err == nil {
+		t.Errorf("element 12 should be past the end of a [3,4] array")
+	}
+}
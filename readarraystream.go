@@ -0,0 +1,178 @@
+package gologix
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Read count elements of an array starting at element start and send them on out one at a time as they come in.
+// out is closed when the read is done, whether it worked or not, so a consumer can range over it and check the error
+// afterwards.
+//
+// The array is read with fragmented reads and the elements of each fragment are decoded and sent before the next
+// fragment is asked for, so only about one reply's worth of the array is held in memory no matter how big it is.  A
+// slow consumer holds up the read.  Counts over 65535 are read as consecutive windows of at most that many elements.
+//
+// Elements come out as the go type that matches the controller's type (see ReadMultiple).  STRINGs come out as
+// strings.  Other structures come out as the raw bytes of each element and need the array to be in KnownTags (see
+// ListAllTags and ListMembers) so the size of an element is known.  BOOL arrays are packed into DWORDs so use
+// ReadBoolArray for those.
+//
+// Example:
+//
+//	out := make(chan any, 100)
+//	go func() { errc <- client.ReadArrayStream("History", 0, 100000, out) }()
+//	for v := range out {
+//		store(v.(float32))
+//	}
+//	err := <-errc
+func (client *Client) ReadArrayStream(tag string, start, count int, out chan<- any) error {
+	defer close(out)
+	if start < 0 || count < 0 {
+		return fmt.Errorf("start and count can't be negative. got %d and %d", start, count)
+	}
+	if strings.HasSuffix(tag, "]") {
+		return fmt.Errorf("tag %s should be the array without an index", tag)
+	}
+	if count == 0 {
+		return nil
+	}
+	var template *UDTDescriptor
+	if known, ok := client.KnownTags[strings.ToLower(tag)]; ok {
+		if known.Info.Type == CIPTypeBOOL {
+			return fmt.Errorf("%s is a BOOL array. use ReadBoolArray", tag)
+		}
+		template = known.UDT
+	}
+
+	err := client.checkConnection()
+	if err != nil {
+		return fmt.Errorf("could not start array stream: %w", err)
+	}
+
+	for done := 0; done < count; {
+		n := min(count-done, 0xFFFF)
+		first, err := client.arrayStart(tag, start+done)
+		if err != nil {
+			return err
+		}
+		ioi, err := client.newIOI(first, CIPTypeUnknown)
+		if err != nil {
+			return fmt.Errorf("problem building ioi for %s: %w", first, err)
+		}
+		sent, err := client.streamFragments(tag, template, out, func(offset uint32) (CIPItem, error) {
+			return client.readFragment(ioi, uint16(n), offset, 0)
+		})
+		if err != nil {
+			return fmt.Errorf("problem streaming %s from element %d: %w", tag, start+done+sent, err)
+		}
+		if sent != n {
+			return fmt.Errorf("asked for %d elements of %s from %d but got %d", n, tag, start+done, sent)
+		}
+		done += n
+	}
+	return nil
+}
+
+// fetch fragments at increasing offsets like assembleFragments but decode the whole elements in each one and send them
+// on out instead of putting the fragments together.  Returns how many elements were sent.
+func (client *Client) streamFragments(tag string, template *UDTDescriptor, out chan<- any, fetch func(offset uint32) (CIPItem, error)) (int, error) {
+	var size int
+	var decode func([]byte) (any, error)
+	var pending []byte // the start of an element cut off at the end of the last fragment.
+	sent := 0
+	offset := uint32(0)
+
+	for {
+		item, err := fetch(offset)
+		if err != nil {
+			return sent, fmt.Errorf("problem reading fragment at offset %d: %w", offset, err)
+		}
+		var hdr msgCIPReadResultData
+		err = item.DeSerialize(&hdr)
+		if err != nil {
+			return sent, fmt.Errorf("problem reading fragment header at offset %d: %w", offset, err)
+		}
+		status := CIPStatus(hdr.Status[1])
+		if status != CIPStatus_OK && status != CIPStatus_PartialTransfer {
+			return sent, fmt.Errorf("fragment at offset %d: %w", offset, readReplyError(hdr))
+		}
+		var handle uint16
+		if hdr.Type == CIPTypeStruct {
+			err = item.DeSerialize(&handle)
+			if err != nil {
+				return sent, fmt.Errorf("problem reading structure handle at offset %d: %w", offset, err)
+			}
+		}
+		if decode == nil {
+			size, decode, err = client.elementDecoder(hdr.Type, handle, template)
+			if err != nil {
+				return sent, err
+			}
+		}
+
+		chunk := item.Data[item.Pos:]
+		offset += uint32(len(chunk))
+		pending = append(pending, chunk...)
+		used := 0
+		for len(pending)-used >= size {
+			v, err := decode(pending[used : used+size])
+			if err != nil {
+				return sent, fmt.Errorf("element %d: %w", sent, err)
+			}
+			if client.TreatFloatSpecialsAsError {
+				err = checkFloatSpecial(tag, v)
+				if err != nil {
+					return sent, err
+				}
+			}
+			out <- v
+			sent++
+			used += size
+		}
+		pending = pending[:copy(pending, pending[used:])]
+
+		if status == CIPStatus_OK {
+			break
+		}
+		if len(chunk) == 0 {
+			return sent, fmt.Errorf("partial transfer at offset %d returned no data", offset)
+		}
+	}
+	if len(pending) > 0 {
+		return sent, fmt.Errorf("%d bytes left over after the last whole element", len(pending))
+	}
+	return sent, nil
+}
+
+// the size of one element of an array of type t and how to decode one.  handle and template are only used for
+// structures.
+func (client *Client) elementDecoder(t CIPType, handle uint16, template *UDTDescriptor) (int, func([]byte) (any, error), error) {
+	order := client.byteOrder()
+	switch {
+	case t == CIPTypeStruct && handle == stringStructHandle:
+		size := CIPTypeSTRING.SizeWithTemplate(template)
+		return size, func(b []byte) (any, error) {
+			length := int(order.Uint32(b))
+			if length > size-4 {
+				return nil, fmt.Errorf("string length %d is more than the %d bytes of data", length, size-4)
+			}
+			return string(b[4 : 4+length]), nil
+		}, nil
+	case t == CIPTypeStruct:
+		if template == nil || template.Info.SizeBytes == 0 {
+			return 0, nil, fmt.Errorf("don't know the size of structure %#04x. list the tag's members first", handle)
+		}
+		return int(template.Info.SizeBytes), func(b []byte) (any, error) {
+			return bytes.Clone(b), nil
+		}, nil
+	}
+	size := t.Size()
+	if size == 0 {
+		return 0, nil, fmt.Errorf("can't stream elements of type %v", t)
+	}
+	return size, func(b []byte) (any, error) {
+		return readValueOrder(t, bytes.NewReader(b), order)
+	}, nil
+}
@@ -0,0 +1,133 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// fetch dat with fragments of the given size for streamFragments.
+func fragmentFetcher(typ CIPType, handle uint16, dat []byte, fragment int) func(offset uint32) (CIPItem, error) {
+	return func(offset uint32) (CIPItem, error) {
+		end := min(int(offset)+fragment, len(dat))
+		status := CIPStatus_PartialTransfer
+		if end == len(dat) {
+			status = CIPStatus_OK
+		}
+		return fragmentReply(status, typ, handle, dat[offset:end]), nil
+	}
+}
+
+// fragments that don't end on an element still give every element whole and in order.
+func TestStreamFragmentsDINT(t *testing.T) {
+	const elements = 5000
+	dat := make([]byte, elements*4)
+	for i := 0; i < elements; i++ {
+		binary.LittleEndian.PutUint32(dat[i*4:], uint32(i*3))
+	}
+	client := NewClient("127.0.0.1")
+	out := make(chan any, elements)
+	sent, err := client.streamFragments("Arr", nil, out, fragmentFetcher(CIPTypeDINT, 0, dat, 482))
+	if err != nil {
+		t.Fatalf("problem streaming: %v", err)
+	}
+	if sent != elements || len(out) != elements {
+		t.Fatalf("wanted %d elements. sent %d and got %d", elements, sent, len(out))
+	}
+	for i := 0; i < elements; i++ {
+		if v := <-out; v != int32(i*3) {
+			t.Fatalf("element %d: wanted %d got %v", i, i*3, v)
+		}
+	}
+}
+
+func TestStreamFragmentsStrings(t *testing.T) {
+	words := []string{"one", "", "a longer string than the rest"}
+	dat := make([]byte, 0, 88*len(words))
+	for _, w := range words {
+		b := make([]byte, 88)
+		binary.LittleEndian.PutUint32(b, uint32(len(w)))
+		copy(b[4:], w)
+		dat = append(dat, b...)
+	}
+	client := NewClient("127.0.0.1")
+	out := make(chan any, len(words))
+	_, err := client.streamFragments("Names", nil, out, fragmentFetcher(CIPTypeStruct, stringStructHandle, dat, 100))
+	if err != nil {
+		t.Fatalf("problem streaming: %v", err)
+	}
+	for i, w := range words {
+		if v := <-out; v != w {
+			t.Errorf("element %d: wanted %q got %v", i, w, v)
+		}
+	}
+}
+
+func TestStreamFragmentsErrors(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	out := make(chan any, 10)
+
+	// a structure we don't have the template of.
+	_, err := client.streamFragments("Udts", nil, out, fragmentFetcher(CIPTypeStruct, 0x1234, make([]byte, 24), 100))
+	if err == nil {
+		t.Errorf("a structure without a template should fail")
+	}
+	// with the template the elements are the raw bytes.
+	template := &UDTDescriptor{Info: msgGetTemplateAttrListResponse{SizeBytes: 12}}
+	sent, err := client.streamFragments("Udts", template, out, fragmentFetcher(CIPTypeStruct, 0x1234, make([]byte, 24), 100))
+	if err != nil || sent != 2 {
+		t.Errorf("wanted 2 structures. got %d and %v", sent, err)
+	}
+	if b, ok := (<-out).([]byte); !ok || len(b) != 12 {
+		t.Errorf("wanted 12 raw bytes got %v", b)
+	}
+
+	// data that doesn't end on an element.
+	_, err = client.streamFragments("Arr", nil, out, fragmentFetcher(CIPTypeDINT, 0, make([]byte, 6), 100))
+	if err == nil {
+		t.Errorf("a partial element at the end should fail")
+	}
+}
+
+func TestReadArrayStream(t *testing.T) {
+	client, remote := newPipeClient(t)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		if s := CIPService(buf.Bytes()[22]); s != CIPService_FragRead {
+			t.Errorf("wanted a fragmented read. got %v", s)
+		}
+		dat := make([]byte, 12)
+		for i := 0; i < 3; i++ {
+			binary.LittleEndian.PutUint32(dat[i*4:], uint32(10+i))
+		}
+		item := fragmentReply(CIPStatus_OK, CIPTypeDINT, 0, dat)
+		b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	out := make(chan any)
+	errc := make(chan error, 1)
+	go func() { errc <- client.ReadArrayStream("Arr", 5, 3, out) }()
+	have := make([]any, 0)
+	for v := range out {
+		have = append(have, v)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("problem streaming: %v", err)
+	}
+	if len(have) != 3 || have[0] != int32(10) || have[2] != int32(12) {
+		t.Errorf("wanted [10 11 12] got %v", have)
+	}
+
+	// the channel is closed on errors too.
+	out = make(chan any)
+	if err := client.ReadArrayStream("Arr[2]", 0, 3, out); err == nil {
+		t.Errorf("a tag with an index should fail")
+	}
+	if _, ok := <-out; ok {
+		t.Errorf("out should be closed")
+	}
+}
@@ -0,0 +1,46 @@
+package gologix
+
+import (
+	"fmt"
+)
+
+// Read a controller scoped tag by its symbol object instance instead of its name.  t is the type of the tag or
+// CIPTypeUnknown to take whatever the controller sends.  The value comes back the way Read_single gives it except
+// that a STRING is a go string.
+//
+// The request path is the symbol class (0x6B) and the instance so it is the same few bytes no matter how long the
+// name is.  The instance IDs are the Instance field of the tags in KnownTags after ListAllTags, or of the tags a
+// TagIterator gives.  They stay the same until the tag is deleted or the controller is downloaded to.  Program scoped
+// tags are numbered within their program and can't be read this way.
+//
+// On firmware 21 and later Read and friends already do this for tags that are in KnownTags.
+func (client *Client) ReadByInstance(instanceID uint16, t CIPType) (any, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return nil, fmt.Errorf("could not start instance read: %w", err)
+	}
+	name := fmt.Sprintf("instance %d", instanceID)
+	ioi := &tagIOI{
+		Path:   name,
+		Type:   t,
+		Buffer: KnownTag{Instance: CIPInstance(instanceID)}.Bytes(),
+	}
+	var hdr msgCIPReadResultData
+	var item *CIPItem
+	err = client.withRetry(name, func() error {
+		var err error
+		hdr, item, err = client.read_ioi_once(name, ioi, 1, 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	value, err := client.decodeRead(name, t, 1, ioi, hdr, item)
+	if err != nil {
+		return nil, err
+	}
+	if b, ok := value.([]byte); ok && t == CIPTypeSTRING {
+		return string(b), nil
+	}
+	return value, nil
+}
@@ -0,0 +1,50 @@
+package gologix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadByInstance(t *testing.T) {
+	client, remote := newPipeClient(t)
+
+	requests := make(chan []byte, 1)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		requests <- buf.Bytes()[20:]
+		writeTestResponse(t, remote, hdr.Context, dintReadReply(t, 1234))
+	}()
+
+	type result struct {
+		v   any
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		v, err := client.ReadByInstance(300, CIPTypeDINT)
+		done <- result{v, err}
+	}()
+	select {
+	case r := <-done:
+		if r.err != nil || r.v != int32(1234) {
+			t.Errorf("wanted 1234. got %v %v", r.v, r.err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("timed out waiting for ReadByInstance")
+	}
+
+	req := <-requests
+	want := []byte{
+		byte(CIPService_Read), 0x03, // path size in words
+		0x20, 0x6B, // symbol class
+		0x25, 0x00, 0x2C, 0x01, // 16 bit instance 300
+		0x01, 0x00, // 1 element
+	}
+	if !check_bytes(req[2:], want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(req[2:]))
+	}
+}
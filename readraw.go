@@ -0,0 +1,92 @@
+package gologix
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Read one element of a tag without decoding it.  This is for figuring out what the controller actually sends when a
+// normal read gives something unexpected.  Use Read and friends for everything else.
+//
+// The type is the type code from the reply and the bytes are everything after it, in the controller's byte order.
+// For structures (CIPTypeStruct, which includes strings) the first two bytes are the structure handle and the rest is
+// the structure data with its padding.  A reply that's too big for one message is read in fragments and put back
+// together the same as for a normal read.
+func (client *Client) ReadRaw(tag string) (CIPType, []byte, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return CIPTypeUnknown, nil, fmt.Errorf("could not start raw read: %w", err)
+	}
+	_, hdr, item, err := client.read_reply(tag, CIPTypeUnknown, 1, 0)
+	if err != nil {
+		return CIPTypeUnknown, nil, err
+	}
+	return hdr.Type, bytes.Clone(item.Data[item.Pos:]), nil
+}
+
+// Read one element of a tag into buf without going through an any.  This is for hot paths that decode the bytes
+// themselves.  Use Read and friends for everything else.
+//
+// The type is the type code from the reply and n is how many bytes of buf were used.  Atomic values are always put in
+// buf little endian, even when the client's ByteOrder is big endian.  For structures (CIPTypeStruct, which includes
+// strings) buf gets the structure data with its padding, without the handle, as the controller sent it.  A bit of an
+// integer tag gives the whole integer.  If buf is too small nothing is copied, n is the size it needs to be, and the
+// error is io.ErrShortBuffer.
+func (client *Client) ReadInto(tag string, buf []byte) (CIPType, int, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return CIPTypeUnknown, 0, fmt.Errorf("could not start read: %w", err)
+	}
+	_, hdr, item, err := client.read_reply(tag, CIPTypeUnknown, 1, 0)
+	if err != nil {
+		return CIPTypeUnknown, 0, err
+	}
+	if hdr.Type == CIPTypeStruct {
+		_, err = readStructHandle(item, hdr.Unknown)
+		if err != nil {
+			return hdr.Type, 0, fmt.Errorf("problem reading %s: %w", tag, err)
+		}
+	}
+	dat := item.Rest()
+	if len(buf) < len(dat) {
+		return hdr.Type, len(dat), fmt.Errorf("%s needs %d bytes. got %d: %w", tag, len(dat), len(buf), io.ErrShortBuffer)
+	}
+	n := copy(buf, dat)
+	if size := hdr.Type.Size(); hdr.Type != CIPTypeStruct && size > 1 && isBigEndian(client.byteOrder()) {
+		for i := 0; i+size <= n; i += size {
+			slices.Reverse(buf[i : i+size])
+		}
+	}
+	return hdr.Type, n, nil
+}
+
+// StructData is a structure as a read reply has it: the handle of its type and the member data.
+type StructData struct {
+	Handle uint16 // the structure handle of the type.  0x0FCE for the builtin STRING.  See Template.Info.Handle
+	Data   []byte // the members laid out the way the template says, in the controller's byte order
+}
+
+// Read a structure tag and give back its handle and raw member data without decoding them.  Nothing needs to be known
+// about the type ahead of time so this works for any UDT.  Use ReadStruct to decode the members into a go struct.
+//
+// A tag that isn't a structure is an error.
+func (client *Client) ReadStructData(tag string) (StructData, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return StructData{}, fmt.Errorf("could not start struct read: %w", err)
+	}
+	_, hdr, item, err := client.read_reply(tag, CIPTypeStruct, 1, 0)
+	if err != nil {
+		return StructData{}, err
+	}
+	if hdr.Type != CIPTypeStruct {
+		return StructData{}, fmt.Errorf("%s isn't a structure. it is a %v", tag, hdr.Type)
+	}
+	handle, err := readStructHandle(item, hdr.Unknown)
+	if err != nil {
+		return StructData{}, fmt.Errorf("problem reading %s: %w", tag, err)
+	}
+	return StructData{Handle: handle, Data: bytes.Clone(item.Rest())}, nil
+}
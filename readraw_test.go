@@ -0,0 +1,171 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// a structure should come back as its handle and data with nothing decoded.
+func TestReadRaw(t *testing.T) {
+	client, remote := newPipeClient(t)
+
+	go func() {
+		hdr, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: CIPTypeStruct, Unknown: 0x02})
+		item.Serialize([]byte{0x34, 0x12, 1, 2, 3, 4, 5, 0, 0, 0})
+		b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	type result struct {
+		typ CIPType
+		dat []byte
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		typ, dat, err := client.ReadRaw("MyUDT")
+		done <- result{typ, dat, err}
+	}()
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatalf("problem reading: %v", r.err)
+		}
+		if r.typ != CIPTypeStruct {
+			t.Errorf("wanted a struct. got %v", r.typ)
+		}
+		if want := []byte{0x34, 0x12, 1, 2, 3, 4, 5, 0, 0, 0}; !check_bytes(r.dat, want) {
+			t.Errorf("wanted %v got %v", to_hex(want), to_hex(r.dat))
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("timed out waiting for ReadRaw")
+	}
+}
+
+func TestReadStructData(t *testing.T) {
+	replies := [][]byte{
+		{0x34, 0x12, 1, 2, 3, 4},
+		{0x34, 0x12, 0, 0, 1, 2, 3, 4}, // a 4 byte handle
+		{},
+	}
+	sizes := []byte{2, 4, 0}
+	types := []CIPType{CIPTypeStruct, CIPTypeStruct, CIPTypeDINT}
+	client, remote := newPipeClient(t)
+	go func() {
+		for i := range replies {
+			hdr, _, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+			item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: types[i], Unknown: sizes[i]})
+			item.Serialize(replies[i])
+			if types[i] == CIPTypeDINT {
+				item.Serialize(int32(5))
+			}
+			b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}
+	}()
+
+	have, err := client.ReadStructData("MyUDT")
+	if err != nil {
+		t.Fatalf("problem reading: %v", err)
+	}
+	if have.Handle != 0x1234 || !check_bytes(have.Data, []byte{1, 2, 3, 4}) {
+		t.Errorf("wanted handle 1234 and 01 02 03 04. got %X and %v", have.Handle, to_hex(have.Data))
+	}
+	_, err = client.ReadStructData("MyUDT")
+	if err == nil {
+		t.Errorf("a 4 byte handle should be an error")
+	}
+	_, err = client.ReadStructData("Count")
+	if err == nil {
+		t.Errorf("a DINT isn't a structure")
+	}
+}
+
+// an array of structures in a multi-service read keeps the elements together after the one handle.
+func TestParseMultiReadReplyStructArray(t *testing.T) {
+	tag := tagDesc{TagName: "x", TagType: CIPTypeUnknown, Elements: 2}
+	dat := []byte{0xCC, 0, 0, 0, byte(CIPTypeStruct), 0x02, 0x34, 0x12, 1, 2, 3, 4}
+	have, err := parseMultiReadReply(tag, &tagIOI{}, dat, binary.LittleEndian)
+	if err != nil {
+		t.Fatalf("problem parsing: %v", err)
+	}
+	b, ok := have.([]byte)
+	if !ok || !check_bytes(b, []byte{1, 2, 3, 4}) {
+		t.Errorf("wanted the structure data. got %v", have)
+	}
+}
+
+func TestReadInto(t *testing.T) {
+	client, remote := newPipeClient(t)
+	replies := []struct {
+		typ  CIPType
+		size byte
+		dat  []byte
+	}{
+		{CIPTypeDINT, 0, []byte{0x78, 0x56, 0x34, 0x12}},
+		{CIPTypeDINT, 0, []byte{0x78, 0x56, 0x34, 0x12}},
+		{CIPTypeStruct, 2, []byte{0x34, 0x12, 1, 2, 3, 4}},
+		{CIPTypeLINT, 0, []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+	}
+	go func() {
+		for _, r := range replies {
+			hdr, _, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+			item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: r.typ, Unknown: r.size})
+			item.Serialize(r.dat)
+			b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}
+	}()
+
+	buf := make([]byte, 8)
+	typ, n, err := client.ReadInto("Count", buf)
+	if err != nil || typ != CIPTypeDINT || n != 4 {
+		t.Fatalf("wanted 4 bytes of DINT. got %d bytes of %v: %v", n, typ, err)
+	}
+	if v := binary.LittleEndian.Uint32(buf); v != 0x12345678 {
+		t.Errorf("wanted 0x12345678 got %#x", v)
+	}
+
+	_, n, err = client.ReadInto("Count", buf[:2])
+	if !errors.Is(err, io.ErrShortBuffer) || n != 4 {
+		t.Errorf("wanted io.ErrShortBuffer and the 4 bytes it needs. got %d: %v", n, err)
+	}
+
+	typ, n, err = client.ReadInto("MyUDT", buf)
+	if err != nil || typ != CIPTypeStruct || !check_bytes(buf[:n], []byte{1, 2, 3, 4}) {
+		t.Errorf("wanted the structure data without its handle. got %v of %v: %v", to_hex(buf[:n]), typ, err)
+	}
+
+	// values from a big endian device still come out little endian.
+	client.ByteOrder = binary.BigEndian
+	typ, n, err = client.ReadInto("Big", buf)
+	if err != nil || typ != CIPTypeLINT || n != 8 {
+		t.Fatalf("wanted 8 bytes of LINT. got %d bytes of %v: %v", n, typ, err)
+	}
+	if v := binary.LittleEndian.Uint64(buf); v != 0x0102030405060708 {
+		t.Errorf("wanted 0x0102030405060708 got %#x", v)
+	}
+}
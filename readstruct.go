@@ -0,0 +1,362 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// bits of a template member's type word.  see page 43 of 1756-PM020H-EN-P
+const (
+	memberTypeStruct   = 0x8000 // the member is a structure. The template instance is in the low 12 bits
+	memberTypeArray    = 0x6000 // the member is an array.  The element count is in the member's Info
+	memberTypeTemplate = 0x0FFF
+)
+
+// Read a structure tag into out, which must be a pointer to a go struct.
+//
+// The whole structure is read in one request and then each member is decoded at the offset the controller's
+// template gives for it, so the go struct doesn't need to match the controller's member order or padding.  Fields
+// are matched to members by a `gologix:"MemberName"` struct tag or, without one, by the field name.  Matching is case
+// insensitive and fields with no matching member are left alone.
+//
+// Fields can be atomic go types, strings (for STRING members), nested structs, and arrays or slices of any of those.
+// An any field gets whatever ReadStructMap would give for the member.
+//
+// The template comes from KnownTags so you need to have called ListAllTags (or ListSubTags for program tags) first.
+func (client *Client) ReadStruct(tag string, out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("out must be a pointer to a struct. got %T", out)
+	}
+	err := client.checkConnection()
+	if err != nil {
+		return fmt.Errorf("could not start struct read: %w", err)
+	}
+
+	desc, err := client.templateForTag(tag)
+	if err != nil {
+		return err
+	}
+
+	val, err := client.Read_single(tag, CIPTypeStruct, 1)
+	if err != nil {
+		return err
+	}
+	dat, ok := val.([]byte)
+	if !ok {
+		return fmt.Errorf("tag %s isn't a structure. got %T", tag, val)
+	}
+
+	return client.decodeStruct(dat, desc, v.Elem())
+}
+
+// Read a structure tag into a map from member name to value, for when there's no go struct to read it into.
+//
+// Each member is decoded at the offset the controller's template gives for it.  Atomic members come back as the go type
+// of their CIP type and BOOLs as bools taken from the bit of the hidden SINT that holds them.  STRING members, and other
+// structures with LEN and DATA members, come back as go strings and any other structure as a nested map.  Array
+// members are a []any of those, except BOOL arrays which are a []bool.  The hidden members that hold the BOOLs are
+// left out.
+//
+// The template comes from KnownTags so you need to have called ListAllTags (or ListSubTags for program tags) first.
+func (client *Client) ReadStructMap(tag string) (map[string]any, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return nil, fmt.Errorf("could not start struct read: %w", err)
+	}
+
+	desc, err := client.templateForTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := client.Read_single(tag, CIPTypeStruct, 1)
+	if err != nil {
+		return nil, err
+	}
+	dat, ok := val.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("tag %s isn't a structure. got %T", tag, val)
+	}
+
+	return client.decodeStructMap(dat, desc)
+}
+
+// the start of the names of the SINT members the controller adds to a structure to hold its BOOLs.
+const hiddenMemberPrefix = "ZZZZZZZZZZ"
+
+// decode the structure data dat laid out per desc into a map of its members.  See ReadStructMap.
+func (client *Client) decodeStructMap(dat []byte, desc UDTDescriptor) (map[string]any, error) {
+	values := make(map[string]any, len(desc.Members))
+	for _, m := range desc.Members {
+		if strings.HasPrefix(m.Name, hiddenMemberPrefix) {
+			continue
+		}
+		var v any
+		err := client.decodeMember(dat, m, reflect.ValueOf(&v).Elem())
+		if err != nil {
+			return values, fmt.Errorf("problem decoding %s.%s: %w", desc.Name, m.Name, err)
+		}
+		values[m.Name] = v
+	}
+	return values, nil
+}
+
+// split a tag into the name of the tag in KnownTags and the member names after it.  Array indexes are left off the
+// base but not the members.
+func splitTagBase(tag string) (string, []string) {
+	parts := strings.Split(tag, ".")
+	// program scoped tags have the program as the first part.
+	base := parts[0]
+	rest := parts[1:]
+	if strings.HasPrefix(strings.ToLower(base), "program:") && len(rest) > 0 {
+		base = base + "." + rest[0]
+		rest = rest[1:]
+	}
+	if pos := array_access_regex.FindStringIndex(base); pos != nil {
+		base = base[:pos[0]]
+	}
+	return base, rest
+}
+
+// find the template for a tag.  The tag can be a member of another structure.
+func (client *Client) templateForTag(tag string) (UDTDescriptor, error) {
+	base, rest := splitTagBase(tag)
+	known, ok := client.KnownTags[strings.ToLower(base)]
+	if !ok {
+		return UDTDescriptor{}, fmt.Errorf("tag %s isn't in KnownTags. ListAllTags needs to be called first", base)
+	}
+	if known.UDT == nil {
+		return UDTDescriptor{}, fmt.Errorf("tag %s isn't a structure", base)
+	}
+
+	desc := *known.UDT
+	for _, part := range rest {
+		if pos := array_access_regex.FindStringIndex(part); pos != nil {
+			part = part[:pos[0]]
+		}
+		m, ok := desc.member(part)
+		if !ok {
+			return UDTDescriptor{}, fmt.Errorf("%s has no member %s", desc.Name, part)
+		}
+		if m.Info.Type&memberTypeStruct == 0 {
+			return UDTDescriptor{}, fmt.Errorf("member %s of %s isn't a structure", part, desc.Name)
+		}
+		var err error
+		desc, err = client.templateByID(uint32(m.Info.Type & memberTypeTemplate))
+		if err != nil {
+			return UDTDescriptor{}, err
+		}
+	}
+	return desc, nil
+}
+
+// get a template by its instance, asking the controller only if we haven't seen it before.
+func (client *Client) templateByID(instance uint32) (UDTDescriptor, error) {
+	for _, u := range client.KnownTypes {
+		if u.Instance_ID == instance {
+			return u, nil
+		}
+	}
+	u, err := client.ListMembers(instance)
+	if err != nil {
+		return UDTDescriptor{}, fmt.Errorf("problem getting template %d: %w", instance, err)
+	}
+	if client.KnownTypes == nil {
+		client.KnownTypes = make(map[string]UDTDescriptor)
+	}
+	client.KnownTypes[u.Name] = u
+	return u, nil
+}
+
+// find a member by name ignoring case.
+func (u UDTDescriptor) member(name string) (UDTMemberDescriptor, bool) {
+	for _, m := range u.Members {
+		if strings.EqualFold(m.Name, name) {
+			return m, true
+		}
+	}
+	return UDTMemberDescriptor{}, false
+}
+
+// decode the structure data dat laid out per desc into the go struct v.
+func (client *Client) decodeStruct(dat []byte, desc UDTDescriptor, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, ok := field.Tag.Lookup("gologix")
+		if !ok {
+			name = field.Name
+		}
+		m, ok := desc.member(name)
+		if !ok {
+			continue
+		}
+		err := client.decodeMember(dat, m, v.Field(i))
+		if err != nil {
+			return fmt.Errorf("problem decoding %s.%s: %w", desc.Name, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// decode one template member out of the structure data into the field fv.
+func (client *Client) decodeMember(dat []byte, m UDTMemberDescriptor, fv reflect.Value) error {
+	offset := int(m.Info.Offset)
+	if offset > len(dat) {
+		return fmt.Errorf("offset %d past the end of the %d bytes of data", offset, len(dat))
+	}
+	dat = dat[offset:]
+
+	var elemSize int
+	var nested UDTDescriptor
+	isStruct := m.Info.Type&memberTypeStruct != 0
+	if isStruct {
+		var err error
+		nested, err = client.templateByID(uint32(m.Info.Type & memberTypeTemplate))
+		if err != nil {
+			return err
+		}
+		elemSize = nested.Size()
+	} else {
+		elemSize = m.Info.CIPType().Size()
+	}
+
+	if m.Info.Type&memberTypeArray != 0 {
+		count := int(m.Info.Info)
+		if !isStruct && m.Info.CIPType() == CIPTypeDWORD && (isBoolList(fv) || fv.Kind() == reflect.Interface) {
+			// BOOL arrays are packed into DWORDs.  Info is the number of bools.
+			return decodeBoolMember(dat, count, fv, client.byteOrder())
+		}
+		elems := fv
+		switch fv.Kind() {
+		case reflect.Slice:
+			fv.Set(reflect.MakeSlice(fv.Type(), count, count))
+		case reflect.Array:
+			count = min(count, fv.Len())
+		case reflect.Interface:
+			// there's no go type to go by so the elements go in a []any.
+			elems = reflect.ValueOf(make([]any, count))
+		default:
+			return fmt.Errorf("array member needs an array or slice field. got %v", fv.Type())
+		}
+		for i := 0; i < count; i++ {
+			start := i * elemSize
+			if start > len(dat) {
+				return fmt.Errorf("element %d past the end of the data", i)
+			}
+			err := client.decodeElement(dat[start:], m, nested, isStruct, elems.Index(i))
+			if err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		if fv.Kind() == reflect.Interface {
+			fv.Set(elems)
+		}
+		return nil
+	}
+
+	return client.decodeElement(dat, m, nested, isStruct, fv)
+}
+
+// decode a single (non-array) value of member m from the start of dat into fv.
+func (client *Client) decodeElement(dat []byte, m UDTMemberDescriptor, nested UDTDescriptor, isStruct bool, fv reflect.Value) error {
+	if isStruct {
+		size := min(int(nested.Info.SizeBytes), len(dat))
+		if fv.Kind() == reflect.String || fv.Kind() == reflect.Interface && isStringTemplate(nested) {
+			if !isStringTemplate(nested) {
+				return fmt.Errorf("%s can't go in a string", nested.Name)
+			}
+			layout, err := newStringLayout(nested)
+			if err != nil {
+				return err
+			}
+			s, err := layout.parse(dat[:size], client.byteOrder())
+			if err != nil {
+				return err
+			}
+			if fv.Kind() == reflect.String {
+				fv.SetString(s)
+			} else {
+				fv.Set(reflect.ValueOf(s))
+			}
+			return nil
+		}
+		if fv.Kind() == reflect.Interface {
+			values, err := client.decodeStructMap(dat[:size], nested)
+			if err != nil {
+				return err
+			}
+			fv.Set(reflect.ValueOf(values))
+			return nil
+		}
+		if fv.Kind() != reflect.Struct {
+			return fmt.Errorf("%s needs a struct field. got %v", nested.Name, fv.Type())
+		}
+		return client.decodeStruct(dat[:size], nested, fv)
+	}
+
+	ct := m.Info.CIPType()
+	var value any
+	if ct == CIPTypeBOOL {
+		// BOOL members are a bit in a hidden SINT.  Info holds the bit number.
+		if len(dat) < 1 {
+			return fmt.Errorf("no data for BOOL")
+		}
+		value = dat[0]&(1<<(m.Info.Info&0x07)) != 0
+	} else {
+		var err error
+		value, err = readValueOrder(ct, bytes.NewReader(dat), client.byteOrder())
+		if err != nil {
+			return err
+		}
+	}
+
+	rv := reflect.ValueOf(value)
+	if !rv.Type().AssignableTo(fv.Type()) {
+		return fmt.Errorf("member is %v which can't go in a field of type %v", ct, fv.Type())
+	}
+	fv.Set(rv)
+	return nil
+}
+
+// whether a template is a string type.  Custom string types have a different name but always have the same LEN and
+// DATA members.
+func isStringTemplate(u UDTDescriptor) bool {
+	_, hasLen := u.member("LEN")
+	_, hasData := u.member("DATA")
+	return hasLen && hasData
+}
+
+// whether fv is a slice or array of bools.
+func isBoolList(fv reflect.Value) bool {
+	k := fv.Kind()
+	return (k == reflect.Slice || k == reflect.Array) && fv.Type().Elem().Kind() == reflect.Bool
+}
+
+// unpack count BOOLs stored 32 to a DWORD at the start of dat into fv.
+func decodeBoolMember(dat []byte, count int, fv reflect.Value, order binary.ByteOrder) error {
+	words := (count + 31) / 32
+	if len(dat) < words*4 {
+		return fmt.Errorf("need %d bytes for %d bools. got %d", words*4, count, len(dat))
+	}
+	packed := make([]uint32, words)
+	for i := range packed {
+		packed[i] = order.Uint32(dat[i*4:])
+	}
+	bools := unpackBools(packed, 0, count)
+	if fv.Kind() == reflect.Slice || fv.Kind() == reflect.Interface {
+		fv.Set(reflect.ValueOf(bools).Convert(fv.Type()))
+		return nil
+	}
+	for i := 0; i < min(count, fv.Len()); i++ {
+		fv.Index(i).SetBool(bools[i])
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type testStructInner struct {
+	B int32
+	A int16
+}
+
+type testStructOuter struct {
+	Total   int32 `gologix:"Count"`
+	Flag    bool
+	Temps   [3]float32
+	Inner   testStructInner
+	Arr     []testStructInner
+	Name    string
+	Bits    []bool
+	Ignored int
+}
+
+// the template and data of an Outer structure with one of every kind of member.  The Inner and STRING templates it
+// uses are put in the client's KnownTypes.
+func testOuterStruct(client *Client) (UDTDescriptor, []byte) {
+	inner := UDTDescriptor{
+		Instance_ID: 200,
+		Name:        "Inner",
+		Info:        msgGetTemplateAttrListResponse{SizeBytes: 8},
+		Members: []UDTMemberDescriptor{
+			{Name: "A", Info: msgMemberInfo{Type: uint16(CIPTypeINT), Offset: 0}},
+			{Name: "B", Info: msgMemberInfo{Type: uint16(CIPTypeDINT), Offset: 4}},
+		},
+	}
+	str := UDTDescriptor{
+		Instance_ID: 300,
+		Name:        "STRING",
+		Info:        msgGetTemplateAttrListResponse{SizeBytes: 88},
+		Members: []UDTMemberDescriptor{
+			{Name: "LEN", Info: msgMemberInfo{Type: uint16(CIPTypeDINT), Offset: 0}},
+			{Name: "DATA", Info: msgMemberInfo{Info: 82, Type: 0x2000 | uint16(CIPTypeSINT), Offset: 4}},
+		},
+	}
+	client.KnownTypes["Inner"] = inner
+	client.KnownTypes["STRING"] = str
+
+	// members are deliberately not in the same order as the go struct.
+	outer := UDTDescriptor{
+		Instance_ID: 100,
+		Name:        "Outer",
+		Info:        msgGetTemplateAttrListResponse{SizeBytes: 140},
+		Members: []UDTMemberDescriptor{
+			{Name: "ZZZZZZZZZZOuter0", Info: msgMemberInfo{Type: uint16(CIPTypeSINT), Offset: 0}},
+			{Name: "Flag", Info: msgMemberInfo{Info: 2, Type: uint16(CIPTypeBOOL), Offset: 0}},
+			{Name: "Count", Info: msgMemberInfo{Type: uint16(CIPTypeDINT), Offset: 4}},
+			{Name: "Temps", Info: msgMemberInfo{Info: 3, Type: 0x2000 | uint16(CIPTypeREAL), Offset: 8}},
+			{Name: "Inner", Info: msgMemberInfo{Type: 0x8000 | 200, Offset: 20}},
+			{Name: "Arr", Info: msgMemberInfo{Info: 2, Type: 0xA000 | 200, Offset: 28}},
+			{Name: "Name", Info: msgMemberInfo{Type: 0x8000 | 300, Offset: 44}},
+			{Name: "Bits", Info: msgMemberInfo{Info: 40, Type: 0x2000 | uint16(CIPTypeDWORD), Offset: 132}},
+		},
+	}
+
+	dat := make([]byte, 140)
+	dat[0] = 0b100 // Flag is bit 2
+	binary.LittleEndian.PutUint32(dat[4:], 1234)
+	for i, f := range []float32{1.5, 2.5, 3.5} {
+		binary.LittleEndian.PutUint32(dat[8+i*4:], math.Float32bits(f))
+	}
+	binary.LittleEndian.PutUint16(dat[20:], 7)
+	binary.LittleEndian.PutUint32(dat[24:], 8)
+	binary.LittleEndian.PutUint16(dat[28:], 9)
+	binary.LittleEndian.PutUint32(dat[32:], 10)
+	binary.LittleEndian.PutUint16(dat[36:], 11)
+	binary.LittleEndian.PutUint32(dat[40:], 12)
+	binary.LittleEndian.PutUint32(dat[44:], 5)
+	copy(dat[48:], "hello")
+	binary.LittleEndian.PutUint32(dat[132:], 1)
+	binary.LittleEndian.PutUint32(dat[136:], 1<<7) // bool 39
+	return outer, dat
+}
+
+func TestDecodeStruct(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	outer, dat := testOuterStruct(client)
+
+	var have testStructOuter
+	have.Ignored = 42
+	err := client.decodeStruct(dat, outer, reflect.ValueOf(&have).Elem())
+	if err != nil {
+		t.Fatalf("problem decoding: %v", err)
+	}
+
+	if have.Total != 1234 {
+		t.Errorf("Count: wanted 1234 got %v", have.Total)
+	}
+	if !have.Flag {
+		t.Errorf("Flag should be set")
+	}
+	if have.Temps != [3]float32{1.5, 2.5, 3.5} {
+		t.Errorf("Temps: got %v", have.Temps)
+	}
+	if have.Inner != (testStructInner{A: 7, B: 8}) {
+		t.Errorf("Inner: got %+v", have.Inner)
+	}
+	if len(have.Arr) != 2 || have.Arr[0] != (testStructInner{A: 9, B: 10}) || have.Arr[1] != (testStructInner{A: 11, B: 12}) {
+		t.Errorf("Arr: got %+v", have.Arr)
+	}
+	if have.Name != "hello" {
+		t.Errorf("Name: got %q", have.Name)
+	}
+	if len(have.Bits) != 40 || !have.Bits[0] || have.Bits[1] || !have.Bits[39] {
+		t.Errorf("Bits: got %v", have.Bits)
+	}
+	if have.Ignored != 42 {
+		t.Errorf("fields without a member should be left alone")
+	}
+}
+
+func TestDecodeStructTypeMismatch(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	desc := UDTDescriptor{
+		Name:    "Bad",
+		Members: []UDTMemberDescriptor{{Name: "Count", Info: msgMemberInfo{Type: uint16(CIPTypeREAL), Offset: 0}}},
+	}
+	var have struct{ Count int32 }
+	err := client.decodeStruct(make([]byte, 4), desc, reflect.ValueOf(&have).Elem())
+	if err == nil {
+		t.Errorf("a REAL member shouldn't decode into an int32 field")
+	}
+}
+
+// a BOOL array member is unpacked in the client's byte order into any kind of bool slice.
+func TestDecodeBoolMember(t *testing.T) {
+	type flags []bool
+	dat := []byte{0x00, 0x00, 0x00, 0x05}
+	var have flags
+	err := decodeBoolMember(dat, 32, reflect.ValueOf(&have).Elem(), binary.BigEndian)
+	if err != nil {
+		t.Fatalf("problem decoding bools: %v", err)
+	}
+	if len(have) != 32 || !have[0] || have[1] || !have[2] || have[3] {
+		t.Errorf("wanted bools 0 and 2 set. got %v", have)
+	}
+}
+
+func TestDecodeStructMap(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	outer, dat := testOuterStruct(client)
+
+	have, err := client.decodeStructMap(dat, outer)
+	if err != nil {
+		t.Fatalf("problem decoding: %v", err)
+	}
+	bits := make([]bool, 40)
+	bits[0] = true
+	bits[39] = true
+	want := map[string]any{
+		"Flag":  true,
+		"Count": int32(1234),
+		"Temps": []any{float32(1.5), float32(2.5), float32(3.5)},
+		"Inner": map[string]any{"A": int16(7), "B": int32(8)},
+		"Arr": []any{
+			map[string]any{"A": int16(9), "B": int32(10)},
+			map[string]any{"A": int16(11), "B": int32(12)},
+		},
+		"Name": "hello",
+		"Bits": bits,
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("wanted %#v\ngot %#v", want, have)
+	}
+}
+
+func TestReadStructMap(t *testing.T) {
+	client, remote := newPipeClient(t)
+	outer, dat := testOuterStruct(client)
+	client.KnownTags = map[string]KnownTag{"thing": {Name: "Thing", UDT: &outer}}
+	go func() {
+		hdr, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: CIPTypeStruct, Unknown: 2})
+		item.Serialize(uint16(0x1234)) // the structure handle
+		item.Serialize(dat)
+		b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	have, err := client.ReadStructMap("Thing")
+	if err != nil {
+		t.Fatalf("problem reading: %v", err)
+	}
+	if have["Count"] != int32(1234) || have["Name"] != "hello" {
+		t.Errorf("unexpected values %v", have)
+	}
+	if _, ok := have["ZZZZZZZZZZOuter0"]; ok {
+		t.Errorf("the hidden BOOL host shouldn't be in the map")
+	}
+
+	_, err = client.ReadStructMap("NotListed")
+	if err == nil {
+		t.Errorf("a tag that isn't in KnownTags should be an error")
+	}
+}
@@ -0,0 +1,76 @@
+package gologix
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Read a list of tags into a map from tag name to value that encoding/json can always encode.
+//
+// This is ReadMultiple with the values cleaned up for JSON: STRING tags come back as a go string cut off at the first
+// null character, BOOLs as bools, and REALs or LREALs that are NaN or ±Inf as nil so they encode as null instead of
+// failing the whole encode.  Other structures are left as their raw bytes which encode as base64.
+//
+// Tags that can't be read are left out of the map and their errors are joined into the returned error.  The map has
+// every tag that was read even when the error isn't nil.
+func (client *Client) ReadTagMap(tags []string) (map[string]any, error) {
+	results, errs := client.ReadMultiple(tags)
+	m := make(map[string]any, len(tags))
+	failed := make([]error, 0)
+	for i, tag := range tags {
+		if errs[i] != nil {
+			failed = append(failed, fmt.Errorf("problem reading %s: %w", tag, errs[i]))
+			continue
+		}
+		m[tag] = jsonValue(results[i])
+	}
+	return m, errors.Join(failed...)
+}
+
+// convert a value read from the controller into something json.Marshal can encode.
+func jsonValue(v any) any {
+	switch x := v.(type) {
+	case string:
+		if i := strings.IndexByte(x, 0); i >= 0 {
+			return x[:i]
+		}
+		return x
+	case float32:
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return nil
+		}
+		return x
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil
+		}
+		return x
+	case []float32:
+		out := make([]any, len(x))
+		for i := range x {
+			out[i] = jsonValue(x[i])
+		}
+		return out
+	case []float64:
+		out := make([]any, len(x))
+		for i := range x {
+			out[i] = jsonValue(x[i])
+		}
+		return out
+	case []string:
+		out := make([]any, len(x))
+		for i := range x {
+			out[i] = jsonValue(x[i])
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i := range x {
+			out[i] = jsonValue(x[i])
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,54 @@
+package gologix
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestReadTagMap(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{
+		"speed":   float32(math.NaN()),
+		"running": true,
+		"count":   int32(3),
+	})
+
+	m, err := client.ReadTagMap([]string{"Speed", "Running", "Count", "NotThere"})
+	if !IsTagNotFound(err) {
+		t.Errorf("wanted tag not found for the missing tag. got %v", err)
+	}
+	if _, ok := m["NotThere"]; ok {
+		t.Errorf("the missing tag shouldn't be in the map")
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("problem encoding: %v", err)
+	}
+	want := `{"Count":3,"Running":true,"Speed":null}`
+	if string(b) != want {
+		t.Errorf("wanted %s got %s", want, b)
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	var tests = []struct {
+		in   any
+		want string
+	}{
+		{"abc\x00\x00garbage", `"abc"`},
+		{"", `""`},
+		{math.Inf(1), `null`},
+		{[]float32{1, float32(math.NaN())}, `[1,null]`},
+		{[]any{"a\x00b", int16(2)}, `["a",2]`},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(jsonValue(tt.in))
+		if err != nil {
+			t.Errorf("%v: problem encoding: %v", tt.in, err)
+			continue
+		}
+		if string(b) != tt.want {
+			t.Errorf("%q: wanted %s got %s", tt.in, tt.want, b)
+		}
+	}
+}
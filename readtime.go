@@ -0,0 +1,86 @@
+package gologix
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeEncoding is how a 64 bit integer tag holds a point in time: a count of Units since Epoch.
+//
+// Logix keeps the UTC wall clock time, the one GetPLCTime reads, as a LINT of microseconds since 1970-01-01 UTC, which
+// is what the zero value means.  The WallClockTime object's CurrentValue is the same count in the controller's local
+// time instead (see GetPLCTimeZoneOffset).  Set the Client's TimeEncoding to something else for tags that count from a
+// different epoch or in different units.
+type TimeEncoding struct {
+	Epoch time.Time     // the time a value of 0 stands for.  The zero time.Time means 1970-01-01 UTC.
+	Unit  time.Duration // how long a count of 1 is.  0 means time.Microsecond.
+}
+
+// Microseconds since 1970-01-01 UTC.  This is how Logix stores UTC wall clock time.
+var LogixWallClock = TimeEncoding{Epoch: time.Unix(0, 0).UTC(), Unit: time.Microsecond}
+
+// Nanoseconds since 1970-01-01 UTC.
+var UnixNano = TimeEncoding{Epoch: time.Unix(0, 0).UTC(), Unit: time.Nanosecond}
+
+func (e TimeEncoding) epoch() time.Time {
+	if e.Epoch.IsZero() {
+		return time.Unix(0, 0).UTC()
+	}
+	return e.Epoch
+}
+
+func (e TimeEncoding) unit() time.Duration {
+	if e.Unit <= 0 {
+		return time.Microsecond
+	}
+	return e.Unit
+}
+
+// The time a raw value from the controller stands for.
+func (e TimeEncoding) Time(v int64) time.Time {
+	unit := e.unit()
+	if unit >= time.Second || time.Second%unit != 0 {
+		return e.epoch().Add(time.Duration(v) * unit)
+	}
+	// count whole seconds and the rest separately so counts far from the epoch don't overflow a Duration.
+	epoch := e.epoch()
+	per_second := int64(time.Second / unit)
+	secs := epoch.Unix() + v/per_second
+	nanos := int64(epoch.Nanosecond()) + (v%per_second)*int64(unit)
+	return time.Unix(secs, nanos).In(epoch.Location())
+}
+
+// The raw value for the controller that stands for t.  Anything finer than Unit is truncated.
+func (e TimeEncoding) Value(t time.Time) int64 {
+	unit := e.unit()
+	if unit >= time.Second || time.Second%unit != 0 {
+		return int64(t.Sub(e.epoch()) / unit)
+	}
+	// whole seconds and the rest separately for the same reason as in Time.
+	secs := t.Unix() - e.epoch().Unix()
+	nanos := int64(t.Nanosecond() - e.epoch().Nanosecond())
+	return secs*int64(time.Second/unit) + nanos/int64(unit)
+}
+
+// Read a 64 bit integer tag (LINT, ULINT, LWORD, or LTIME) that holds a point in time and convert it using the
+// client's TimeEncoding.  The default reads Logix wall clock time: microseconds since 1970-01-01 UTC.  A tag that
+// holds local time instead, like one filled in from the controller's CurrentValue, is ahead of UTC by what
+// GetPLCTimeZoneOffset gives so subtract that from the result.
+func (client *Client) ReadTime(tag string) (time.Time, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not start time read: %w", err)
+	}
+	val, err := client.read_single(tag, CIPTypeLINT, 1, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch v := val.(type) {
+	case int64:
+		return client.TimeEncoding.Time(v), nil
+	case uint64:
+		return client.TimeEncoding.Time(int64(v)), nil
+	default:
+		return time.Time{}, fmt.Errorf("type mismatch: tag %s is a %T which can't hold a time", tag, val)
+	}
+}
@@ -0,0 +1,67 @@
+package gologix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeEncoding(t *testing.T) {
+	when := time.Date(2026, 10, 14, 8, 30, 15, 123456000, time.UTC)
+	var tests = []struct {
+		name string
+		enc  TimeEncoding
+		raw  int64
+	}{
+		{"zero value", TimeEncoding{}, when.UnixMicro()},
+		{"logix wall clock", LogixWallClock, when.UnixMicro()},
+		{"unix nano", UnixNano, when.UnixNano()},
+		{"milliseconds since 2000", TimeEncoding{Epoch: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Unit: time.Millisecond}, when.Sub(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).Milliseconds()},
+		{"minutes", TimeEncoding{Unit: time.Minute}, when.Unix() / 60},
+	}
+	for _, tt := range tests {
+		have := tt.enc.Time(tt.raw)
+		want := when.Truncate(tt.enc.unit())
+		if !have.Equal(want) {
+			t.Errorf("%s: %d should be %v. got %v", tt.name, tt.raw, want, have)
+		}
+		if v := tt.enc.Value(when); v != tt.raw {
+			t.Errorf("%s: %v should be %d. got %d", tt.name, when, tt.raw, v)
+		}
+	}
+
+	// far enough out that the count in nanoseconds wouldn't fit in a time.Duration
+	far := time.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC)
+	if have := LogixWallClock.Time(LogixWallClock.Value(far)); !have.Equal(far) {
+		t.Errorf("wanted %v to round trip. got %v", far, have)
+	}
+}
+
+func TestReadTime(t *testing.T) {
+	client, remote := newPipeClient(t)
+	when := time.Date(2026, 10, 14, 8, 30, 15, 123456000, time.UTC)
+
+	go func() {
+		hdr, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: CIPTypeLINT})
+		item.Serialize(when.UnixMicro())
+		items, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *items)
+	}()
+
+	have, err := client.ReadTime("WallClock")
+	if err != nil {
+		t.Fatalf("problem reading time: %v", err)
+	}
+	if !have.Equal(when) {
+		t.Errorf("wanted %v got %v", when, have)
+	}
+}
@@ -0,0 +1,169 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ReconnectBackoff controls how AutoReconnect retries when the controller can't be reached.
+//
+// The first attempt is made right away.  After each failed attempt the client waits before trying again,
+// starting at Initial and multiplying by Multiplier each time up to Max.
+type ReconnectBackoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	Attempts   int // give up after this many attempts.  Anything less than 1 is treated as 1.
+}
+
+// how long to wait after the given failed attempt (starting at 1) before the next one.
+func (b ReconnectBackoff) delay(attempt int) time.Duration {
+	d := float64(b.Initial)
+	mult := b.Multiplier
+	if mult < 1 {
+		mult = 1
+	}
+	for i := 1; i < attempt; i++ {
+		d *= mult
+		if b.Max > 0 && d >= float64(b.Max) {
+			return b.Max
+		}
+	}
+	if b.Max > 0 && d > float64(b.Max) {
+		return b.Max
+	}
+	return time.Duration(d)
+}
+
+// re-establish the session after the connection with generation gen died.  If the socket is still up only the cip
+// connection is redone, with a full reconnect if that doesn't work.
+//
+// Reconnects are serialized.  If another caller already reconnected while we were waiting on the lock
+// there is nothing to do.
+func (client *Client) reconnect(gen uint32, cause error) error {
+	client.reconnect_lock.Lock()
+	defer client.reconnect_lock.Unlock()
+
+	if client.connected && client.connGeneration.Load() != gen {
+		return nil
+	}
+	client.Logger.Info("connection lost. reconnecting", "error", cause)
+	if client.connected {
+		// the socket and session are still up so try redoing just the cip connection first.
+		err := client.refreshConnection()
+		if err == nil {
+			if client.OnReconnect != nil {
+				client.OnReconnect(1, nil)
+			}
+			return nil
+		}
+		// a failed refresh leaves the client disconnected.
+		client.Logger.Warn("refreshing connection failed. reconnecting the session", "error", err)
+	}
+
+	attempts := client.ReconnectBackoff.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = client.Connect()
+		if err == nil && !client.connected {
+			err = errors.New("connect in progress elsewhere")
+		}
+		if client.OnReconnect != nil {
+			client.OnReconnect(attempt, err)
+		}
+		if err == nil {
+			return nil
+		}
+		client.Logger.Warn("reconnect attempt failed", "attempt", attempt, "error", err)
+		if attempt < attempts {
+			time.Sleep(client.ReconnectBackoff.delay(attempt))
+		}
+	}
+	return fmt.Errorf("gave up after %d attempts: %w", attempts, err)
+}
+
+// Close the cip connection and open a new one over the same session and socket without registering the session again.
+//
+// This is quicker than Disconnect and Connect for when the controller dropped the connection, say because it sat idle
+// past its timeout, but the socket is still open.  The controller refusing the forward close because the connection
+// is already gone is fine.  If the forward open fails the client is disconnected and the error returned.
+//
+// AutoReconnect does this on its own before falling back to a full reconnect when the socket is still open.
+func (client *Client) RefreshConnection() error {
+	client.reconnect_lock.Lock()
+	defer client.reconnect_lock.Unlock()
+	return client.refreshConnection()
+}
+
+func (client *Client) refreshConnection() error {
+	if !client.connected || client.connecting || client.disconnecting {
+		return errors.New("not connected")
+	}
+	err := func() error {
+		// send errors are left for us to deal with instead of disconnecting or reconnecting on their own.
+		client.connecting = true
+		defer func() { client.connecting = false }()
+		client.stopHeartbeat()
+
+		item, err := client.newForwardClose()
+		if err != nil {
+			return err
+		}
+		itemData, err := serializeItems([]CIPItem{{}, item})
+		if err != nil {
+			return fmt.Errorf("problem serializing forward close: %w", err)
+		}
+		header, data, err := client.send_recv_data(cipCommandSendRRData, itemData)
+		if err != nil && !errors.Is(err, ErrTimeout) {
+			return fmt.Errorf("problem sending forward close: %w", err)
+		}
+		if err == nil {
+			_, err = client.parseResponse(&header, data)
+		}
+		if err != nil {
+			client.Logger.Debug("forward close failed. opening a new connection anyway", slog.Any("err", err))
+		}
+		return client.openConnection()
+	}()
+	if err != nil {
+		client.Disconnect()
+		return fmt.Errorf("problem refreshing connection: %w", err)
+	}
+	client.connGeneration.Add(1)
+	if client.ConnectionKeepAlive {
+		client.startHeartbeat()
+	}
+	return nil
+}
+
+// connected messages have the OT connection id baked into their address item.  After a reconnect the id is
+// different so the message has to be updated before it is resent.
+func retargetConnection(msgs []any, connID uint32) []any {
+	if len(msgs) == 0 {
+		return msgs
+	}
+	b, ok := msgs[0].(*[]byte)
+	if !ok || b == nil {
+		return msgs
+	}
+	// items header (8 bytes) followed by the first item's id and length.
+	dat := *b
+	if len(dat) < 16 ||
+		CIPItemID(binary.LittleEndian.Uint16(dat[8:])) != cipItem_ConnectionAddress ||
+		binary.LittleEndian.Uint16(dat[10:]) != 4 {
+		return msgs
+	}
+	updated := make([]byte, len(dat))
+	copy(updated, dat)
+	binary.LittleEndian.PutUint32(updated[12:], connID)
+	out := make([]any, len(msgs))
+	copy(out, msgs)
+	out[0] = &updated
+	return out
+}
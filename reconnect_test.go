@@ -0,0 +1,298 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestReconnectBackoffDelay(t *testing.T) {
+	b := ReconnectBackoff{Initial: time.Millisecond * 100, Max: time.Second, Multiplier: 2}
+	want := []time.Duration{
+		time.Millisecond * 100,
+		time.Millisecond * 200,
+		time.Millisecond * 400,
+		time.Millisecond * 800,
+		time.Second,
+		time.Second,
+	}
+	for i, w := range want {
+		if have := b.delay(i + 1); have != w {
+			t.Errorf("attempt %d: wanted %v got %v", i+1, w, have)
+		}
+	}
+
+	// a multiplier below 1 shouldn't shrink the delay
+	b = ReconnectBackoff{Initial: time.Millisecond * 100, Multiplier: 0}
+	if have := b.delay(5); have != time.Millisecond*100 {
+		t.Errorf("wanted constant delay. got %v", have)
+	}
+}
+
+func TestRetargetConnection(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.OTNetworkConnectionID = 0x11223344
+
+	reqItems := make([]CIPItem, 2)
+	reqItems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
+	reqItems[1] = newItem(cipItem_ConnectedData, uint16(0xBEEF))
+	itemData, err := serializeItems(reqItems)
+	if err != nil {
+		t.Fatalf("problem serializing items: %v", err)
+	}
+
+	original := append([]byte{}, *itemData...)
+	msgs := retargetConnection([]any{itemData}, 0xAABBCCDD)
+	updated := *(msgs[0].(*[]byte))
+	if id := binary.LittleEndian.Uint32(updated[12:]); id != 0xAABBCCDD {
+		t.Errorf("connection id should have been replaced. got %X", id)
+	}
+	if string(*itemData) != string(original) {
+		t.Errorf("original message should not be modified")
+	}
+	if string(updated[16:]) != string(original[16:]) {
+		t.Errorf("the rest of the message should be unchanged")
+	}
+
+	// unconnected messages start with a null address item and are left alone
+	reqItems[0] = CIPItem{}
+	itemData, err = serializeItems(reqItems)
+	if err != nil {
+		t.Fatalf("problem serializing items: %v", err)
+	}
+	msgs = retargetConnection([]any{itemData}, 0xAABBCCDD)
+	if msgs[0].(*[]byte) != itemData {
+		t.Errorf("unconnected message should pass through untouched")
+	}
+}
+
+// play the controller's side of a RefreshConnection.  The forward close is refused like it is when the controller
+// already dropped the connection and the forward open gives the connection otID.
+func serveRefresh(t *testing.T, remote net.Conn, otID uint32) {
+	for _, service := range []CIPService{CIPService_ForwardClose, CIPService_LargeForwardOpen} {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading %v: %v", service, err)
+			return
+		}
+		if hdr.Command != cipCommandSendRRData {
+			t.Errorf("wanted a %v with send rr data. got command %v", service, hdr.Command)
+			return
+		}
+		// interface handle, timeout, item count, the null item, and the unconnected data item header come first.
+		if have := CIPService(buf.Bytes()[16]); have != service {
+			t.Errorf("wanted %v got %v", service, have)
+			return
+		}
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_UnconnectedData}}
+		if service == CIPService_ForwardClose {
+			item.Serialize(msgCIPMessageRouterResponse{Service: service.AsResponse(), Status: CIPStatus_ConnectionFailure, StatusLen: 1})
+			item.Serialize(uint16(0x0107)) // connection not found
+		} else {
+			item.Serialize(msgCIPMessageRouterResponse{Service: service.AsResponse()})
+			item.Serialize(msgCipForwardOpenReply{OtNetworkConnectionId: otID, TOConnectionId: 0x5678, OTApiNs: 2500000, TOApiNs: 2500000})
+		}
+		items, err := serializeItems([]CIPItem{{}, item})
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *items)
+	}
+}
+
+func TestRefreshConnection(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.OTNetworkConnectionID = 0x1111
+	gen := client.connGeneration.Load()
+	go serveRefresh(t, remote, 0x2222)
+
+	err := client.RefreshConnection()
+	if err != nil {
+		t.Fatalf("problem refreshing connection: %v", err)
+	}
+	if !client.connected {
+		t.Errorf("should still be connected")
+	}
+	if client.OTNetworkConnectionID != 0x2222 {
+		t.Errorf("wanted connection id 0x2222 got %#x", client.OTNetworkConnectionID)
+	}
+	if client.connGeneration.Load() == gen {
+		t.Errorf("the connection generation should change")
+	}
+
+	if err := NewClient("127.0.0.1").RefreshConnection(); err == nil {
+		t.Errorf("refreshing a disconnected client should fail")
+	}
+}
+
+// the controller drops the cip connection and ignores connected messages on it but the socket stays open.  A ping
+// with AutoReconnect should only redo the forward open.
+func TestPingRefreshesConnection(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.AutoReconnect = true
+	client.SocketTimeout = time.Millisecond * 50
+	client.OTNetworkConnectionID = 0x1111
+
+	go func() {
+		// the first ping goes unanswered.
+		_, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading first ping: %v", err)
+			return
+		}
+		serveRefresh(t, remote, 0x2222)
+
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading second ping: %v", err)
+			return
+		}
+		if hdr.Command != cipCommandSendUnitData {
+			t.Errorf("wanted the ping again. got command %v", hdr.Command)
+		}
+		if have := binary.LittleEndian.Uint32(buf.Bytes()[12:]); have != 0x2222 {
+			t.Errorf("ping should go over the new connection 0x2222. got %#x", have)
+		}
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(uint16(1))
+		item.Serialize([]byte{byte(CIPService_GetAttributeSingle.AsResponse()), 0})
+		item.Serialize(uint16(0))
+		item.Serialize(uint16(vendorIdDefault))
+		b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	err := client.Ping()
+	if err != nil {
+		t.Fatalf("ping should have refreshed the connection: %v", err)
+	}
+	if client.OTNetworkConnectionID != 0x2222 {
+		t.Errorf("wanted connection id 0x2222 got %#x", client.OTNetworkConnectionID)
+	}
+}
+
+// a read on a connection the controller dropped is answered with a status saying so.  With AutoReconnect the
+// connection is redone over the same session and the read sent again.
+func TestReadRefreshesConnection(t *testing.T) {
+	var tests = []struct {
+		name  string
+		reply []byte // the answer to the first read.
+	}{
+		{"connection not found", connectionFailureReply(t, CIPStatus_ConnectionFailure, 0x0107)},
+		{"connection lost", connectionFailureReply(t, CIPStatus_ConnectionLost, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, remote := newPipeClient(t)
+			client.AutoReconnect = true
+			client.SocketTimeout = time.Millisecond * 50
+			client.OTNetworkConnectionID = 0x1111
+
+			go func() {
+				hdr, _, err := recvData(remote)
+				if err != nil {
+					t.Errorf("problem reading first read: %v", err)
+					return
+				}
+				writeTestResponse(t, remote, hdr.Context, tt.reply)
+				serveRefresh(t, remote, 0x2222)
+
+				hdr, buf, err := recvData(remote)
+				if err != nil {
+					t.Errorf("problem reading second read: %v", err)
+					return
+				}
+				if have := binary.LittleEndian.Uint32(buf.Bytes()[12:]); have != 0x2222 {
+					t.Errorf("read should go over the new connection 0x2222. got %#x", have)
+				}
+				writeTestResponse(t, remote, hdr.Context, dintReadReply(t, 42))
+			}()
+
+			v, err := Read[int32](client, "Tag")
+			if err != nil {
+				t.Fatalf("read should have refreshed the connection: %v", err)
+			}
+			if v != 42 {
+				t.Errorf("wanted 42 got %d", v)
+			}
+			if client.OTNetworkConnectionID != 0x2222 {
+				t.Errorf("wanted connection id 0x2222 got %#x", client.OTNetworkConnectionID)
+			}
+		})
+	}
+}
+
+// a read that just goes unanswered may still have reached the controller so it isn't sent again, even with
+// AutoReconnect.
+func TestReadTimeoutDoesntRefresh(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.AutoReconnect = true
+	client.SocketTimeout = time.Millisecond * 50
+	client.OTNetworkConnectionID = 0x1111
+
+	go func() {
+		_, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading the read: %v", err)
+		}
+	}()
+
+	_, err := Read[int32](client, "Tag")
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("wanted ErrTimeout got %v", err)
+	}
+	if client.OTNetworkConnectionID != 0x1111 {
+		t.Errorf("the connection shouldn't be redone. got connection id %#x", client.OTNetworkConnectionID)
+	}
+	if !client.connected {
+		t.Errorf("a timeout shouldn't close the session")
+	}
+}
+
+// a connected reply with only a status, like the controller sends for a connection it doesn't have.
+func connectionFailureReply(t *testing.T, status CIPStatus, extended uint16) []byte {
+	item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+	item.Serialize(uint16(1))
+	if extended == 0 {
+		item.Serialize([]byte{byte(CIPService_Read.AsResponse()), 0, byte(status), 0})
+	} else {
+		item.Serialize([]byte{byte(CIPService_Read.AsResponse()), 0, byte(status), 1})
+		item.Serialize(extended)
+	}
+	b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+	if err != nil {
+		t.Fatalf("problem building reply: %v", err)
+	}
+	return *b
+}
+
+// a request that can't be built never goes out so it shouldn't redo the connection either.
+func TestPrepareErrorDoesntReconnect(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.AutoReconnect = true
+	gen := client.connGeneration.Load()
+
+	sent := make(chan CIPCommand, 1)
+	go func() {
+		hdr, _, err := recvData(remote)
+		if err == nil {
+			sent <- hdr.Command
+		}
+	}()
+
+	_, _, err := client.send_recv_data(cipCommandSendUnitData, "not a message")
+	if err == nil {
+		t.Fatalf("a string can't be sent")
+	}
+	select {
+	case cmd := <-sent:
+		t.Errorf("nothing should be sent. got command %v", cmd)
+	case <-time.After(time.Millisecond * 50):
+	}
+	if !client.connected || client.connGeneration.Load() != gen {
+		t.Errorf("the connection should be left alone")
+	}
+}
@@ -0,0 +1,177 @@
+package gologix
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Registry holds clients to several controllers by name so a gateway doesn't have to juggle them itself.
+//
+// Clients connect the first time they are used and reconnect on their own after that.  Metrics and OnReconnect on
+// the registry are handed to every client added that doesn't have its own.  Close disconnects all of them.  Use
+// NewRegistry to create one.
+type Registry struct {
+	// Given to clients added after it is set that don't have their own Metrics.
+	Metrics MetricsObserver
+
+	// Called after every reconnect attempt of any client with the name of the client.  Given to clients added after it
+	// is set that don't have their own OnReconnect.
+	OnReconnect func(name string, attempt int, err error)
+
+	mu      sync.Mutex
+	clients map[string]*Client
+	closed  bool
+}
+
+// Create an empty registry.  Add clients to it with Add, AddURL, or AddDiscovered.
+func NewRegistry() *Registry {
+	return &Registry{clients: make(map[string]*Client)}
+}
+
+// Add a client under name.  It isn't connected until the first read or write through it.
+//
+// AutoConnect and AutoReconnect are turned on so the registry can bring it up and keep it up.  A name can only be
+// used once.
+func (r *Registry) Add(name string, client *Client) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errors.New("registry is closed")
+	}
+	if _, ok := r.clients[name]; ok {
+		return fmt.Errorf("already have a client named %q", name)
+	}
+	client.AutoConnect = true
+	client.AutoReconnect = true
+	if client.Metrics == nil {
+		client.Metrics = r.Metrics
+	}
+	if client.OnReconnect == nil && r.OnReconnect != nil {
+		onReconnect := r.OnReconnect
+		client.OnReconnect = func(attempt int, err error) {
+			onReconnect(name, attempt, err)
+		}
+	}
+	r.clients[name] = client
+	return nil
+}
+
+// Add a client under name from a url like NewClientFromURL takes.
+func (r *Registry) AddURL(name string, rawURL string) error {
+	client, err := NewClientFromURL(rawURL)
+	if err != nil {
+		return fmt.Errorf("problem with url for %s: %w", name, err)
+	}
+	return r.Add(name, client)
+}
+
+// Find devices on the local subnet with Discover and add a client for each one name gives a name.  Devices name
+// returns false for are skipped.  Returns how many clients were added.
+//
+// A device whose name is already taken is an error and stops adding, the ones added before it stay.
+func (r *Registry) AddDiscovered(timeout time.Duration, name func(Identity) (string, bool)) (int, error) {
+	ids, err := Discover(timeout)
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for _, id := range ids {
+		n, ok := name(id)
+		if !ok {
+			continue
+		}
+		err = r.Add(n, NewClient(id.IP.String()))
+		if err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
+
+// The client added under name.
+func (r *Registry) Client(name string) (*Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, errors.New("registry is closed")
+	}
+	client, ok := r.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("no client named %q", name)
+	}
+	return client, nil
+}
+
+// The names of all the clients in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Read a tag from the controller named name.  See Client.Read
+func (r *Registry) Read(name string, tag string, data any) error {
+	client, err := r.Client(name)
+	if err != nil {
+		return err
+	}
+	return client.Read(tag, data)
+}
+
+// Write a tag to the controller named name.  See Client.Write
+func (r *Registry) Write(name string, tag string, value any) error {
+	client, err := r.Client(name)
+	if err != nil {
+		return err
+	}
+	return client.Write(tag, value)
+}
+
+// Take the client named name out of the registry and disconnect it if it is connected.
+func (r *Registry) Remove(name string) error {
+	r.mu.Lock()
+	client, ok := r.clients[name]
+	delete(r.clients, name)
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no client named %q", name)
+	}
+	if !client.Connected() {
+		return nil
+	}
+	return client.Disconnect()
+}
+
+// Disconnect every client that is connected.  Reads and writes through the registry fail after this.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	clients := r.clients
+	r.clients = make(map[string]*Client)
+	r.mu.Unlock()
+
+	var errs []error
+	for name, client := range clients {
+		if !client.Connected() {
+			continue
+		}
+		err := client.Disconnect()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,88 @@
+package gologix
+
+import (
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	client, remote := newPipeClient(t)
+	client.AutoConnect = false
+	if err := r.Add("plc1", client); err != nil {
+		t.Fatalf("problem adding client: %v", err)
+	}
+	if !client.AutoConnect || !client.AutoReconnect {
+		t.Errorf("registry clients should auto connect and reconnect")
+	}
+	if err := r.Add("plc1", NewClient("127.0.0.2")); err == nil {
+		t.Errorf("adding a name twice should fail")
+	}
+	if err := r.AddURL("plc2", "eip://127.0.0.3/slot/2"); err != nil {
+		t.Fatalf("problem adding by url: %v", err)
+	}
+	if names := r.Names(); len(names) != 2 || names[0] != "plc1" || names[1] != "plc2" {
+		t.Errorf("wanted [plc1 plc2] got %v", names)
+	}
+
+	go func() {
+		hdr, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, dintReadReply(t, 42))
+	}()
+	var v int32
+	if err := r.Read("plc1", "Tag", &v); err != nil {
+		t.Fatalf("problem reading: %v", err)
+	}
+	if v != 42 {
+		t.Errorf("wanted 42 got %d", v)
+	}
+	if err := r.Read("nope", "Tag", &v); err == nil {
+		t.Errorf("reading from an unknown client should fail")
+	}
+
+	if err := r.Remove("plc2"); err != nil {
+		t.Errorf("problem removing unconnected client: %v", err)
+	}
+	client.connected = false
+	if err := r.Close(); err != nil {
+		t.Errorf("problem closing: %v", err)
+	}
+	if err := r.Read("plc1", "Tag", &v); err == nil {
+		t.Errorf("reading after close should fail")
+	}
+	if err := r.Add("plc3", NewClient("127.0.0.4")); err == nil {
+		t.Errorf("adding after close should fail")
+	}
+}
+
+func TestRegistryHandsOutCallbacks(t *testing.T) {
+	r := NewRegistry()
+	var gotName string
+	r.OnReconnect = func(name string, attempt int, err error) { gotName = name }
+	r.Metrics = &testObserver{}
+
+	client := NewClient("127.0.0.1")
+	own := &testObserver{}
+	client.Metrics = own
+	if err := r.Add("line3", client); err != nil {
+		t.Fatalf("problem adding client: %v", err)
+	}
+	if client.Metrics != own {
+		t.Errorf("a client's own metrics should be kept")
+	}
+	client.OnReconnect(1, nil)
+	if gotName != "line3" {
+		t.Errorf("wanted reconnect from line3 got %q", gotName)
+	}
+
+	other := NewClient("127.0.0.2")
+	if err := r.Add("line4", other); err != nil {
+		t.Fatalf("problem adding client: %v", err)
+	}
+	if other.Metrics != r.Metrics {
+		t.Errorf("the registry's metrics should be given to a client without any")
+	}
+}
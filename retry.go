@@ -0,0 +1,74 @@
+package gologix
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryPolicy says which errors from reads and writes are worth sending the same request again for.  The zero value
+// never retries.
+//
+// Only CIP statuses in Statuses are retried.  Tag not found and privilege violations are never retried even if they
+// are in the list since sending the request again can't help.  Timeouts aren't retried either because a write that
+// timed out may have happened.
+//
+// Single reads and writes are retried, and so are the multi-service requests of ReadList, ReadMultiple and
+// WriteMultiple when the request fails as a whole.  ReadStruct and ReadStructMap read the tag like Read so they are
+// retried too.
+type RetryPolicy struct {
+	Attempts int           // the most times to try a request including the first.  Anything less than 2 doesn't retry.
+	Delay    time.Duration // how long to wait before each retry.
+
+	// the statuses to retry.  One without Extended matches its general status whatever the extended status is and one
+	// with Extended also has to match the first extended status.  nil retries resource unavailable (0x02), which is
+	// what a busy controller sends, and connection in use (0x01 with extended status 0x0100).
+	Statuses []CIPError
+
+	// if set, called before every retry with the tag, the attempt that failed (starting at 1), and its error.
+	OnRetry func(tag string, attempt int, err error)
+}
+
+// whether err is one the policy retries.
+func (p RetryPolicy) retryable(err error) bool {
+	if err == nil || IsTagNotFound(err) || IsPrivilegeViolation(err) {
+		return false
+	}
+	var cerr *CIPError
+	if !errors.As(err, &cerr) {
+		return false
+	}
+	statuses := p.Statuses
+	if statuses == nil {
+		statuses = defaultRetryStatuses
+	}
+	for _, s := range statuses {
+		if s.General == cerr.General && (len(s.Extended) == 0 || s.extended() == cerr.extended()) {
+			return true
+		}
+	}
+	return false
+}
+
+// the statuses RetryPolicy retries when Statuses is nil.
+var defaultRetryStatuses = []CIPError{
+	{General: byte(CIPStatus_ResourceUnavailable)},
+	{General: byte(CIPStatus_ConnectionFailure), Extended: []uint16{0x0100}},
+}
+
+// run f until it works, gives an error RetryPolicy doesn't retry, or runs out of attempts.  The last error is returned.
+func (client *Client) withRetry(tag string, f func() error) error {
+	p := client.RetryPolicy
+	attempt := 1
+	for {
+		err := f()
+		if attempt >= p.Attempts || !p.retryable(err) {
+			return err
+		}
+		client.Logger.Debug("retrying", "tag", tag, "attempt", attempt, "error", err)
+		if p.OnRetry != nil {
+			p.OnRetry(tag, attempt, err)
+		}
+		time.Sleep(p.Delay)
+		attempt++
+	}
+}
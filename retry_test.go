@@ -0,0 +1,218 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// build the reply to a read that failed with status.
+func failedReadReply(t *testing.T, status CIPStatus) []byte {
+	items := []CIPItem{
+		newItem(cipItem_ConnectionAddress, uint32(0)),
+		{Header: cipItemHeader{ID: cipItem_ConnectedData}},
+	}
+	items[1].Serialize([]byte{0, 0, byte(CIPService_Read.AsResponse()), 0, byte(status), 0})
+	b, err := serializeItems(items)
+	if err != nil {
+		t.Fatalf("problem building reply: %v", err)
+	}
+	return *b
+}
+
+func TestRetryPolicy(t *testing.T) {
+	client, remote := newPipeClient(t)
+	retries := make([]int, 0)
+	client.RetryPolicy = RetryPolicy{
+		Attempts: 3,
+		Delay:    time.Millisecond,
+		OnRetry:  func(tag string, attempt int, err error) { retries = append(retries, attempt) },
+	}
+
+	replies := [][]byte{
+		failedReadReply(t, CIPStatus_ResourceUnavailable),
+		failedReadReply(t, CIPStatus_ResourceUnavailable),
+		dintReadReply(t, 42),
+		failedReadReply(t, CIPStatus_PathDestinationUnknown),
+		failedReadReply(t, CIPStatus_ResourceUnavailable),
+	}
+	requests := make(chan int, 1)
+	go func() {
+		n := 0
+		for _, reply := range replies {
+			hdr, _, err := recvData(remote)
+			if err != nil {
+				break
+			}
+			n++
+			writeTestResponse(t, remote, hdr.Context, reply)
+		}
+		requests <- n
+	}()
+
+	var v int32
+	err := client.Read("Busy", &v)
+	if err != nil || v != 42 {
+		t.Fatalf("wanted 42 after two retries. got %v %v", v, err)
+	}
+	if len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+		t.Errorf("wanted retries after attempts 1 and 2. got %v", retries)
+	}
+
+	// tag not found is never retried so the next read is the only request for it.
+	err = client.Read("Missing", &v)
+	if !IsTagNotFound(err) {
+		t.Errorf("wanted tag not found. got %v", err)
+	}
+	if len(retries) != 2 {
+		t.Errorf("tag not found shouldn't be retried. got retries %v", retries)
+	}
+
+	// with one attempt left the busy reply is what you get back.
+	client.RetryPolicy.Attempts = 1
+	err = client.Read("Busy", &v)
+	status, ok := cipGeneral(err)
+	if !ok || status != CIPStatus_ResourceUnavailable {
+		t.Errorf("wanted resource unavailable. got %v", err)
+	}
+
+	client.DebugCloseConn()
+	select {
+	case n := <-requests:
+		if n != len(replies) {
+			t.Errorf("wanted %d requests got %d", len(replies), n)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("timed out")
+	}
+}
+
+func TestRetryPolicyStatuses(t *testing.T) {
+	busy := &CIPError{General: byte(CIPStatus_ResourceUnavailable)}
+	lost := &CIPError{General: byte(CIPStatus_ConnectionLost)}
+	missing := &CIPError{General: byte(CIPStatus_PathDestinationUnknown)}
+
+	inUse := &CIPError{General: byte(CIPStatus_ConnectionFailure), Extended: []uint16{0x0100}}
+	notFound := &CIPError{General: byte(CIPStatus_ConnectionFailure), Extended: []uint16{0x0107}}
+
+	p := RetryPolicy{}
+	if !p.retryable(busy) || !p.retryable(inUse) || p.retryable(notFound) || p.retryable(lost) || p.retryable(ErrTimeout) {
+		t.Errorf("default statuses should only retry resource unavailable and connection in use")
+	}
+	p.Statuses = []CIPError{{General: byte(CIPStatus_ConnectionLost)}, {General: byte(CIPStatus_PathDestinationUnknown)}}
+	if p.retryable(busy) || !p.retryable(lost) {
+		t.Errorf("a status list should replace the default")
+	}
+	if p.retryable(missing) {
+		t.Errorf("tag not found should never be retried")
+	}
+	p.Statuses = []CIPError{{General: byte(CIPStatus_ConnectionFailure)}}
+	if !p.retryable(inUse) || !p.retryable(notFound) {
+		t.Errorf("a status without extended should match any extended status")
+	}
+	p.Statuses = []CIPError{{General: byte(CIPStatus_ConnectionFailure), Extended: []uint16{0x0107}}}
+	if p.retryable(inUse) || !p.retryable(notFound) {
+		t.Errorf("a status with extended should only match that extended status")
+	}
+}
+
+// the reply to a multi-service request that failed as a whole with status and one extended status word.
+func failedMultiReply(t *testing.T, status CIPStatus, extended uint16) []byte {
+	items := []CIPItem{
+		newItem(cipItem_ConnectionAddress, uint32(0)),
+		{Header: cipItemHeader{ID: cipItem_ConnectedData}},
+	}
+	items[1].Serialize([]byte{0, 0, byte(CIPService_MultipleService.AsResponse()), 0, byte(status), 1})
+	items[1].Serialize(extended)
+	b, err := serializeItems(items)
+	if err != nil {
+		t.Fatalf("problem building reply: %v", err)
+	}
+	return *b
+}
+
+// the reply to a multi-service request where every service got the same reply.
+func multiReply(t *testing.T, service CIPService, qty int, reply []byte) []byte {
+	dat := []byte{0, 0, byte(CIPService_MultipleService.AsResponse()), 0, 0, 0}
+	dat = binary.LittleEndian.AppendUint16(dat, uint16(qty))
+	for i := 0; i < qty; i++ {
+		dat = binary.LittleEndian.AppendUint16(dat, uint16(2+2*qty+(4+len(reply))*i))
+	}
+	for i := 0; i < qty; i++ {
+		dat = append(dat, byte(service.AsResponse()), 0, 0, 0)
+		dat = append(dat, reply...)
+	}
+	items := []CIPItem{
+		newItem(cipItem_ConnectionAddress, uint32(0)),
+		{Header: cipItemHeader{ID: cipItem_ConnectedData}},
+	}
+	items[1].Serialize(dat)
+	b, err := serializeItems(items)
+	if err != nil {
+		t.Fatalf("problem building reply: %v", err)
+	}
+	return *b
+}
+
+// a multi-service request the controller turns down as a whole because the connection is in use is sent again, and so
+// is the read of a structure.
+func TestRetryMultipleRequests(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.RetryPolicy = RetryPolicy{Attempts: 2, Delay: time.Millisecond}
+	pt := Template{Name: "Pt", Members: []UDTMemberDescriptor{{Name: "X", Info: msgMemberInfo{Type: uint16(CIPTypeDINT)}}}}
+	client.KnownTags["pt"] = KnownTag{Name: "Pt", Info: TagInfo{Type: CIPTypeStruct}, UDT: &pt}
+
+	dint := binary.LittleEndian.AppendUint16(nil, uint16(CIPTypeDINT))
+	dint = binary.LittleEndian.AppendUint32(dint, 42)
+	structReply := []CIPItem{
+		newItem(cipItem_ConnectionAddress, uint32(0)),
+		{Header: cipItemHeader{ID: cipItem_ConnectedData}},
+	}
+	structReply[1].Serialize(msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: CIPTypeStruct, Unknown: 2})
+	structReply[1].Serialize(uint16(0x1234)) // the structure handle
+	structReply[1].Serialize(int32(42))
+	b, err := serializeItems(structReply)
+	if err != nil {
+		t.Fatalf("problem building reply: %v", err)
+	}
+	replies := [][]byte{
+		failedMultiReply(t, CIPStatus_ConnectionFailure, 0x0100),
+		multiReply(t, CIPService_Read, 2, dint),
+		failedMultiReply(t, CIPStatus_ConnectionFailure, 0x0100),
+		multiReply(t, CIPService_Read, 1, dint),
+		failedMultiReply(t, CIPStatus_ResourceUnavailable, 0),
+		multiReply(t, CIPService_Write, 1, nil),
+		failedReadReply(t, CIPStatus_ResourceUnavailable),
+		*b,
+	}
+	go func() {
+		for _, reply := range replies {
+			hdr, _, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			writeTestResponse(t, remote, hdr.Context, reply)
+		}
+	}()
+
+	vals, errs := client.ReadMultiple([]string{"A", "B"})
+	for i := range vals {
+		if errs[i] != nil || vals[i] != int32(42) {
+			t.Errorf("ReadMultiple %d: wanted 42 got %v %v", i, vals[i], errs[i])
+		}
+	}
+	vals, err = client.ReadList([]string{"A"}, []CIPType{CIPTypeDINT}, []int{1})
+	if err != nil || len(vals) != 1 || vals[0] != int32(42) {
+		t.Errorf("ReadList: wanted 42 got %v %v", vals, err)
+	}
+	errs = client.WriteMultiple([]TagWrite{{Tag: "A", Value: int32(1)}})
+	if errs[0] != nil {
+		t.Errorf("WriteMultiple: %v", errs[0])
+	}
+	var p struct{ X int32 }
+	err = client.ReadStruct("Pt", &p)
+	if err != nil || p.X != 42 {
+		t.Errorf("ReadStruct: wanted 42 got %v %v", p.X, err)
+	}
+}
@@ -3,7 +3,10 @@ package gologix
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"time"
 )
 
@@ -16,34 +19,28 @@ type eipHeader struct {
 	Options       uint32
 }
 
-// prepares a bytes buffer for sending a message
-func (client *Client) sendMsgBuild(cmd CIPCommand, msgs ...any) ([]byte, error) {
+// builds a message into buf for sending.  buf usually comes from getSendBuffer.
+func (client *Client) sendMsgBuild(buf *bytes.Buffer, cmd CIPCommand, msgs ...any) error {
 	// calculate messageLen of all message parts
 	messageLen := 0
 	for _, msg := range msgs {
-		messageLen += binary.Size(msg)
+		messageLen += msgSize(msg)
 	}
 	// build header based on size
 	hdr := client.newEIPHeader(cmd, messageLen)
 
-	// initialize a buffer and add the header to it.
-	// the 24 is from the header size
-	b := make([]byte, 0, messageLen+24)
-	buf := bytes.NewBuffer(b)
-	err := binary.Write(buf, binary.LittleEndian, hdr)
-	if err != nil {
-		return nil, fmt.Errorf("problem writing header to buffer. %w", err)
-	}
+	buf.Grow(messageLen + eipHeaderSize)
+	buf.Write(hdr.appendTo(buf.AvailableBuffer()))
 
 	// add all message components to the buffer.
 	for _, msg := range msgs {
-		err = binary.Write(buf, binary.LittleEndian, msg)
+		err := writeMsg(buf, msg)
 		if err != nil {
-			return nil, fmt.Errorf("problem writing msg to buffer. %w", err)
+			return fmt.Errorf("problem writing msg to buffer. %w", err)
 		}
 	}
 
-	return buf.Bytes(), nil
+	return nil
 }
 
 // send takes the command followed by all the structures that need
@@ -54,11 +51,7 @@ func (client *Client) sendData(b []byte) error {
 	// write the packet buffer to the tcp connection
 	written := 0
 	for written < len(b) {
-		if client.SocketTimeout != 0 {
-			client.conn.SetWriteDeadline(time.Now().Add(client.SocketTimeout))
-		} else {
-			client.conn.SetWriteDeadline(time.Now().Add(time.Second))
-		}
+		client.conn.SetWriteDeadline(time.Now().Add(client.socketTimeout()))
 		n, err := client.conn.Write(b[written:])
 		if err != nil {
 			return fmt.Errorf("problem writing to socket: %w", err)
@@ -70,16 +63,103 @@ func (client *Client) sendData(b []byte) error {
 }
 
 // sends one message and gets one response in a mutex-protected way.
+//
+// If AutoReconnect is set and the socket fails, the session is re-established and the message is sent one more time.
+// If the socket is still up but the controller dropped the cip connection, only the connection is redone.
 func (client *Client) send_recv_data(cmd CIPCommand, msgs ...any) (eipHeader, *bytes.Buffer, error) {
-	buffer, err := client.sendMsgBuild(cmd, msgs...)
-	if err != nil {
-		return eipHeader{}, nil, fmt.Errorf("error preparing to send message: %w", err)
+	return client.send_recv_data_timeout(client.socketTimeout(), cmd, msgs...)
+}
+
+// same as send_recv_data but waits at most timeout for the response.
+//
+// A timeout leaves the session up and returns ErrTimeout.  If the response shows up later it is thrown away instead
+// of being taken as the response to whatever request is sent next.
+//
+// With AutoReconnect a connected message answered with a status saying the connection is gone gets the cip connection
+// redone over the same session before it is sent again.  A timeout is returned as is even then since the controller
+// may have already acted on the request.
+func (client *Client) send_recv_data_timeout(timeout time.Duration, cmd CIPCommand, msgs ...any) (eipHeader, *bytes.Buffer, error) {
+	gen := client.connGeneration.Load()
+	hdr, buf, err := client.send_recv_once(gen, timeout, cmd, msgs...)
+	if err == nil && (cmd != cipCommandSendUnitData || !client.AutoReconnect || !connectionGone(buf)) {
+		return hdr, buf, nil
+	}
+	if !client.AutoReconnect || client.connecting || client.disconnecting {
+		return hdr, buf, err
+	}
+	if cmd != cipCommandSendUnitData && cmd != cipCommandSendRRData {
+		return hdr, buf, err
+	}
+	var perr prepareError
+	if errors.As(err, &perr) {
+		// nothing was sent so there is nothing wrong with the connection.
+		return hdr, buf, err
+	}
+	if err == nil {
+		err = errors.New("controller says the connection is gone")
+	} else if errors.Is(err, ErrTimeout) && client.connected && client.connGeneration.Load() == gen {
+		// the session is fine.  the controller is just slow.
+		return hdr, buf, err
+	}
+	// with the socket still up this only redoes the cip connection.
+	rerr := client.reconnect(gen, err)
+	if rerr != nil {
+		return hdr, buf, fmt.Errorf("%w: reconnect failed: %w", err, rerr)
+	}
+	msgs = retargetConnection(msgs, client.OTNetworkConnectionID)
+	return client.send_recv_once(client.connGeneration.Load(), timeout, cmd, msgs...)
+}
+
+// send one message and get one response on the connection with generation gen.
+//
+// The write is mutex-protected but the wait for the response isn't, so any number of requests can be in flight at
+// once.  The read pump hands each response to the request with the matching sender context.
+func (client *Client) send_recv_once(gen uint32, timeout time.Duration, cmd CIPCommand, msgs ...any) (hdr eipHeader, buf *bytes.Buffer, err error) {
+	start := time.Now()
+	var buffer []byte
+	sendBuf := getSendBuffer()
+	// deferred first so it runs after the metrics are done looking at what was sent.
+	defer putSendBuffer(sendBuf)
+	if client.Metrics != nil {
+		defer func() {
+			var received []byte
+			if buf != nil {
+				received = buf.Bytes()
+			}
+			client.observeRequest(cmd, start, buffer, hdr, received, err)
+		}()
 	}
-	client.mutex.Lock()
 
-	err = client.sendData(buffer)
+	// the header is built under the lock too since it bumps the header sequence counter.
+	client.mutex.Lock()
+	err = client.sendMsgBuild(sendBuf, cmd, msgs...)
+	buffer = sendBuf.Bytes()
 	if err != nil {
 		client.mutex.Unlock()
+		return eipHeader{}, nil, fmt.Errorf("error preparing to send message: %w", prepareError{err})
+	}
+	senderContext := binary.LittleEndian.Uint64(buffer[12:20])
+
+	resp, err := client.addPending(senderContext)
+	if err == nil {
+		err = client.sendData(buffer)
+		if err != nil {
+			client.removePending(senderContext)
+		} else if cmd == cipCommandSendUnitData {
+			client.lastUnitData.Store(time.Now().UnixNano())
+		}
+	}
+	client.mutex.Unlock()
+	if err != nil {
+		if client.connecting {
+			// Connect() is still in progress and will clean up the socket itself.
+			// Calling Disconnect() here would wait on ourselves forever.
+			return eipHeader{}, nil, fmt.Errorf("error sending data during connect: %w", err)
+		}
+		if client.connGeneration.Load() != gen {
+			// somebody already replaced the connection we were using.
+			return eipHeader{}, nil, fmt.Errorf("error sending data on stale connection: %w", err)
+		}
 		err2 := client.Disconnect()
 		if err2 != nil {
 			return eipHeader{}, nil, fmt.Errorf("error disconnecting after send error %w: %w", err, err2)
@@ -87,49 +167,201 @@ func (client *Client) send_recv_data(cmd CIPCommand, msgs ...any) (eipHeader, *b
 		return eipHeader{}, nil, fmt.Errorf("error sending data resulting in forced disconnect: %w", err)
 	}
 
-	hdr, buf, err := client.recvData()
-	client.mutex.Unlock()
+	timer := getTimer(timeout)
+	var r pumpResponse
+	select {
+	case r = <-resp:
+		putTimer(timer)
+		// the pump is done with the channel once it has sent on it.
+		pendingPool.Put(resp)
+	case <-timer.C:
+		// the stream is still in sync.  If the response shows up later the pump won't find anybody waiting on it
+		// and will drop it.
+		client.removePending(senderContext)
+		return eipHeader{}, nil, fmt.Errorf("no response after %v: %w", timeout, ErrTimeout)
+	}
+
+	err = r.err
 	if err != nil {
+		if client.connecting {
+			return r.hdr, r.buf, fmt.Errorf("error receiving data during connect: %w", err)
+		}
+		if client.connGeneration.Load() != gen {
+			return r.hdr, r.buf, fmt.Errorf("error receiving data on stale connection: %w", err)
+		}
 		err2 := client.Disconnect()
 		if err2 != nil {
-			return hdr, buf, fmt.Errorf("error disconnecting after recvError %w: %w", err, err2)
+			return r.hdr, r.buf, fmt.Errorf("error disconnecting after recvError %w: %w", err, err2)
 		}
-		return hdr, buf, fmt.Errorf("error receiving data resulting in forced disconnect: %w", err)
+		return r.hdr, r.buf, fmt.Errorf("error receiving data resulting in forced disconnect: %w", err)
 	}
-	return hdr, buf, nil
+	return r.hdr, r.buf, nil
 }
 
-// recv_data reads the header and then the number of words it specifies.
-func (client *Client) recvData() (eipHeader, *bytes.Buffer, error) {
+// a request that couldn't be built.  Nothing went out on the connection.
+type prepareError struct{ err error }
 
-	hdr := eipHeader{}
-	var err error
-	if client.SocketTimeout != 0 {
-		client.conn.SetReadDeadline(time.Now().Add(client.SocketTimeout))
-	} else {
-		client.conn.SetReadDeadline(time.Now().Add(time.Second))
+func (e prepareError) Error() string { return e.err.Error() }
+func (e prepareError) Unwrap() error { return e.err }
+
+// whether the reply to a connected message says the controller doesn't have the connection any more: a connection
+// failure with connection not found (0x0107) or connection lost.  The reply is left for the caller to read.
+func connectionGone(buf *bytes.Buffer) bool {
+	// items header, connection address item, connected data item header, sequence count, service, and reserved come
+	// before the status.
+	if buf == nil {
+		return false
 	}
-	err = binary.Read(client.conn, binary.LittleEndian, &hdr)
-	if err != nil {
-		return hdr, nil, fmt.Errorf("problem reading header from socket: %w", err)
+	dat := buf.Bytes()
+	if len(dat) < 26 {
+		return false
 	}
-	if client.SocketTimeout != 0 {
-		client.conn.SetReadDeadline(time.Now().Add(client.SocketTimeout))
-	} else {
-		client.conn.SetReadDeadline(time.Now().Add(time.Second))
+	switch CIPStatus(dat[24]) {
+	case CIPStatus_ConnectionLost:
+		return true
+	case CIPStatus_ConnectionFailure:
+		return dat[25] >= 1 && len(dat) >= 28 && binary.LittleEndian.Uint16(dat[26:]) == 0x0107
+	}
+	return false
+}
+
+// what the read pump hands to a waiting request.
+type pumpResponse struct {
+	hdr eipHeader
+	buf *bytes.Buffer
+	err error
+}
+
+// start reading responses off conn in the background.  Replaces the pending requests of any previous connection.
+func (client *Client) startPump(conn net.Conn) {
+	done := make(chan struct{})
+	client.pending_lock.Lock()
+	client.pending = make(map[uint64]chan pumpResponse)
+	client.pump_err = nil
+	client.pump_done = done
+	client.pending_lock.Unlock()
+	go client.readPump(conn, done)
+}
+
+// read responses until the socket fails, giving each to the request waiting on its sender context.
+// When the socket fails every request still waiting gets the error.
+func (client *Client) readPump(conn net.Conn, done chan struct{}) {
+	defer close(done)
+	scratch := make([]byte, eipHeaderSize)
+	for {
+		hdr, buf, err := recvDataInto(conn, scratch)
+		if err != nil {
+			client.pending_lock.Lock()
+			if client.pump_done == done {
+				client.pump_err = fmt.Errorf("connection closed: %w", err)
+				for senderContext, resp := range client.pending {
+					resp <- pumpResponse{err: client.pump_err}
+					delete(client.pending, senderContext)
+				}
+			}
+			client.pending_lock.Unlock()
+			return
+		}
+
+		client.pending_lock.Lock()
+		resp, ok := client.pending[hdr.Context]
+		if ok && client.pump_done == done {
+			delete(client.pending, hdr.Context)
+		}
+		client.pending_lock.Unlock()
+		if !ok {
+			client.Logger.Debug("dropping response nobody is waiting for", "context", hdr.Context)
+			continue
+		}
+		resp <- pumpResponse{hdr: hdr, buf: buf}
+	}
+}
+
+// wait for the read pump of the current connection to stop.
+func (client *Client) waitPump() {
+	client.pending_lock.Lock()
+	done := client.pump_done
+	client.pending_lock.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// register a request so the pump can find it when its response comes in.
+func (client *Client) addPending(senderContext uint64) (chan pumpResponse, error) {
+	client.pending_lock.Lock()
+	defer client.pending_lock.Unlock()
+	if client.pending == nil {
+		return nil, errors.New("not connected")
+	}
+	if client.pump_err != nil {
+		return nil, client.pump_err
 	}
-	data_size := hdr.Length
-	data := make([]byte, data_size)
-	if data_size > 0 {
+	// buffered so the pump never blocks on a request that already gave up.
+	resp := pendingPool.Get().(chan pumpResponse)
+	client.pending[senderContext] = resp
+	return resp, nil
+}
+
+func (client *Client) removePending(senderContext uint64) {
+	client.pending_lock.Lock()
+	delete(client.pending, senderContext)
+	client.pending_lock.Unlock()
+}
+
+// recv_data reads the header and then the number of words it specifies.
+func recvData(conn net.Conn) (eipHeader, *bytes.Buffer, error) {
+	return recvDataInto(conn, make([]byte, eipHeaderSize))
+}
 
-		err = binary.Read(client.conn, binary.LittleEndian, &data)
+// same as recvData but reads the header into scratch, which has to be eipHeaderSize long, so a loop reading many
+// messages doesn't need a new one each time.  The payload is always new since decoded values like structure bytes
+// point into it.
+func recvDataInto(conn net.Conn, scratch []byte) (eipHeader, *bytes.Buffer, error) {
+	_, err := io.ReadFull(conn, scratch)
+	if err != nil {
+		return eipHeader{}, nil, fmt.Errorf("problem reading header from socket: %w", err)
+	}
+	hdr := parseEIPHeader(scratch)
+	data := make([]byte, hdr.Length)
+	if len(data) > 0 {
+		_, err = io.ReadFull(conn, data)
 		if err != nil {
 			return hdr, nil, fmt.Errorf("problem reading socket payload: %w", err)
 		}
 	}
 	buf := bytes.NewBuffer(data)
-	return hdr, buf, err
+	return hdr, buf, nil
+}
 
+// how long to wait on the socket when nothing more specific is set.
+func (client *Client) socketTimeout() time.Duration {
+	if client.SocketTimeout != 0 {
+		return client.SocketTimeout
+	}
+	return time.Second
+}
+
+// how long to wait for the response to a read.  override wins if it is set.
+func (client *Client) readTimeout(override time.Duration) time.Duration {
+	if override > 0 {
+		return override
+	}
+	if client.ReadTimeout > 0 {
+		return client.ReadTimeout
+	}
+	return client.socketTimeout()
+}
+
+// how long to wait for the response to a write.  override wins if it is set.
+func (client *Client) writeTimeout(override time.Duration) time.Duration {
+	if override > 0 {
+		return override
+	}
+	if client.WriteTimeout > 0 {
+		return client.WriteTimeout
+	}
+	return client.socketTimeout()
 }
 
 func (client *Client) DebugCloseConn() {
@@ -144,7 +376,7 @@ func (client *Client) newEIPHeader(cmd CIPCommand, size int) (hdr eipHeader) {
 	hdr.Length = uint16(size)
 	hdr.SessionHandle = client.SessionHandle
 	hdr.Status = 0
-	hdr.Context = client.Context
+	hdr.Context = client.Context + client.requestContext.Add(1)
 	hdr.Options = 0
 
 	return
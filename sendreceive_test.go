@@ -0,0 +1,290 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hook a client up to one end of a pipe as if it were connected.  The other end plays the controller.
+func newPipeClient(t testing.TB) (*Client, net.Conn) {
+	client := NewClient("127.0.0.1")
+	local, remote := net.Pipe()
+	t.Cleanup(func() {
+		local.Close()
+		remote.Close()
+	})
+	client.conn = local
+	client.connected = true
+	client.knownFirmware = 32 // so building IOIs doesn't ask the "controller" for its firmware
+	client.startPump(local)
+	return client, remote
+}
+
+func writeTestResponse(t testing.TB, conn net.Conn, context uint64, payload []byte) {
+	b := bytes.Buffer{}
+	binary.Write(&b, binary.LittleEndian, eipHeader{Command: cipCommandSendUnitData, Length: uint16(len(payload)), Context: context})
+	b.Write(payload)
+	_, err := conn.Write(b.Bytes())
+	if err != nil {
+		t.Errorf("problem writing response: %v", err)
+	}
+}
+
+// a request times out, then its response shows up right before the next one's.  The late response must be dropped
+// instead of being handed to the second request.
+func TestSendRecvTimeoutAndLateResponse(t *testing.T) {
+	client, remote := newPipeClient(t)
+
+	first := make(chan uint64, 1)
+	go func() {
+		hdr, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading first request: %v", err)
+			return
+		}
+		first <- hdr.Context
+	}()
+	_, _, err := client.send_recv_data_timeout(time.Millisecond*20, cipCommandSendUnitData, []byte{1})
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("wanted ErrTimeout got %v", err)
+	}
+	if !client.Connected() {
+		t.Fatalf("a timeout shouldn't end the session")
+	}
+
+	go func() {
+		hdr, _, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading second request: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, <-first, []byte{0xAA})
+		writeTestResponse(t, remote, hdr.Context, []byte{0xBB, 0xCC})
+	}()
+	_, buf, err := client.send_recv_data_timeout(time.Second, cipCommandSendUnitData, []byte{2})
+	if err != nil {
+		t.Fatalf("problem with second request: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), []byte{0xBB, 0xCC}) {
+		t.Errorf("got the wrong response: %v", buf.Bytes())
+	}
+}
+
+// build the reply to a read of a DINT tag.
+func dintReadReply(t testing.TB, value int32) []byte {
+	data := bytes.Buffer{}
+	binary.Write(&data, binary.LittleEndian, msgCIPReadResultData{Service: CIPService_Read.AsResponse(), Type: CIPTypeDINT})
+	binary.Write(&data, binary.LittleEndian, value)
+	items := []CIPItem{
+		newItem(cipItem_ConnectionAddress, uint32(0)),
+		{Header: cipItemHeader{ID: cipItem_ConnectedData}},
+	}
+	items[1].Serialize(data.Bytes())
+	b, err := serializeItems(items)
+	if err != nil {
+		t.Fatalf("problem building reply: %v", err)
+	}
+	return *b
+}
+
+// fire a batch of reads at once and have the "controller" answer them in reverse order.
+// Every read should still get its own value.
+func TestConcurrentReads(t *testing.T) {
+	const count = 10
+	client, remote := newPipeClient(t)
+
+	go func() {
+		type request struct {
+			context uint64
+			value   int32
+		}
+		reqs := make([]request, 0, count)
+		for len(reqs) < count {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			// items header, connection address item, connected data item header, sequence, service, path size.
+			// then the 0x91 symbolic segment with the tag name.
+			dat := buf.Bytes()
+			const pos = 8 + 8 + 4 + 4
+			if dat[pos] != 0x91 {
+				t.Errorf("expected a symbolic segment. got %v", dat)
+				return
+			}
+			name := string(dat[pos+2 : pos+2+int(dat[pos+1])])
+			v, _ := strconv.Atoi(strings.TrimPrefix(name, "tag"))
+			reqs = append(reqs, request{hdr.Context, int32(v * 100)})
+		}
+		for i := len(reqs) - 1; i >= 0; i-- {
+			writeTestResponse(t, remote, reqs[i].context, dintReadReply(t, reqs[i].value))
+		}
+	}()
+
+	wg := sync.WaitGroup{}
+	results := make([]any, count)
+	errs := make([]error, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = client.read_single(fmt.Sprintf("tag%d", i), CIPTypeDINT, 1, time.Second*2)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < count; i++ {
+		if errs[i] != nil {
+			t.Errorf("read %d: %v", i, errs[i])
+			continue
+		}
+		if results[i] != int32(i*100) {
+			t.Errorf("read %d: wanted %d got %v", i, i*100, results[i])
+		}
+	}
+}
+
+// requests waiting on a response when the socket goes away should get an error instead of waiting on a timeout.
+func TestPendingRequestsFailOnClose(t *testing.T) {
+	client, remote := newPipeClient(t)
+	go func() {
+		recvData(remote)
+		remote.Close()
+	}()
+
+	start := time.Now()
+	_, _, err := client.send_recv_data_timeout(time.Second*5, cipCommandSendUnitData, []byte{1})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if errors.Is(err, ErrTimeout) || time.Since(start) > time.Second*2 {
+		t.Errorf("should have failed when the socket closed instead of timing out: %v", err)
+	}
+
+	_, _, err = client.send_recv_data_timeout(time.Second, cipCommandSendUnitData, []byte{1})
+	if err == nil {
+		t.Errorf("requests after the pump stopped should fail")
+	}
+}
+
+func TestRequestTimeouts(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.SocketTimeout = time.Second * 3
+	if have := client.readTimeout(0); have != time.Second*3 {
+		t.Errorf("read should fall back to SocketTimeout. got %v", have)
+	}
+	client.ReadTimeout = time.Second
+	client.WriteTimeout = time.Second * 2
+	if have := client.readTimeout(0); have != time.Second {
+		t.Errorf("wanted ReadTimeout got %v", have)
+	}
+	if have := client.writeTimeout(0); have != time.Second*2 {
+		t.Errorf("wanted WriteTimeout got %v", have)
+	}
+	if have := client.readTimeout(time.Millisecond); have != time.Millisecond {
+		t.Errorf("override should win. got %v", have)
+	}
+}
+
+func TestRequestContextsUnique(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	a := client.newEIPHeader(cipCommandSendUnitData, 0)
+	b := client.newEIPHeader(cipCommandSendUnitData, 0)
+	if a.Context == b.Context {
+		t.Errorf("two requests got the same sender context %d", a.Context)
+	}
+}
+
+// play a controller that answers every request on remote with reply, the same bytes each time but for the sender
+// context.  It allocates nothing per request so a benchmark's allocations are all the client's.
+func cannedResponder(remote net.Conn, reply []byte) {
+	b := bytes.Buffer{}
+	binary.Write(&b, binary.LittleEndian, eipHeader{Command: cipCommandSendUnitData, Length: uint16(len(reply))})
+	b.Write(reply)
+	frame := b.Bytes()
+	req := make([]byte, 4096)
+	for {
+		_, err := io.ReadFull(remote, req[:24])
+		if err != nil {
+			return
+		}
+		n := int(binary.LittleEndian.Uint16(req[2:]))
+		_, err = io.ReadFull(remote, req[24:24+n])
+		if err != nil {
+			return
+		}
+		copy(frame[12:20], req[12:20])
+		_, err = remote.Write(frame)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// a steady state poll of one tag.  Run with -benchmem to see the allocations per read.
+func BenchmarkRead(b *testing.B) {
+	client, remote := newPipeClient(b)
+	go cannedResponder(remote, dintReadReply(b, 1234))
+	client.KnownTags["count"] = KnownTag{Name: "Count", Info: TagInfo{Type: CIPTypeDINT}, Instance: 7}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v, err := Read[int32](client, "Count")
+		if err != nil || v != 1234 {
+			b.Fatalf("bad read: %v %v", v, err)
+		}
+	}
+}
+
+// BenchmarkRead without the any in between.
+func BenchmarkReadInto(b *testing.B) {
+	client, remote := newPipeClient(b)
+	go cannedResponder(remote, dintReadReply(b, 1234))
+	client.KnownTags["count"] = KnownTag{Name: "Count", Info: TagInfo{Type: CIPTypeDINT}, Instance: 7}
+	buf := make([]byte, 4)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, n, err := client.ReadInto("Count", buf)
+		if err != nil || n != 4 || binary.LittleEndian.Uint32(buf) != 1234 {
+			b.Fatalf("bad read: % X %v", buf[:n], err)
+		}
+	}
+}
+
+// items read out of a buffer share its memory so they have to be capped so appending to one can't change the next.
+func TestReadItemsBufferCapped(t *testing.T) {
+	items := []CIPItem{newItem(cipItem_ConnectionAddress, uint32(0x11223344)), newItem(cipItem_ConnectedData, uint16(0x5566))}
+	b, err := serializeItems(items)
+	if err != nil {
+		t.Fatalf("problem serializing items: %v", err)
+	}
+	// serializeItems starts with the interface handle and sequence counter which readItems doesn't take.
+	fromBuffer, err := readItems(bytes.NewBuffer((*b)[6:]))
+	if err != nil {
+		t.Fatalf("problem reading items: %v", err)
+	}
+	fromReader, err := readItems(bytes.NewReader((*b)[6:]))
+	if err != nil {
+		t.Fatalf("problem reading items: %v", err)
+	}
+	if len(fromBuffer) != 2 || !bytes.Equal(fromBuffer[0].Data, fromReader[0].Data) || !bytes.Equal(fromBuffer[1].Data, fromReader[1].Data) {
+		t.Fatalf("buffer and reader disagree: %+v vs %+v", fromBuffer, fromReader)
+	}
+	fromBuffer[0].Serialize(uint32(0xFFFFFFFF))
+	if !bytes.Equal(fromBuffer[1].Data, []byte{0x66, 0x55}) {
+		t.Errorf("appending to item 0 changed item 1 to % X", fromBuffer[1].Data)
+	}
+}
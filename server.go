@@ -3,7 +3,9 @@ package gologix
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"math/rand"
 	"net"
@@ -57,28 +59,70 @@ func NewServer(r *PathRouter) *Server {
 	return &srv
 }
 
-// Start listening on the TCP and UDP ports associated with the Ethernet/IP protocol.
-// these are 44818 and 2222 respectively
-// as far as I can tell there is never an option to change this on any devices so it is hard coded here.
-func (srv *Server) Serve() error {
-	srv.ConnMgr.Init(srv.Logger)
-
+// Open the TCP and UDP listeners on the given addresses without serving them yet.
+//
+// Serve listens on the standard ports, which is what you want for real devices.  This is for everything else, like
+// tests that want to run a server on "127.0.0.1:0" and point a client at whatever port it got (see TCPAddr).
+func (srv *Server) Listen(tcpAddr, udpAddr string) error {
 	var err error
-	srv.TCPListener, err = net.Listen("tcp", "0.0.0.0:44818")
-	srv.Logger.Info("Listening on TCP port 44818")
+	srv.TCPListener, err = net.Listen("tcp", tcpAddr)
 	if err != nil {
 		return fmt.Errorf("couldn't open tcp listener. %w", err)
 	}
+	srv.Logger.Info("Listening on TCP", "address", srv.TCPListener.Addr().String())
 
-	srv.UDPListener, err = net.ListenPacket("udp", "0.0.0.0:2222")
-	srv.Logger.Info("Listening on UDP port 2222")
+	srv.UDPListener, err = net.ListenPacket("udp", udpAddr)
 	if err != nil {
-		return fmt.Errorf("couldn't open udp listener. %v", err)
+		srv.TCPListener.Close()
+		return fmt.Errorf("couldn't open udp listener. %w", err)
 	}
+	srv.Logger.Info("Listening on UDP", "address", srv.UDPListener.LocalAddr().String())
+	return nil
+}
+
+// The address the TCP listener is on.  Only valid after Listen or once Serve has started.
+func (srv *Server) TCPAddr() net.Addr {
+	return srv.TCPListener.Addr()
+}
+
+// Stop listening.  Serve returns once the listeners are closed.  Connections that are already open are left alone.
+func (srv *Server) Close() error {
+	err := newMultiError(nil)
+	if srv.TCPListener != nil {
+		if cerr := srv.TCPListener.Close(); cerr != nil {
+			err.Add(cerr)
+		}
+	}
+	if srv.UDPListener != nil {
+		if cerr := srv.UDPListener.Close(); cerr != nil {
+			err.Add(cerr)
+		}
+	}
+	if len(err.errs) == 0 {
+		return nil
+	}
+	return err
+}
+
+// Start listening on the TCP and UDP ports associated with the Ethernet/IP protocol.
+// these are 44818 and 2222 respectively
+// as far as I can tell there is never an option to change this on any devices so it is hard coded here.
+//
+// If Listen was already called, the listeners it opened are served instead.
+func (srv *Server) Serve() error {
+	srv.ConnMgr.Init(srv.Logger)
+
+	if srv.TCPListener == nil || srv.UDPListener == nil {
+		err := srv.Listen("0.0.0.0:44818", "0.0.0.0:2222")
+		if err != nil {
+			return err
+		}
+	}
+	var err error
 
 	// we'll start two server goroutines and then wait for either of them to error out on the error channel.
 
-	errCh := make(chan error)
+	errCh := make(chan error, 2)
 
 	go func() {
 		err := srv.serveUDP()
@@ -117,6 +161,9 @@ func (srv *Server) serveTCP() error {
 	for {
 		conn, err := srv.TCPListener.Accept()
 		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
 			srv.Logger.Error("problem with tcp accept", "error", err)
 			continue
 		}
@@ -145,6 +192,9 @@ func (srv *Server) serveUDP() error {
 		b := make([]byte, 4096)
 		buf := bytes.NewBuffer(b)
 		n, addr, err := srv.UDPListener.ReadFrom(b)
+		if errors.Is(err, net.ErrClosed) {
+			return err
+		}
 		if n == 0 {
 			srv.Logger.Debug("Read 0 bytes on udp listener.")
 			continue
@@ -237,6 +287,16 @@ func (h *serverTCPHandler) serve(srv *Server) error {
 			if err != nil {
 				return fmt.Errorf("problem with sendListServices %w", err)
 			}
+		case cipCommand_NOP:
+			// keeps the session alive.  Whatever data it has is ignored and there is no reply.
+			_, err = io.CopyN(io.Discard, h.conn, int64(eipHdr.Length))
+			if err != nil {
+				return fmt.Errorf("problem reading nop data %w", err)
+			}
+		case cipCommandUnRegisterSession:
+			// the client is done.  There is no reply, we just close the socket.
+			srv.Logger.Info("session unregistered", "remote addr", h.conn.RemoteAddr().String())
+			return nil
 
 		}
 	}
@@ -374,12 +434,43 @@ func (h *serverTCPHandler) forwardClose(i CIPItem) error {
 		return fmt.Errorf("couldn't close open connection with ID %v. %v", fwd_close.ConnectionSerialNumber, err)
 	}
 
-	path := make([]byte, fwd_close.PathSize*2)
+	var reserved byte
+	err = i.DeSerialize(&reserved)
+	if err != nil {
+		return fmt.Errorf("problem parsing forward close reserved byte %w", err)
+	}
+	path := make([]byte, fwd_close.ConnPathSize*2)
 	err = i.DeSerialize(&path)
 	if err != nil {
 		return fmt.Errorf("problem parsing forward close path %w", err)
 	}
-	return nil
+
+	items := make([]CIPItem, 2)
+	items[0] = CIPItem{Header: cipItemHeader{ID: cipItem_Null}}
+	items[1] = newItem(cipItem_UnconnectedData, nil)
+	items[1].Serialize(msgEIPForwardCloseReply{
+		Service:                fwd_close.Service.AsResponse(),
+		ConnectionSerialNumber: fwd_close.ConnectionSerialNumber,
+		VendorID:               fwd_close.VendorID,
+		OriginatorSerialNumber: fwd_close.OriginatorSerialNumber,
+	})
+	itemData, err := serializeItems(items)
+	if err != nil {
+		return fmt.Errorf("could not serialize items: %w", err)
+	}
+	return h.send(cipCommandSendRRData, itemData)
+}
+
+type msgEIPForwardCloseReply struct {
+	Service                CIPService
+	Reserved               byte
+	Status                 CIPStatus
+	StatusLen              byte
+	ConnectionSerialNumber uint16
+	VendorID               uint16
+	OriginatorSerialNumber uint32
+	ReplySize              byte
+	Reserved2              byte
 }
 
 // in this message T is for target and O is for originator so
@@ -166,7 +166,10 @@ func (h *serverTCPHandler) connectedMulti(items []CIPItem) error {
 
 			result, err := p.TagRead(tagname, int16(qty))
 			if err != nil {
-				return fmt.Errorf("problem getting data for %s from provider. %w", tagname, err)
+				// only this read failed.  the rest of the request still gets answered.
+				h.server.Logger.Debug("problem getting data from provider", "tag", tagname, "error", err)
+				results[i] = []byte{byte(svc.AsResponse()), 0x00, byte(CIPStatus_PathDestinationUnknown), 0x00}
+				continue
 			}
 
 			// build this portion of the response msg
@@ -322,8 +325,9 @@ func (h *serverTCPHandler) connectedRead(items []CIPItem) error {
 	p := provider
 	result, err := p.TagRead(tag, int16(qty))
 	if err != nil {
-		err2 := h.sendConnectedError(CIPService_FragRead, seq, connection.OT, CIPStatus_InvalidMemberID, 0)
-		return fmt.Errorf("problem getting data from provider. %w / %v", err, err2)
+		// a tag that isn't there is the client's problem, not the connection's.  tell them and keep serving.
+		h.server.Logger.Debug("problem getting data from provider", "tag", tag, "error", err)
+		return h.sendConnectedError(CIPService_FragRead, seq, connection.OT, CIPStatus_PathDestinationUnknown, 0)
 	}
 	typ, _ := GoVarToCIPType(result)
 
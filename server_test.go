@@ -0,0 +1,76 @@
+package gologix
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+)
+
+// start a server on a free local port with a map tag provider at 1,0 and connect a client to it.
+func newTestServer(t *testing.T, data map[string]any) (*Server, *Client) {
+	t.Helper()
+	router := NewRouter()
+	router.Handle([]byte{1, 0}, &MapTagProvider{Data: data})
+	srv := NewServer(router)
+	srv.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv.ConnMgr.Init(srv.Logger)
+
+	err := srv.Listen("127.0.0.1:0", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("problem listening: %v", err)
+	}
+	go srv.Serve()
+	t.Cleanup(func() { srv.Close() })
+
+	client := NewClient("127.0.0.1")
+	client.Controller.Port = uint(srv.TCPAddr().(*net.TCPAddr).Port)
+	client.ConnectionKeepAlive = false
+	err = client.Connect()
+	if err != nil {
+		t.Fatalf("problem connecting to test server: %v", err)
+	}
+	return srv, client
+}
+
+func TestServerEndToEnd(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{"setpoint": float32(1.5)})
+
+	var sp float32
+	err := client.Read("Setpoint", &sp)
+	if err != nil {
+		t.Fatalf("problem reading setpoint: %v", err)
+	}
+	if sp != 1.5 {
+		t.Errorf("wanted 1.5 got %v", sp)
+	}
+
+	err = client.Write("Count", int32(12345))
+	if err != nil {
+		t.Fatalf("problem writing count: %v", err)
+	}
+	var count int32
+	err = client.Read("Count", &count)
+	if err != nil {
+		t.Fatalf("problem reading count back: %v", err)
+	}
+	if count != 12345 {
+		t.Errorf("wanted 12345 got %v", count)
+	}
+
+	// a missing tag is an error for the read but the connection stays up.
+	var missing int32
+	err = client.Read("NotThere", &missing)
+	if !IsTagNotFound(err) {
+		t.Errorf("wanted tag not found. got %v", err)
+	}
+	err = client.Read("Count", &count)
+	if err != nil {
+		t.Errorf("problem reading after a missing tag: %v", err)
+	}
+
+	err = client.Disconnect()
+	if err != nil {
+		t.Errorf("problem disconnecting: %v", err)
+	}
+}
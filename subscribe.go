@@ -0,0 +1,113 @@
+package gologix
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// TagUpdate is a change in a subscribed tag's value, or a failed read of it if Err is set.
+type TagUpdate struct {
+	Name      string
+	Value     any // the same go type ReadMultiple gives.  nil when Err is set.
+	Timestamp time.Time
+	Err       error
+}
+
+// SubscribeOptions changes how SubscribeWithOptions reports changes.
+type SubscribeOptions struct {
+	// send an update for every tag after the first read instead of waiting for it to change.
+	EmitFirst bool
+
+	// how many updates the channel holds before the polling waits for you to take them.  0 holds one per tag.
+	Buffer int
+}
+
+// Poll tags every interval and get an update on the channel whenever one of their values changes.  The first read of
+// every tag is sent too.  Call the returned func to stop polling.  It waits for a read in progress to finish and the
+// channel is closed once it returns.
+//
+// interval has to be more than 0.  If it isn't nothing is polled and every tag gets one update with Err set on a
+// channel that is already closed.
+//
+// The tags are read together with ReadMultiple so they go in as few requests as will fit.  A tag that fails to read
+// gets an update with Err set every poll it fails and the polling carries on.  Once it reads again its value is sent
+// even if it didn't change.
+func (client *Client) Subscribe(tags []string, interval time.Duration) (<-chan TagUpdate, func()) {
+	return client.SubscribeWithOptions(tags, interval, SubscribeOptions{EmitFirst: true})
+}
+
+// Subscribe with control over whether the first reads are sent and how big the channel is.  See Subscribe.
+func (client *Client) SubscribeWithOptions(tags []string, interval time.Duration, opts SubscribeOptions) (<-chan TagUpdate, func()) {
+	size := opts.Buffer
+	if size <= 0 || interval <= 0 {
+		size = len(tags)
+	}
+	updates := make(chan TagUpdate, size)
+	if interval <= 0 {
+		err := fmt.Errorf("subscribe interval has to be more than 0. got %v", interval)
+		now := time.Now()
+		for _, tag := range tags {
+			updates <- TagUpdate{Name: tag, Timestamp: now, Err: err}
+		}
+		close(updates)
+		return updates, func() {}
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	tags = append([]string(nil), tags...)
+
+	go func() {
+		defer close(done)
+		defer close(updates)
+		last := make([]any, len(tags))
+		known := make([]bool, len(tags)) // whether last has a good read in it
+		first := true
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			values, errs := client.ReadMultiple(tags)
+			now := time.Now()
+			for i, tag := range tags {
+				u := TagUpdate{Name: tag, Timestamp: now}
+				switch {
+				case errs[i] != nil:
+					known[i] = false
+					u.Err = errs[i]
+				case !known[i]:
+					known[i] = true
+					last[i] = values[i]
+					if first && !opts.EmitFirst {
+						continue
+					}
+					u.Value = values[i]
+				case !reflect.DeepEqual(values[i], last[i]):
+					last[i] = values[i]
+					u.Value = values[i]
+				default:
+					continue
+				}
+				select {
+				case updates <- u:
+				case <-stop:
+					return
+				}
+			}
+			first = false
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() { close(stop) })
+		<-done
+	}
+	return updates, cancel
+}
@@ -0,0 +1,86 @@
+package gologix
+
+import (
+	"testing"
+	"time"
+)
+
+// get the next update or fail after a while.
+func nextUpdate(t *testing.T, updates <-chan TagUpdate) TagUpdate {
+	t.Helper()
+	select {
+	case u, ok := <-updates:
+		if !ok {
+			t.Fatalf("updates closed early")
+		}
+		return u
+	case <-time.After(time.Second * 5):
+		t.Fatalf("timed out waiting for an update")
+	}
+	return TagUpdate{}
+}
+
+func TestSubscribe(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{"count": int32(1), "speed": float32(2.5)})
+
+	updates, cancel := client.Subscribe([]string{"Count", "Speed"}, time.Millisecond*5)
+	first := map[string]any{}
+	for i := 0; i < 2; i++ {
+		u := nextUpdate(t, updates)
+		if u.Err != nil {
+			t.Fatalf("problem reading %s: %v", u.Name, u.Err)
+		}
+		first[u.Name] = u.Value
+	}
+	if first["Count"] != int32(1) || first["Speed"] != float32(2.5) {
+		t.Errorf("wanted the first reads of both tags. got %v", first)
+	}
+
+	err := client.Write("Count", int32(7))
+	if err != nil {
+		t.Fatalf("problem writing: %v", err)
+	}
+	// speed doesn't change so the next update has to be count.
+	u := nextUpdate(t, updates)
+	if u.Name != "Count" || u.Value != int32(7) || u.Timestamp.IsZero() {
+		t.Errorf("wanted count changing to 7. got %+v", u)
+	}
+
+	cancel()
+	cancel() // more than once is fine
+	for range updates {
+	}
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{"count": int32(1)})
+
+	updates, cancel := client.SubscribeWithOptions([]string{"Count", "NotThere"}, time.Millisecond*5, SubscribeOptions{})
+	defer cancel()
+	// count never changes and its first read isn't sent so only the missing tag shows up.  It keeps showing up.
+	for i := 0; i < 3; i++ {
+		u := nextUpdate(t, updates)
+		if u.Name != "NotThere" || !IsTagNotFound(u.Err) {
+			t.Errorf("wanted tag not found for NotThere. got %+v", u)
+		}
+	}
+}
+
+// a bad interval can't be polled so every tag gets an error and the channel is closed.
+func TestSubscribeBadInterval(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	for _, interval := range []time.Duration{0, -time.Second} {
+		updates, cancel := client.Subscribe([]string{"Count", "Speed"}, interval)
+		n := 0
+		for u := range updates {
+			if u.Err == nil {
+				t.Errorf("an interval of %v should be an error. got %+v", interval, u)
+			}
+			n++
+		}
+		if n != 2 {
+			t.Errorf("wanted an error for both tags. got %d updates", n)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,188 @@
+package gologix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// longest name the controller allows for a tag or member.
+const maxTagNameLength = 40
+
+// Tag is a parsed symbolic tag path such as "Program:Main.MyArray[3].Member.5"
+//
+// Use ParseTag to create one.  The zero value is not a valid tag.
+type Tag struct {
+	Segments  []TagSegment // the dotted parts of the path in order.  The program, if any, is the first segment.
+	BitAccess bool         // true if the path ends in a bit number
+	Bit       int          // the bit number if BitAccess is set
+}
+
+// TagSegment is one dotted part of a tag path: a tag or member name with optional array indices.
+type TagSegment struct {
+	Name    string
+	Indices []int // one entry per array dimension.  nil if the segment isn't indexed.
+}
+
+// Parse a symbolic tag path.
+//
+// Names must start with a letter or underscore and contain only letters, digits, and underscores.  A "Program:Name."
+// prefix is allowed on the first segment.  Array indices can have up to 3 dimensions separated by commas.  A trailing
+// all digit part is a bit number.  Whitespace anywhere in the path is an error.
+func ParseTag(s string) (Tag, error) {
+	var t Tag
+	if s == "" {
+		return t, errors.New("empty tag path")
+	}
+	if strings.ContainsAny(s, " \t\r\n") {
+		return t, fmt.Errorf("tag path %q contains whitespace", s)
+	}
+
+	parts := strings.Split(s, ".")
+	if strings.HasPrefix(strings.ToLower(parts[0]), "program:") {
+		if len(parts) < 2 {
+			return t, fmt.Errorf("tag path %q has a program but no tag", s)
+		}
+		err := validateTagName(parts[0][len("program:"):])
+		if err != nil {
+			return t, fmt.Errorf("bad program name in %q: %w", s, err)
+		}
+		t.Segments = append(t.Segments, TagSegment{Name: parts[0]})
+		parts = parts[1:]
+		if isDigits(parts[0]) {
+			return t, fmt.Errorf("tag path %q has a bit number but no tag", s)
+		}
+	}
+
+	for i, part := range parts {
+		if len(t.Segments) > 0 && i == len(parts)-1 && isDigits(part) {
+			bit, err := strconv.Atoi(part)
+			if err != nil || bit > 63 {
+				return t, fmt.Errorf("bit %s out of range in %q", part, s)
+			}
+			t.BitAccess = true
+			t.Bit = bit
+			break
+		}
+		seg, err := parseTagSegment(part)
+		if err != nil {
+			return t, fmt.Errorf("problem with %q in %q: %w", part, s, err)
+		}
+		t.Segments = append(t.Segments, seg)
+	}
+	return t, nil
+}
+
+func parseTagSegment(part string) (TagSegment, error) {
+	var seg TagSegment
+	name := part
+	if open := strings.IndexByte(part, '['); open >= 0 {
+		if !strings.HasSuffix(part, "]") {
+			return seg, errors.New("unterminated array index")
+		}
+		name = part[:open]
+		index_text := part[open+1 : len(part)-1]
+		indexes := strings.Split(index_text, ",")
+		if len(indexes) > 3 {
+			return seg, fmt.Errorf("%d array dimensions. max is 3", len(indexes))
+		}
+		seg.Indices = make([]int, len(indexes))
+		for i, index := range indexes {
+			if !isDigits(index) {
+				return seg, fmt.Errorf("bad array index %q", index)
+			}
+			v, err := strconv.ParseUint(index, 10, 32)
+			if err != nil {
+				return seg, fmt.Errorf("array index %s out of range: %w", index, err)
+			}
+			seg.Indices[i] = int(v)
+		}
+	} else if strings.ContainsAny(part, "]") {
+		return seg, errors.New("unexpected ]")
+	}
+
+	err := validateTagName(name)
+	if err != nil {
+		return seg, err
+	}
+	seg.Name = name
+	return seg, nil
+}
+
+func validateTagName(name string) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+	if len(name) > maxTagNameLength {
+		return fmt.Errorf("name is %d characters. max is %d", len(name), maxTagNameLength)
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return fmt.Errorf("invalid character %q in %s", c, name)
+		}
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// The tag path back in its string form.
+func (t Tag) String() string {
+	var b strings.Builder
+	for i, seg := range t.Segments {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(seg.Name)
+		if seg.Indices != nil {
+			b.WriteByte('[')
+			for j, index := range seg.Indices {
+				if j > 0 {
+					b.WriteByte(',')
+				}
+				b.WriteString(strconv.Itoa(index))
+			}
+			b.WriteByte(']')
+		}
+	}
+	if t.BitAccess {
+		fmt.Fprintf(&b, ".%d", t.Bit)
+	}
+	return b.String()
+}
+
+// Build the IOI (request path) for the tag.  Each segment is a symbolic segment followed by an element segment for
+// each array index.  The bit number isn't part of the path.  Reads of a bit have to read the whole word and pick the
+// bit out of it.
+func (t Tag) IOI() ([]byte, error) {
+	if len(t.Segments) == 0 {
+		return nil, errors.New("tag has no segments")
+	}
+	b := bytes.Buffer{}
+	for _, seg := range t.Segments {
+		part, err := marshalIOIPart(seg.Name)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(part)
+		for _, index := range seg.Indices {
+			b.Write(CIPElement(index).Bytes())
+		}
+	}
+	return b.Bytes(), nil
+}
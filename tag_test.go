@@ -0,0 +1,120 @@
+package gologix
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	var tests = []struct {
+		path     string
+		segments []TagSegment
+		bit      int // -1 for no bit access
+	}{
+		{"MyDint", []TagSegment{{Name: "MyDint"}}, -1},
+		{"MyDint.5", []TagSegment{{Name: "MyDint"}}, 5},
+		{"MyArray[3]", []TagSegment{{Name: "MyArray", Indices: []int{3}}}, -1},
+		{"My3D[2,3,4]", []TagSegment{{Name: "My3D", Indices: []int{2, 3, 4}}}, -1},
+		{
+			"Program:Main.MyArray[3].Member.5",
+			[]TagSegment{{Name: "Program:Main"}, {Name: "MyArray", Indices: []int{3}}, {Name: "Member"}},
+			5,
+		},
+		{"_under.score_2[70000]", []TagSegment{{Name: "_under"}, {Name: "score_2", Indices: []int{70000}}}, -1},
+		{"Big[4294967295]", []TagSegment{{Name: "Big", Indices: []int{4294967295}}}, -1},
+	}
+
+	for _, tt := range tests {
+		tag, err := ParseTag(tt.path)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.path, err)
+			continue
+		}
+		if len(tag.Segments) != len(tt.segments) {
+			t.Errorf("%s: wanted %d segments got %+v", tt.path, len(tt.segments), tag.Segments)
+			continue
+		}
+		for i := range tt.segments {
+			if tag.Segments[i].Name != tt.segments[i].Name || !slices.Equal(tag.Segments[i].Indices, tt.segments[i].Indices) {
+				t.Errorf("%s: segment %d wanted %+v got %+v", tt.path, i, tt.segments[i], tag.Segments[i])
+			}
+		}
+		if tt.bit < 0 && tag.BitAccess {
+			t.Errorf("%s: shouldn't be a bit access", tt.path)
+		}
+		if tt.bit >= 0 && (!tag.BitAccess || tag.Bit != tt.bit) {
+			t.Errorf("%s: wanted bit %d got %v/%d", tt.path, tt.bit, tag.BitAccess, tag.Bit)
+		}
+		if tag.String() != tt.path {
+			t.Errorf("%s: String() gave back %s", tt.path, tag.String())
+		}
+	}
+}
+
+func TestParseTagErrors(t *testing.T) {
+	var tests = []string{
+		"",
+		"My Dint",
+		"MyDint ",
+		"MyArray[ 3]",
+		"MyArray[-1]",
+		"MyArray[4294967296]",
+		"MyArray[1,2,3,4]",
+		"MyArray[]",
+		"MyArray[1",
+		"MyArray]",
+		"MyArray[a]",
+		"1Tag",
+		"Tag..Member",
+		"Tag.",
+		"Program:Main",
+		"Program:Main.5",
+		"Program:Main[1].Tag",
+		"Program:.Tag",
+		"Program:Ma in.Tag",
+		"MyDint.64",
+		"5",
+		"Tag-Name",
+		"ThisNameIsWayTooLongToBeAValidLogixTagName1",
+	}
+	for _, path := range tests {
+		tag, err := ParseTag(path)
+		if err == nil {
+			t.Errorf("%q: expected an error. got %+v", path, tag)
+		}
+	}
+}
+
+// the IOI from a parsed Tag should be the same thing newIOI builds
+func TestTagIOI(t *testing.T) {
+	client := Client{}
+	var tests = []string{
+		"profile[0,1,257]",
+		"profile[300,2,70000]",
+		"dwell3.acc",
+		"struct3.today.rate",
+		"my2dstruct4[1].today.hourlycount[3]",
+		"program:main.mydint",
+		"mydint.5",
+	}
+	for _, path := range tests {
+		tag, err := ParseTag(path)
+		if err != nil {
+			t.Errorf("%s: problem parsing %v", path, err)
+			continue
+		}
+		have, err := tag.IOI()
+		if err != nil {
+			t.Errorf("%s: problem building ioi %v", path, err)
+			continue
+		}
+		want, err := client.newIOI(path, CIPTypeDINT)
+		if err != nil {
+			t.Errorf("%s: problem building reference ioi %v", path, err)
+			continue
+		}
+		if !check_bytes(have, want.Buffer) {
+			t.Errorf("%s: \nwanted %v\ngot    %v", path, to_hex(want.Buffer), to_hex(have))
+		}
+	}
+}
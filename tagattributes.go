@@ -0,0 +1,124 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// TagAttributes is what the symbol object of a tag says about it.
+type TagAttributes struct {
+	Name string
+	Info TagInfo // the type word and array dimensions in the same form ListAllTags gives them
+
+	// the size in bytes of one element of the tag.  For a UDT this is the size of the whole structure.
+	ElementSize uint16
+
+	// the size of each array dimension, first dimension first.  Empty for a tag that isn't an array.
+	Dimensions []int
+}
+
+// Ask the controller for the name, type, element size, and array dimensions of one tag without listing all of them.
+//
+// This is a Get_Attribute_List of attributes 1, 2, 7, and 8 of the tag's symbol object.  If the tag is in KnownTags it
+// is addressed by its instance, otherwise by name.  Program scoped tags are named like "Program:MainProgram.MyTag".
+// The tag has to be a base tag.  Members and array elements don't have a symbol object of their own.
+func (client *Client) GetTagAttributes(tag string) (TagAttributes, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return TagAttributes{}, fmt.Errorf("could not get attributes of %s: %w", tag, err)
+	}
+	var path []byte
+	if known, ok := client.KnownTags[strings.ToLower(tag)]; ok {
+		path = known.Bytes()
+	} else {
+		ioi, err := client.newIOI(tag, CIPTypeUnknown)
+		if err != nil {
+			return TagAttributes{}, fmt.Errorf("could not build path for %s: %w", tag, err)
+		}
+		path = ioi.Buffer
+	}
+
+	msg := make([]byte, 0, 10)
+	for _, attr := range []uint16{4, 1, 2, 7, 8} { // the count then the attributes
+		msg = binary.LittleEndian.AppendUint16(msg, attr)
+	}
+	item, err := client.GenericCIPMessage(CIPService_GetAttributeList, path, msg)
+	if err != nil {
+		return TagAttributes{}, fmt.Errorf("problem getting attributes of %s: %w", tag, err)
+	}
+	attrs, err := parseTagAttributes(item)
+	if err != nil {
+		return attrs, fmt.Errorf("problem parsing attributes of %s: %w", tag, err)
+	}
+	return attrs, nil
+}
+
+// parse a Get_Attribute_List reply for the symbol object attributes.  item has to be at the attribute count.
+func parseTagAttributes(item *CIPItem) (TagAttributes, error) {
+	var attrs TagAttributes
+	count, err := item.Uint16()
+	if err != nil {
+		return attrs, fmt.Errorf("problem reading attribute count. %w", err)
+	}
+	var haveType bool
+	var dims [3]uint32
+	for i := 0; i < int(count); i++ {
+		var hdr struct {
+			ID     uint16
+			Status uint16
+		}
+		err = item.DeSerialize(&hdr)
+		if err != nil {
+			return attrs, fmt.Errorf("problem reading attribute header %d. %w", i, err)
+		}
+		if hdr.Status != uint16(CIPStatus_OK) {
+			// there is no value after a failed attribute.  The name and type are a must but the rest are optional.
+			if hdr.ID == 1 || hdr.ID == 2 {
+				return attrs, fmt.Errorf("attribute %d: %w", hdr.ID, &CIPError{General: byte(hdr.Status)})
+			}
+			continue
+		}
+		switch hdr.ID {
+		case 1:
+			size, err := item.Uint16()
+			if err != nil {
+				return attrs, fmt.Errorf("problem reading name length. %w", err)
+			}
+			name := make([]byte, size)
+			err = item.DeSerialize(name)
+			if err != nil {
+				return attrs, fmt.Errorf("problem reading name. %w", err)
+			}
+			attrs.Name = string(name)
+		case 2:
+			word, err := item.Uint16()
+			if err != nil {
+				return attrs, fmt.Errorf("problem reading type. %w", err)
+			}
+			attrs.Info.Type = CIPType(word & 0xFF)
+			attrs.Info.TypeInfo = byte(word >> 8)
+			haveType = true
+		case 7:
+			attrs.ElementSize, err = item.Uint16()
+			if err != nil {
+				return attrs, fmt.Errorf("problem reading element size. %w", err)
+			}
+		case 8:
+			err = item.DeSerialize(&dims)
+			if err != nil {
+				return attrs, fmt.Errorf("problem reading dimensions. %w", err)
+			}
+		default:
+			return attrs, fmt.Errorf("unexpected attribute %d", hdr.ID)
+		}
+	}
+	if !haveType {
+		return attrs, fmt.Errorf("no type in reply")
+	}
+	attrs.Info.Dimension1 = dims[0]
+	attrs.Info.Dimension2 = dims[1]
+	attrs.Info.Dimension3 = dims[2]
+	attrs.Dimensions = attrs.Info.arrayOrder()
+	return attrs, nil
+}
@@ -0,0 +1,112 @@
+package gologix
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// a Get_Attribute_List reply to a GetTagAttributes request.
+func tagAttributesReply(t *testing.T, name string, typ uint16, size uint16, dims [3]uint32) []byte {
+	item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+	item.Serialize(msgCIPReadResultData{Service: CIPService_GetAttributeList.AsResponse()})
+	// msgCIPReadResultData has a type and a pad byte after the status that aren't in this reply.
+	item.Data = item.Data[:len(item.Data)-2]
+	item.Serialize(uint16(4))
+	item.Serialize([2]uint16{1, 0})
+	item.Serialize(uint16(len(name)))
+	item.Serialize([]byte(name))
+	item.Serialize([2]uint16{2, 0})
+	item.Serialize(typ)
+	item.Serialize([2]uint16{7, 0})
+	item.Serialize(size)
+	item.Serialize([2]uint16{8, 0})
+	item.Serialize(dims)
+	b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+	if err != nil {
+		t.Fatalf("problem building reply: %v", err)
+	}
+	return *b
+}
+
+func TestGetTagAttributes(t *testing.T) {
+	var tests = []struct {
+		name string
+		typ  uint16
+		size uint16
+		dims [3]uint32
+		want TagAttributes
+	}{
+		{"Scalar", 0x00C4, 4, [3]uint32{0, 0, 0},
+			TagAttributes{Name: "Scalar", Info: TagInfo{Type: CIPTypeDINT}, ElementSize: 4, Dimensions: []int{}}},
+		{"OneDim", 0x20C4, 4, [3]uint32{10, 0, 0},
+			TagAttributes{Name: "OneDim", Info: TagInfo{Type: CIPTypeDINT, TypeInfo: 0x20, Dimension1: 10}, ElementSize: 4, Dimensions: []int{10}}},
+		{"TwoDim", 0x40CA, 4, [3]uint32{3, 5, 0},
+			TagAttributes{Name: "TwoDim", Info: TagInfo{Type: CIPTypeREAL, TypeInfo: 0x40, Dimension1: 3, Dimension2: 5}, ElementSize: 4, Dimensions: []int{3, 5}}},
+		// the dims past the ones the type says are used are ignored.
+		{"ThreeDim", 0xE123, 20, [3]uint32{2, 3, 4},
+			TagAttributes{Name: "ThreeDim", Info: TagInfo{Type: 0x23, TypeInfo: 0xE1, Dimension1: 2, Dimension2: 3, Dimension3: 4}, ElementSize: 20, Dimensions: []int{2, 3, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, remote := newPipeClient(t)
+			go func() {
+				hdr, buf, err := recvData(remote)
+				if err != nil {
+					t.Errorf("problem reading request: %v", err)
+					return
+				}
+				req := buf.Bytes()[20:]
+				if CIPService(req[2]) != CIPService_GetAttributeList {
+					t.Errorf("wanted a get attribute list. got service %v", CIPService(req[2]))
+				}
+				path := req[4 : 4+int(req[3])*2]
+				if !bytes.Contains(path, []byte(strings.ToLower(tt.name))) {
+					t.Errorf("wanted %s in the path. got % X", tt.name, path)
+				}
+				attrs := req[4+int(req[3])*2:]
+				want := []byte{4, 0, 1, 0, 2, 0, 7, 0, 8, 0}
+				if !bytes.Equal(attrs, want) {
+					t.Errorf("wanted attributes % X. got % X", want, attrs)
+				}
+				writeTestResponse(t, remote, hdr.Context, tagAttributesReply(t, tt.name, tt.typ, tt.size, tt.dims))
+			}()
+
+			have, err := client.GetTagAttributes(tt.name)
+			if err != nil {
+				t.Fatalf("problem getting attributes: %v", err)
+			}
+			if !reflect.DeepEqual(have, tt.want) {
+				t.Errorf("wanted %+v got %+v", tt.want, have)
+			}
+		})
+	}
+}
+
+func TestGetTagAttributesKnownTag(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.KnownTags = map[string]KnownTag{"known": {Name: "Known", Instance: 0x1234}}
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		req := buf.Bytes()[20:]
+		path := req[4 : 4+int(req[3])*2]
+		want := []byte{0x20, 0x6B, 0x25, 0x00, 0x34, 0x12}
+		if !bytes.Equal(path, want) {
+			t.Errorf("wanted path % X. got % X", want, path)
+		}
+		writeTestResponse(t, remote, hdr.Context, tagAttributesReply(t, "Known", 0x00C3, 2, [3]uint32{}))
+	}()
+
+	have, err := client.GetTagAttributes("Known")
+	if err != nil {
+		t.Fatalf("problem getting attributes: %v", err)
+	}
+	if have.Name != "Known" || have.Info.Type != CIPTypeINT || have.ElementSize != 2 {
+		t.Errorf("unexpected attributes %+v", have)
+	}
+}
@@ -0,0 +1,27 @@
+package gologix
+
+import (
+	"fmt"
+)
+
+// Check whether a tag exists on the controller and what type it is without needing a variable to read it into.
+//
+// A tag the controller doesn't know about is not an error.  You get false and CIPTypeUnknown for it.  Any other
+// problem, like a dropped connection or a privilege violation, is returned as the error.
+//
+// This reads one element of the tag and looks at the type in the reply so structures (including strings) all come
+// back as CIPTypeStruct.
+func (client *Client) TagExists(tag string) (bool, CIPType, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return false, CIPTypeUnknown, fmt.Errorf("could not check tag %s: %w", tag, err)
+	}
+	_, hdr, _, err := client.read_reply(tag, CIPTypeUnknown, 1, 0)
+	if err != nil {
+		if IsTagNotFound(err) {
+			return false, CIPTypeUnknown, nil
+		}
+		return false, CIPTypeUnknown, err
+	}
+	return true, hdr.Type, nil
+}
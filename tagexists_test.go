@@ -0,0 +1,28 @@
+package gologix
+
+import "testing"
+
+func TestTagExists(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{"setpoint": float32(1.5), "count": int32(3)})
+	defer client.Disconnect()
+
+	var tests = []struct {
+		tag    string
+		exists bool
+		typ    CIPType
+	}{
+		{"Setpoint", true, CIPTypeREAL},
+		{"Count", true, CIPTypeDINT},
+		{"NotThere", false, CIPTypeUnknown},
+	}
+	for _, tt := range tests {
+		exists, typ, err := client.TagExists(tt.tag)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.tag, err)
+			continue
+		}
+		if exists != tt.exists || typ != tt.typ {
+			t.Errorf("%s: wanted %v %v got %v %v", tt.tag, tt.exists, tt.typ, exists, typ)
+		}
+	}
+}
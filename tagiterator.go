@@ -0,0 +1,57 @@
+package gologix
+
+import "fmt"
+
+// TagIterator walks the controller scoped tags one at a time, fetching the next reply's worth from the controller only
+// when the ones it has are used up.  Get one with client.IterateTags.
+//
+// Unlike ListAllTags nothing is stored in client.KnownTags and UDT templates aren't looked up so the UDT field of the
+// tags is always nil.  Use client.ListMembers on Info.Template_ID() for the ones you care about.
+type TagIterator struct {
+	client *Client
+	next   uint32 // the instance to start the next request at
+	buf    []KnownTag
+	done   bool // the controller has no more tags after the ones in buf
+	err    error
+}
+
+// Start iterating over the controller scoped tags.  Nothing is sent until the first call to Next.
+func (client *Client) IterateTags() *TagIterator {
+	return &TagIterator{client: client, next: 1}
+}
+
+// Get the next tag.  The bool is false once there are no more tags or after an error.  An error stops the iteration
+// and is returned again by every later call.
+//
+// Program scoped tags and system tags are skipped the same as ListAllTags skips them.
+func (it *TagIterator) Next() (KnownTag, bool, error) {
+	for {
+		if it.err != nil {
+			return KnownTag{}, false, it.err
+		}
+		for len(it.buf) > 0 {
+			kt := it.buf[0]
+			it.buf = it.buf[1:]
+			if isControllerTag(kt.Name, kt.Info) {
+				return kt, true, nil
+			}
+		}
+		if it.done {
+			return KnownTag{}, false, nil
+		}
+		tags, more, err := it.client.listTagsPage(it.next)
+		if err != nil {
+			it.err = fmt.Errorf("problem iterating tags: %w", err)
+			continue
+		}
+		if len(tags) == 0 && more {
+			it.err = fmt.Errorf("partial transfer from instance %d had no tags", it.next)
+			continue
+		}
+		if len(tags) > 0 {
+			it.next = uint32(tags[len(tags)-1].Instance) + 1
+		}
+		it.buf = tags
+		it.done = !more
+	}
+}
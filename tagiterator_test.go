@@ -0,0 +1,107 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// build the connected data of one GetInstanceAttributeList reply of the symbol object.
+func symbolListReply(status CIPStatus, tags map[uint32]string, order []uint32, types map[uint32]CIPType) []byte {
+	return symbolListReplyAccess(status, tags, order, types, nil)
+}
+
+// symbolListReply with the external access of some of the tags.  The rest are read/write.
+func symbolListReplyAccess(status CIPStatus, tags map[uint32]string, order []uint32, types map[uint32]CIPType, access map[uint32]ExternalAccess) []byte {
+	reply := binary.LittleEndian.AppendUint16(nil, 0)
+	reply = append(reply, byte(CIPService_GetInstanceAttributeList.AsResponse()), 0, byte(status), 0)
+	for _, instance := range order {
+		name := tags[instance]
+		reply = binary.LittleEndian.AppendUint32(reply, instance)
+		reply = binary.LittleEndian.AppendUint16(reply, uint16(len(name)))
+		reply = append(reply, name...)
+		reply = append(reply, byte(types[instance]), 0)
+		reply = append(reply, make([]byte, 12)...) // no dimensions
+		reply = append(reply, byte(access[instance]))
+	}
+	return reply
+}
+
+func TestTagIterator(t *testing.T) {
+	client, remote := newPipeClient(t)
+
+	pages := [][]byte{
+		symbolListReply(CIPStatus_PartialTransfer,
+			map[uint32]string{1: "Alpha", 2: "__hidden", 3: "Program:Main"},
+			[]uint32{1, 2, 3},
+			map[uint32]CIPType{1: CIPTypeDINT, 2: CIPTypeDINT, 3: CIPTypeDINT}),
+		symbolListReply(CIPStatus_OK,
+			map[uint32]string{5: "Beta", 6: "X"},
+			[]uint32{5, 6},
+			map[uint32]CIPType{5: CIPTypeREAL, 6: CIPTypeBOOL}),
+	}
+	starts := make(chan uint32, len(pages))
+	go func() {
+		for _, page := range pages {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			// sequence, service, path size, then the class and instance segments
+			dat := buf.Bytes()[20:]
+			if CIPService(dat[2]) != CIPService_GetInstanceAttributeList {
+				t.Errorf("expected an instance attribute list request. got %v", CIPService(dat[2]))
+			}
+			path := dat[4 : 4+2*int(dat[3])]
+			switch path[2] {
+			case 0x24:
+				starts <- uint32(path[3])
+			case 0x25:
+				starts <- uint32(binary.LittleEndian.Uint16(path[4:]))
+			}
+
+			items := []CIPItem{
+				newItem(cipItem_ConnectionAddress, uint32(0)),
+				{Header: cipItemHeader{ID: cipItem_ConnectedData}},
+			}
+			items[1].Serialize(page)
+			b, err := serializeItems(items)
+			if err != nil {
+				t.Errorf("problem building reply: %v", err)
+				return
+			}
+			writeTestResponse(t, remote, hdr.Context, *b)
+		}
+	}()
+
+	it := client.IterateTags()
+	want := []string{"Alpha", "Beta", "X"}
+	for _, name := range want {
+		kt, ok, err := it.Next()
+		if err != nil || !ok {
+			t.Fatalf("wanted %s. got ok %v err %v", name, ok, err)
+		}
+		if kt.Name != name {
+			t.Errorf("wanted %s got %s", name, kt.Name)
+		}
+		if kt.UDT != nil {
+			t.Errorf("%s shouldn't have a UDT", name)
+		}
+	}
+	_, ok, err := it.Next()
+	if ok || err != nil {
+		t.Errorf("wanted the end of the tags. got ok %v err %v", ok, err)
+	}
+	if len(client.KnownTags) != 0 {
+		t.Errorf("iterating shouldn't fill in KnownTags. got %d", len(client.KnownTags))
+	}
+
+	close(starts)
+	have := []uint32{}
+	for s := range starts {
+		have = append(have, s)
+	}
+	if len(have) != 2 || have[0] != 1 || have[1] != 4 {
+		t.Errorf("wanted requests starting at instances 1 and 4. got %v", have)
+	}
+}
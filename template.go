@@ -0,0 +1,39 @@
+package gologix
+
+import "fmt"
+
+// Template is the layout of a structure in the controller as read from its template object (class 0x6C).
+//
+// Info holds the template attributes: SizeWords is the size of the definition, SizeBytes is the size of the structure
+// data when read, MemberCount is the number of members, and Handle is the structure handle the controller
+// sends in front of the data when the structure is read.  Each member has its name, CIP type, and byte offset.
+type Template = UDTDescriptor
+
+// Get the definition of the structure template with the given instance ID from the controller.
+// The result also goes into client.KnownTypes.
+func (client *Client) GetTemplate(instanceID uint16) (Template, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return Template{}, fmt.Errorf("could not start template read: %w", err)
+	}
+	t, err := client.ListMembers(uint32(instanceID))
+	if err != nil {
+		return Template{}, fmt.Errorf("problem getting template %d: %w", instanceID, err)
+	}
+	if client.KnownTypes == nil {
+		client.KnownTypes = make(map[string]UDTDescriptor)
+	}
+	client.KnownTypes[t.Name] = t
+	return t, nil
+}
+
+// Get the definition of the structure behind a tag.  The tag can be a member of another structure.
+//
+// The tag has to be in KnownTags so you need to have called ListAllTags (or ListSubTags for program tags) first.
+func (client *Client) GetStructDefinition(tag string) (Template, error) {
+	err := client.checkConnection()
+	if err != nil {
+		return Template{}, fmt.Errorf("could not start template read: %w", err)
+	}
+	return client.templateForTag(tag)
+}
@@ -0,0 +1,68 @@
+package gologix
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+)
+
+// the port controllers with CIP Security take EtherNet/IP over TLS on.
+const portTLSDefault = 2221
+
+// Create a client like NewClient that runs the EtherNet/IP session over TLS on port 2221.  config says how the
+// controller's certificate is checked.  See the TLSConfig field.
+func NewTLSClient(ip string, config *tls.Config) *Client {
+	client := NewClient(ip)
+	client.Controller.Port = portTLSDefault
+	client.TLSConfig = config
+	return client
+}
+
+// A TLS config that trusts the controller's certificate only if its SHA-256 fingerprint is one of fingerprints.  The
+// certificate chain and host name aren't checked, which suits controllers with self-signed certificates.
+//
+// The fingerprint is the hash of the whole DER encoded certificate, the same as openssl x509 -fingerprint -sha256
+// shows.
+func PinnedTLSConfig(fingerprints ...[sha256.Size]byte) *tls.Config {
+	pins := append([][sha256.Size]byte(nil), fingerprints...)
+	return &tls.Config{
+		// the default verification is replaced by the fingerprint check below, not skipped.
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("controller sent no certificate")
+			}
+			have := sha256.Sum256(cs.PeerCertificates[0].Raw)
+			for _, pin := range pins {
+				if bytes.Equal(have[:], pin[:]) {
+					return nil
+				}
+			}
+			return fmt.Errorf("controller certificate fingerprint %X isn't pinned", have)
+		},
+	}
+}
+
+// start TLS on a freshly dialed socket.  The handshake gets the same time limit as the dial.
+func (client *Client) tlsHandshake(ctx context.Context, conn net.Conn) (net.Conn, error) {
+	cfg := client.TLSConfig.Clone()
+	if cfg.ServerName == "" {
+		cfg.ServerName = client.Controller.IpAddress
+	}
+	tc := tls.Client(conn, cfg)
+	hctx, cancel := context.WithTimeout(ctx, client.connectTimeout())
+	defer cancel()
+	err := tc.HandshakeContext(hctx)
+	if err != nil {
+		conn.Close()
+		msg := "tls handshake with controller failed"
+		client.Logger.Error(msg, slog.Any("err", err))
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return tc, nil
+}
@@ -0,0 +1,104 @@
+package gologix
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+// a self-signed certificate for 127.0.0.1.
+func testCertificate(t *testing.T) (tls.Certificate, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("problem generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("problem creating certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("problem parsing certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, cert
+}
+
+// a test server that only takes TLS connections.
+func newTLSTestServer(t *testing.T, cert tls.Certificate, data map[string]any) *Server {
+	t.Helper()
+	router := NewRouter()
+	router.Handle([]byte{1, 0}, &MapTagProvider{Data: data})
+	srv := NewServer(router)
+	srv.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv.ConnMgr.Init(srv.Logger)
+	err := srv.Listen("127.0.0.1:0", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("problem listening: %v", err)
+	}
+	srv.TCPListener = tls.NewListener(srv.TCPListener, &tls.Config{Certificates: []tls.Certificate{cert}})
+	go srv.Serve()
+	t.Cleanup(func() { srv.Close() })
+	return srv
+}
+
+func TestTLSConnect(t *testing.T) {
+	cert, parsed := testCertificate(t)
+	srv := newTLSTestServer(t, cert, map[string]any{"count": int32(7)})
+	port := uint(srv.TCPAddr().(*net.TCPAddr).Port)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(parsed)
+	var tests = []struct {
+		name   string
+		config *tls.Config
+		fail   bool
+	}{
+		{"ca", &tls.Config{RootCAs: pool}, false},
+		{"untrusted", &tls.Config{}, true},
+		{"pinned", PinnedTLSConfig(sha256.Sum256(parsed.Raw)), false},
+		{"wrong pin", PinnedTLSConfig([sha256.Size]byte{1, 2, 3}), true},
+	}
+	for _, tt := range tests {
+		client := NewTLSClient("127.0.0.1", tt.config)
+		client.Controller.Port = port
+		client.ConnectionKeepAlive = false
+		err := client.Connect()
+		if tt.fail {
+			if err == nil {
+				client.Disconnect()
+				t.Errorf("%s: expected the connect to fail", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: problem connecting: %v", tt.name, err)
+			continue
+		}
+		var count int32
+		err = client.Read("Count", &count)
+		if err != nil || count != 7 {
+			t.Errorf("%s: wanted 7 got %d (%v)", tt.name, count, err)
+		}
+		client.Disconnect()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
 )
 
 type CIPType byte
@@ -19,12 +20,15 @@ type GoLogixTypes interface {
 
 // return the CIPType that corresponds to go type of variable T
 // also return the element count
+//
+// A byte is a USINT, not a BOOL or a BYTE, so a byte of flags is read and written as the 8 bit integer it is stored
+// as.  Only a go bool is a BOOL.
 func GoVarToCIPType(T any) (CIPType, int) {
 	switch x := T.(type) {
 	case bool:
 		return CIPTypeBOOL, 1
 	case byte:
-		return CIPTypeBYTE, 1
+		return CIPTypeUSINT, 1
 	case int8:
 		return CIPTypeSINT, 1
 	case uint16:
@@ -46,7 +50,7 @@ func GoVarToCIPType(T any) (CIPType, int) {
 	case string:
 		return CIPTypeSTRING, 1
 	case []byte:
-		return CIPTypeBYTE, len(x)
+		return CIPTypeUSINT, len(x)
 	case []uint16:
 		return CIPTypeUINT, len(x)
 	case []int16:
@@ -71,6 +75,14 @@ func GoVarToCIPType(T any) (CIPType, int) {
 	return CIPTypeUnknown, 1
 }
 
+// return the CIPType that corresponds to the go type T.
+// This is the same as GoVarToCIPType for a zero value of T.
+func GoTypeToCIPType[T GoLogixTypes]() CIPType {
+	var t T
+	ct, _ := GoVarToCIPType(t)
+	return ct
+}
+
 const (
 	CIPTypeUnknown         CIPType = 0x00
 	CIPTypeStruct          CIPType = 0xA0 // also used for strings.  Not sure what's up with CIPTypeSTRING
@@ -120,6 +132,8 @@ func (c CIPType) Size() int {
 		return 88
 	case CIPTypeUTIME:
 		return 8
+	case CIPTypeLTIME:
+		return 8
 	case CIPTypeBOOL:
 		return 1
 	case CIPTypeBYTE:
@@ -163,6 +177,36 @@ func (c CIPType) Size() int {
 	}
 }
 
+// return the size in bytes of one element of the type.  For structures (including STRING) the size comes from the
+// template's definition size when one is given since the controller pads every structure to its own size.  Without
+// a template STRING is the size of the builtin STRING and everything else falls back to Size().
+func (c CIPType) SizeWithTemplate(template *UDTDescriptor) int {
+	if (c == CIPTypeStruct || c == CIPTypeSTRING) && template != nil && template.Info.SizeBytes > 0 {
+		return int(template.Info.SizeBytes)
+	}
+	if c == CIPTypeSTRING {
+		// the builtin STRING is a DINT LEN and 82 SINTs of DATA padded out to 88 bytes.
+		return 88
+	}
+	return c.Size()
+}
+
+// return the natural alignment of the type in bytes.  Members of a structure start on a multiple of their type's
+// alignment.  Structures (and STRINGs) are aligned to 4 bytes, or 8 bytes if they have a 64 bit member which you
+// can get from UDTDescriptor.Align().
+func (c CIPType) Align() int {
+	switch c {
+	case CIPTypeBOOL, CIPTypeSINT, CIPTypeUSINT, CIPTypeBYTE:
+		return 1
+	case CIPTypeINT, CIPTypeUINT, CIPTypeWORD, CIPTypeDATE:
+		return 2
+	case CIPTypeLINT, CIPTypeULINT, CIPTypeLWORD, CIPTypeLREAL, CIPTypeUTIME, CIPTypeLTIME:
+		return 8
+	default:
+		return 4
+	}
+}
+
 // return a buffer that can hold the data structure
 func (c CIPType) NewBuffer() *[]byte {
 	buf := make([]byte, c.Size())
@@ -223,6 +267,11 @@ func (t CIPType) readValue(r io.Reader) (any, error) {
 // To do this it reads the needed number of bytes from r.
 // It returns the value as an any so the caller will have to do a cast to get it back
 func readValue(t CIPType, r io.Reader) (any, error) {
+	return readValueOrder(t, r, binary.LittleEndian)
+}
+
+// readValueOrder is readValue for data in the given byte order.
+func readValueOrder(t CIPType, r io.Reader, order binary.ByteOrder) (any, error) {
 
 	var value any
 	var err error
@@ -230,71 +279,77 @@ func readValue(t CIPType, r io.Reader) (any, error) {
 	case CIPTypeUnknown:
 		return nil, fmt.Errorf("unknown type")
 	case CIPTypeStruct:
-		return nil, fmt.Errorf("don't know what to do with a struct")
+		return nil, fmt.Errorf("can't read a structure as one value. use ReadStruct or ReadStructMap")
 	case CIPTypeBOOL:
-		var trueval bool
-		err = binary.Read(r, binary.LittleEndian, &trueval)
-		value = trueval
+		// a whole byte with any bit set being true.  A BYTE is the same size but its bits are left alone.
+		var b [1]byte
+		_, err = io.ReadFull(r, b[:])
+		value = b[0] != 0
 	case CIPTypeBYTE:
 		var trueval byte
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeSINT:
 		var trueval int8
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeINT:
 		var trueval int16
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeDINT:
 		var trueval int32
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
-	case CIPTypeLINT:
+	case CIPTypeLINT, CIPTypeUTIME, CIPTypeLTIME:
 		var trueval int64
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeUSINT:
 		var trueval uint8
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeUINT:
 		var trueval uint16
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeUDINT:
 		var trueval uint32
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
+		value = trueval
+	case CIPTypeULINT:
+		var trueval uint64
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeLWORD:
 		var trueval uint64
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeREAL:
 		var trueval float32
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeLREAL:
 		var trueval float64
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeWORD:
 		var trueval uint16
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeDWORD:
 		var trueval uint32
-		err = binary.Read(r, binary.LittleEndian, &trueval)
+		err = binary.Read(r, order, &trueval)
 		value = trueval
 	case CIPTypeSTRING:
-		var trueval [86]byte
-		err = binary.Read(r, binary.LittleEndian, &trueval)
-		value = trueval
+		// LEN, DATA, and the padding after DATA
+		var trueval [4 + stringDataSize + 2]byte
+		err = binary.Read(r, order, &trueval)
+		if err == nil {
+			value, err = parseLogixStringOrder(trueval[:4+stringDataSize], order)
+		}
 	default:
 		return nil, fmt.Errorf("default (unknown) type %d", t)
-		//panic(fmt.Sprintf("Default type %d", t))
-
 	}
 	if err != nil {
 		return nil, fmt.Errorf("problem reading %s as one unit of %T. %w", t, value, err)
@@ -302,6 +357,56 @@ func readValue(t CIPType, r io.Reader) (any, error) {
 	return value, nil
 }
 
+// The go type a read of this CIP type gives back, the other way from GoVarToCIPType.  Use it with reflect.New to
+// make somewhere to put a value whose type you only find out at run time.
+//
+// STRING is a go string.  Other structures are a []byte of their raw data since their layout depends on the UDT.
+// Types with no go equivalent give nil.
+func (c CIPType) GoType() reflect.Type {
+	switch c {
+	case CIPTypeBOOL:
+		return reflect.TypeOf(false)
+	case CIPTypeBYTE, CIPTypeUSINT:
+		return reflect.TypeOf(byte(0))
+	case CIPTypeSINT:
+		return reflect.TypeOf(int8(0))
+	case CIPTypeINT:
+		return reflect.TypeOf(int16(0))
+	case CIPTypeDINT:
+		return reflect.TypeOf(int32(0))
+	case CIPTypeLINT, CIPTypeUTIME, CIPTypeLTIME:
+		return reflect.TypeOf(int64(0))
+	case CIPTypeUINT, CIPTypeWORD:
+		return reflect.TypeOf(uint16(0))
+	case CIPTypeUDINT, CIPTypeDWORD:
+		return reflect.TypeOf(uint32(0))
+	case CIPTypeULINT, CIPTypeLWORD:
+		return reflect.TypeOf(uint64(0))
+	case CIPTypeREAL:
+		return reflect.TypeOf(float32(0))
+	case CIPTypeLREAL:
+		return reflect.TypeOf(float64(0))
+	case CIPTypeSTRING:
+		return reflect.TypeOf("")
+	case CIPTypeStruct:
+		return reflect.TypeOf([]byte(nil))
+	}
+	return nil
+}
+
+// the byte order of tag data for this client.
+func (client *Client) byteOrder() binary.ByteOrder {
+	if client.ByteOrder == nil {
+		return binary.LittleEndian
+	}
+	return client.ByteOrder
+}
+
+// whether order puts the most significant byte first.
+func isBigEndian(order binary.ByteOrder) bool {
+	return order.Uint16([]byte{0, 1}) == 1
+}
+
 // after reading a value v from the controller, you can get a bit from it with
 // getBit. bitpos must be between 0 and the length of the CIPType you read.
 func getBit(t CIPType, v any, bitpos int) (bool, error) {
@@ -310,10 +415,8 @@ func getBit(t CIPType, v any, bitpos int) (bool, error) {
 	switch t {
 	case CIPTypeUnknown:
 		return false, errors.New("unknown type")
-		//panic("Unknown type.")
 	case CIPTypeStruct:
 		return false, errors.New("got a struct - can't get a bit")
-		//panic("Struct!")
 	case CIPTypeBOOL:
 		if bitpos == 0 {
 			x, ok := v.(bool)
@@ -334,11 +437,9 @@ func getBit(t CIPType, v any, bitpos int) (bool, error) {
 		}
 	case CIPTypeSINT:
 		if bitpos >= 0 && bitpos < 8 {
-			x, ok := v.(byte)
+			x, ok := v.(int8)
 			if ok {
-				mask := byte(1 << bitpos)
-				masked := x & mask
-				return masked != 0, nil
+				return uint8(x)&(1<<bitpos) != 0, nil
 			}
 			err = fmt.Errorf("value was a SINT, but bit %d was requested. must be 0-7 for SINT", bitpos)
 		}
@@ -402,7 +503,7 @@ func getBit(t CIPType, v any, bitpos int) (bool, error) {
 			}
 			err = fmt.Errorf("value was a UDINT, but bit %d was requested. must be 0-31 for UDINT", bitpos)
 		}
-	case CIPTypeLWORD:
+	case CIPTypeULINT, CIPTypeLWORD:
 		if bitpos >= 0 && bitpos < 64 {
 			x, ok := v.(uint64)
 			if ok {
@@ -410,7 +511,7 @@ func getBit(t CIPType, v any, bitpos int) (bool, error) {
 				masked := x & mask
 				return masked != 0, nil
 			}
-			err = fmt.Errorf("value was a LWORD, but bit %d was requested. must be 0-63 for LWORD", bitpos)
+			err = fmt.Errorf("value was a %v, but bit %d was requested. must be 0-63 for %v", t, bitpos, t)
 		}
 	case CIPTypeREAL:
 		err = fmt.Errorf("value was a REAL, not finding bit of real")
@@ -440,11 +541,10 @@ func getBit(t CIPType, v any, bitpos int) (bool, error) {
 		err = fmt.Errorf("value was a STRING, not finding bit of string")
 	default:
 		return false, errors.New("got an unknown type. don't know how to get bit")
-		//panic("Default type.")
 
 	}
 	if err != nil {
 		return false, err
 	}
-	return false, err
+	return false, fmt.Errorf("bit %d out of range for %v", bitpos, t)
 }
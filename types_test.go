@@ -0,0 +1,311 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readValue used to panic on these.  They should be errors the caller can deal with.
+func TestReadValueBadTypes(t *testing.T) {
+	var tests = []struct {
+		name string
+		t    CIPType
+		dat  []byte
+	}{
+		{"struct header", CIPTypeStruct, []byte{0xCE, 0x0F, 3, 0, 0, 0, 'a', 'b', 'c'}},
+		{"unknown", CIPTypeUnknown, []byte{1, 2, 3, 4}},
+		{"unassigned type code", CIPType(0xB0), []byte{1, 2, 3, 4}},
+		{"short dint", CIPTypeDINT, []byte{1, 2}},
+		{"empty lreal", CIPTypeLREAL, []byte{}},
+	}
+
+	for _, tt := range tests {
+		_, err := readValue(tt.t, bytes.NewReader(tt.dat))
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+		_, err = tt.t.readValue(bytes.NewReader(tt.dat))
+		if err == nil {
+			t.Errorf("%s: expected an error from the method", tt.name)
+		}
+	}
+
+	// a struct says what to use instead.
+	_, err := readValue(CIPTypeStruct, bytes.NewReader([]byte{0xCE, 0x0F, 3, 0}))
+	if err == nil || !strings.Contains(err.Error(), "ReadStruct") {
+		t.Errorf("wanted an error pointing at ReadStruct. got %v", err)
+	}
+}
+
+func TestReadValueULINT(t *testing.T) {
+	v, err := readValue(CIPTypeULINT, bytes.NewReader([]byte{1, 0, 0, 0, 0, 0, 0, 0x80}))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if v != uint64(0x8000_0000_0000_0001) {
+		t.Errorf("wanted 0x8000000000000001 got %v", v)
+	}
+}
+
+func TestReadValueBigEndian(t *testing.T) {
+	v, err := readValueOrder(CIPTypeDINT, bytes.NewReader([]byte{0, 0, 1, 2}), binary.BigEndian)
+	if err != nil || v != int32(0x0102) {
+		t.Errorf("wanted 0x0102 got %v (%v)", v, err)
+	}
+	v, err = readValueOrder(CIPTypeREAL, bytes.NewReader([]byte{0x3F, 0xC0, 0, 0}), binary.BigEndian)
+	if err != nil || v != float32(1.5) {
+		t.Errorf("wanted 1.5 got %v (%v)", v, err)
+	}
+
+	// only LEN flips.  the characters stay in order.
+	dat := make([]byte, 4+stringDataSize+2)
+	binary.BigEndian.PutUint32(dat, 3)
+	copy(dat[4:], "abc")
+	v, err = readValueOrder(CIPTypeSTRING, bytes.NewReader(dat), binary.BigEndian)
+	if err != nil || v != "abc" {
+		t.Errorf("wanted abc got %q (%v)", v, err)
+	}
+}
+
+// every signed width has to keep its sign, from -1 out to both ends of its range.
+func TestReadValueSigned(t *testing.T) {
+	var tests = []struct {
+		t    CIPType
+		dat  []byte
+		want any
+	}{
+		{CIPTypeSINT, []byte{0xFF}, int8(-1)},
+		{CIPTypeSINT, []byte{0x80}, int8(math.MinInt8)},
+		{CIPTypeSINT, []byte{0x7F}, int8(math.MaxInt8)},
+		{CIPTypeINT, []byte{0xFF, 0xFF}, int16(-1)},
+		{CIPTypeINT, []byte{0x80, 0xFF}, int16(-128)},
+		{CIPTypeINT, []byte{0x00, 0x80}, int16(math.MinInt16)},
+		{CIPTypeINT, []byte{0xFF, 0x7F}, int16(math.MaxInt16)},
+		{CIPTypeDINT, []byte{0xFF, 0xFF, 0xFF, 0xFF}, int32(-1)},
+		{CIPTypeDINT, []byte{0x80, 0xFF, 0xFF, 0xFF}, int32(-128)},
+		{CIPTypeDINT, []byte{0x00, 0x00, 0x00, 0x80}, int32(math.MinInt32)},
+		{CIPTypeDINT, []byte{0xFF, 0xFF, 0xFF, 0x7F}, int32(math.MaxInt32)},
+		{CIPTypeLINT, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, int64(-1)},
+		{CIPTypeLINT, []byte{0x80, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, int64(-128)},
+		{CIPTypeLINT, []byte{0, 0, 0, 0, 0, 0, 0, 0x80}, int64(math.MinInt64)},
+		{CIPTypeLINT, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F}, int64(math.MaxInt64)},
+	}
+	for _, tt := range tests {
+		v, err := readValue(tt.t, bytes.NewReader(tt.dat))
+		if err != nil {
+			t.Errorf("%v % X: %v", tt.t, tt.dat, err)
+			continue
+		}
+		if v != tt.want {
+			t.Errorf("%v % X: wanted %v (%T) got %v (%T)", tt.t, tt.dat, tt.want, tt.want, v, v)
+		}
+
+		// a multi-service read of the same value goes through a different decoder.
+		reply := append([]byte{0xCC, 0, 0, 0, byte(tt.t), 0}, tt.dat...)
+		tag := tagDesc{TagName: "x", TagType: tt.t, Elements: 1}
+		v, err = parseMultiReadReply(tag, &tagIOI{}, reply, binary.LittleEndian)
+		if err != nil || v != tt.want {
+			t.Errorf("multi read of %v % X: wanted %v got %v (%v)", tt.t, tt.dat, tt.want, v, err)
+		}
+	}
+}
+
+// the sign bit is just another bit.
+func TestGetBitSigned(t *testing.T) {
+	var tests = []struct {
+		t   CIPType
+		v   any
+		bit int
+	}{
+		{CIPTypeSINT, int8(math.MinInt8), 7},
+		{CIPTypeINT, int16(math.MinInt16), 15},
+		{CIPTypeDINT, int32(math.MinInt32), 31},
+		{CIPTypeLINT, int64(math.MinInt64), 63},
+		{CIPTypeULINT, uint64(1 << 63), 63},
+	}
+	for _, tt := range tests {
+		v, err := getBit(tt.t, tt.v, tt.bit)
+		if err != nil || !v {
+			t.Errorf("bit %d of %v %v should be set. got %v (%v)", tt.bit, tt.t, tt.v, v, err)
+		}
+		v, err = getBit(tt.t, tt.v, 0)
+		if err != nil || v {
+			t.Errorf("bit 0 of %v %v should be clear. got %v (%v)", tt.t, tt.v, v, err)
+		}
+	}
+}
+
+func TestGetBitErrors(t *testing.T) {
+	_, err := getBit(CIPTypeDINT, int32(1), 32)
+	if err == nil {
+		t.Errorf("bit 32 of a DINT should be an error")
+	}
+	_, err = getBit(CIPTypeStruct, []byte{}, 0)
+	if err == nil {
+		t.Errorf("bit of a struct should be an error")
+	}
+	v, err := getBit(CIPTypeDINT, int32(-1<<31), 31)
+	if err != nil || !v {
+		t.Errorf("bit 31 should be set. got %v, %v", v, err)
+	}
+}
+
+func TestCheckFloatSpecial(t *testing.T) {
+	var tests = []struct {
+		name string
+		v    any
+		fail bool
+	}{
+		{"real", float32(1.5), false},
+		{"lreal", float64(-2), false},
+		{"dint", int32(5), false},
+		{"real nan", float32(math.NaN()), true},
+		{"lreal nan", math.NaN(), true},
+		{"real inf", float32(math.Inf(1)), true},
+		{"lreal -inf", math.Inf(-1), true},
+		{"array", []any{float32(1), float32(2)}, false},
+		{"array with nan", []any{float32(1), float32(math.NaN())}, true},
+	}
+
+	for _, tt := range tests {
+		err := checkFloatSpecial("MyReal", tt.v)
+		if tt.fail {
+			if !errors.Is(err, ErrFloatSpecial) {
+				t.Errorf("%s: expected ErrFloatSpecial. got %v", tt.name, err)
+				continue
+			}
+			if !strings.Contains(err.Error(), "MyReal") {
+				t.Errorf("%s: error should name the tag. got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
+
+func TestCIPTypeAlign(t *testing.T) {
+	var tests = []struct {
+		ct    CIPType
+		align int
+	}{
+		{CIPTypeBOOL, 1},
+		{CIPTypeSINT, 1},
+		{CIPTypeINT, 2},
+		{CIPTypeDINT, 4},
+		{CIPTypeREAL, 4},
+		{CIPTypeLINT, 8},
+		{CIPTypeLREAL, 8},
+		{CIPTypeStruct, 4},
+	}
+	for _, tt := range tests {
+		if have := tt.ct.Align(); have != tt.align {
+			t.Errorf("%v: wanted alignment %d got %d", tt.ct, tt.align, have)
+		}
+	}
+}
+
+func TestSizeWithTemplate(t *testing.T) {
+	template := &UDTDescriptor{Name: "MyUDT", Info: msgGetTemplateAttrListResponse{SizeBytes: 12}}
+	if have := CIPTypeStruct.SizeWithTemplate(template); have != 12 {
+		t.Errorf("struct size should come from the template. got %d", have)
+	}
+	if have := CIPTypeStruct.SizeWithTemplate(nil); have != 88 {
+		t.Errorf("struct without a template should fall back to Size(). got %d", have)
+	}
+	if have := CIPTypeSTRING.SizeWithTemplate(nil); have != 88 {
+		t.Errorf("STRING without a template should be the builtin STRING size. got %d", have)
+	}
+	if have := CIPTypeDINT.SizeWithTemplate(template); have != 4 {
+		t.Errorf("atomic types should ignore the template. got %d", have)
+	}
+}
+
+func TestGoType(t *testing.T) {
+	var tests = []struct {
+		ct   CIPType
+		want any
+	}{
+		{CIPTypeBOOL, false},
+		{CIPTypeSINT, int8(0)},
+		{CIPTypeUSINT, byte(0)},
+		{CIPTypeINT, int16(0)},
+		{CIPTypeDINT, int32(0)},
+		{CIPTypeLINT, int64(0)},
+		{CIPTypeUDINT, uint32(0)},
+		{CIPTypeLWORD, uint64(0)},
+		{CIPTypeREAL, float32(0)},
+		{CIPTypeLREAL, float64(0)},
+		{CIPTypeSTRING, ""},
+		{CIPTypeStruct, []byte(nil)},
+	}
+	for _, tt := range tests {
+		have := tt.ct.GoType()
+		if have != reflect.TypeOf(tt.want) {
+			t.Errorf("%v: wanted %T got %v", tt.ct, tt.want, have)
+		}
+	}
+	if CIPTypeUnknown.GoType() != nil {
+		t.Errorf("unknown shouldn't have a go type")
+	}
+
+	// going back the other way gets the same type for the ones that have only one go type.
+	for _, ct := range []CIPType{CIPTypeBOOL, CIPTypeSINT, CIPTypeINT, CIPTypeDINT, CIPTypeLINT, CIPTypeREAL, CIPTypeLREAL} {
+		back, _ := GoVarToCIPType(reflect.New(ct.GoType()).Elem().Interface())
+		if back != ct {
+			t.Errorf("%v: went to %v and back to %v", ct, ct.GoType(), back)
+		}
+	}
+}
+
+// a byte is an 8 bit integer and only a bool is a BOOL.
+func TestBoolAndByte(t *testing.T) {
+	if ct, _ := GoVarToCIPType(byte(1)); ct != CIPTypeUSINT {
+		t.Errorf("a byte should be a USINT. got %v", ct)
+	}
+	if ct, n := GoVarToCIPType([]byte{1, 2, 3}); ct != CIPTypeUSINT || n != 3 {
+		t.Errorf("a []byte should be 3 USINTs. got %d %v", n, ct)
+	}
+	if ct, _ := GoVarToCIPType(true); ct != CIPTypeBOOL {
+		t.Errorf("a bool should be a BOOL. got %v", ct)
+	}
+
+	r := bytes.NewReader([]byte{0x02, 0x02, 0x00})
+	v, err := readValue(CIPTypeBOOL, r)
+	if err != nil || v != true {
+		t.Errorf("0x02 should read as a BOOL true. got %v %v", v, err)
+	}
+	v, err = readValue(CIPTypeBYTE, r)
+	if err != nil || v != byte(2) {
+		t.Errorf("0x02 should read as a BYTE 2. got %v %v", v, err)
+	}
+	v, err = readValue(CIPTypeBOOL, r)
+	if err != nil || v != false {
+		t.Errorf("0x00 should read as a BOOL false. got %v %v", v, err)
+	}
+	if r.Len() != 0 {
+		t.Errorf("each value should be one byte. %d left over", r.Len())
+	}
+}
+
+func TestReadFlagsByte(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{"flags": int8(-127), "done": true})
+
+	flags, err := Read[byte](client, "Flags")
+	if err != nil {
+		t.Fatalf("problem reading flags: %v", err)
+	}
+	if flags != 0x81 {
+		t.Errorf("wanted 0x81 got 0x%X", flags)
+	}
+	done, err := Read[bool](client, "Done")
+	if err != nil || !done {
+		t.Errorf("wanted done to be true. got %v %v", done, err)
+	}
+}
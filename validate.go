@@ -0,0 +1,158 @@
+package gologix
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Whether Write would send v as this type and it fits.  See Validate.
+func (c CIPType) Accepts(v any) bool {
+	return c.Validate(v) == nil
+}
+
+// Check that Write would send v as this type, and that it fits, without sending anything.  The error says what is
+// wrong with it.
+//
+// Write picks the CIP type from the go type (see GoVarToCIPType) so v has to be the go type of c, like an int32 for a
+// DINT or a float32 for a REAL, or a slice of them for an array tag.  An int or a float64 for a DINT is an error
+// since Write doesn't convert it.  Use EncodeValue for that.  A STRING takes a string of at most 82 characters, the
+// size of the built in STRING.
+//
+// Structures can't be checked without their template so any go struct is accepted for CIPTypeStruct.
+func (c CIPType) Validate(v any) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return fmt.Errorf("can't write nil to a %v", c)
+	}
+	if c == CIPTypeStruct {
+		if rv.Kind() != reflect.Struct {
+			return fmt.Errorf("can't write a %T to a structure. it has to be a struct", v)
+		}
+		return nil
+	}
+	sent, _ := GoVarToCIPType(v)
+	if sent != c {
+		if sent == CIPTypeStruct && rv.Kind() != reflect.Struct {
+			// anything GoVarToCIPType doesn't know is a struct to it.
+			return fmt.Errorf("there is no CIP type for a %T. use a sized type such as int32", v)
+		}
+		return fmt.Errorf("can't write a %T to a %v. Write sends it as a %v", v, c, sent)
+	}
+	return c.fits(v)
+}
+
+// Check that v, which can be any go number for the number types, fits c without being cut off or wrapping around.
+//
+// Any go integer or float fits an integer type as long as it is a whole number in the type's range, so 300 doesn't fit
+// a SINT and -1 doesn't fit a UINT.  REAL takes any number that doesn't overflow a float32 and LREAL any number.  BOOL
+// only takes a bool and STRING a string of at most 82 characters.  A slice or array is checked element by element.
+func (c CIPType) fits(v any) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return fmt.Errorf("can't write nil to a %v", c)
+	}
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			err := c.fits(rv.Index(i).Interface())
+			if err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	switch c {
+	case CIPTypeBOOL:
+		if rv.Kind() != reflect.Bool {
+			return fmt.Errorf("can't write a %T to a BOOL. it has to be a bool", v)
+		}
+		return nil
+	case CIPTypeSTRING:
+		if rv.Kind() != reflect.String {
+			return fmt.Errorf("can't write a %T to a STRING. it has to be a string", v)
+		}
+		if rv.Len() > stringDataSize {
+			return fmt.Errorf("%d characters won't fit a STRING which holds %d", rv.Len(), stringDataSize)
+		}
+		return nil
+	case CIPTypeREAL:
+		f, ok := floatValue(rv)
+		if !ok {
+			return fmt.Errorf("can't write a %T to a REAL. it has to be a number", v)
+		}
+		if !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32 {
+			return fmt.Errorf("%v is too big for a REAL", v)
+		}
+		return nil
+	case CIPTypeLREAL:
+		if _, ok := floatValue(rv); !ok {
+			return fmt.Errorf("can't write a %T to a LREAL. it has to be a number", v)
+		}
+		return nil
+	}
+
+	min, max, ok := c.intRange()
+	if !ok {
+		return fmt.Errorf("can't check values for a %v", c)
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		if i < min || i >= 0 && uint64(i) > max {
+			return fmt.Errorf("%d is out of range for a %v", i, c)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u > max {
+			return fmt.Errorf("%d is out of range for a %v", u, c)
+		}
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) {
+			return fmt.Errorf("%v isn't a whole number so it can't be a %v", f, c)
+		}
+		// the float64 of a 64 bit max rounds up past it so compare with one more than max.
+		if f < float64(min) || f >= float64(max)+1 {
+			return fmt.Errorf("%v is out of range for a %v", f, c)
+		}
+	default:
+		return fmt.Errorf("can't write a %T to a %v. it has to be a number", v, c)
+	}
+	return nil
+}
+
+// the smallest and biggest value of the integer types.  ok is false for types that aren't integers.
+func (c CIPType) intRange() (min int64, max uint64, ok bool) {
+	switch c {
+	case CIPTypeSINT:
+		return math.MinInt8, math.MaxInt8, true
+	case CIPTypeINT:
+		return math.MinInt16, math.MaxInt16, true
+	case CIPTypeDINT:
+		return math.MinInt32, math.MaxInt32, true
+	case CIPTypeLINT, CIPTypeUTIME, CIPTypeLTIME:
+		return math.MinInt64, math.MaxInt64, true
+	case CIPTypeUSINT, CIPTypeBYTE:
+		return 0, math.MaxUint8, true
+	case CIPTypeUINT, CIPTypeWORD:
+		return 0, math.MaxUint16, true
+	case CIPTypeUDINT, CIPTypeDWORD:
+		return 0, math.MaxUint32, true
+	case CIPTypeULINT, CIPTypeLWORD:
+		return 0, math.MaxUint64, true
+	}
+	return 0, 0, false
+}
+
+// the value of any go number as a float64.
+func floatValue(rv reflect.Value) (float64, bool) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
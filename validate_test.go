@@ -0,0 +1,104 @@
+package gologix
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCIPTypeValidate(t *testing.T) {
+	var tests = []struct {
+		t    CIPType
+		v    any
+		want bool
+	}{
+		{CIPTypeSINT, int8(127), true},
+		{CIPTypeSINT, int8(-1), true},
+		{CIPTypeSINT, 127, false},
+		{CIPTypeUINT, uint16(65535), true},
+		{CIPTypeUINT, int16(1), false},
+		{CIPTypeINT, int64(1), false},
+		{CIPTypeDINT, int32(12), true},
+		{CIPTypeDINT, float64(12), false},
+		{CIPTypeDINT, uint32(math.MaxUint32), false},
+		{CIPTypeUDINT, uint32(math.MaxUint32), true},
+		{CIPTypeLINT, int64(math.MinInt64), true},
+		{CIPTypeLINT, uint64(1), false},
+		{CIPTypeLWORD, uint64(math.MaxUint64), true},
+		{CIPTypeULINT, uint64(math.MaxUint64), false},
+		{CIPTypeDINT, "12", false},
+		{CIPTypeDINT, true, false},
+		{CIPTypeREAL, float32(1.5), true},
+		{CIPTypeREAL, float32(math.NaN()), true},
+		{CIPTypeREAL, 1.5, false},
+		{CIPTypeREAL, int32(7), false},
+		{CIPTypeLREAL, 1e39, true},
+		{CIPTypeBOOL, true, true},
+		{CIPTypeBOOL, int32(1), false},
+		{CIPTypeSTRING, "hello", true},
+		{CIPTypeSTRING, string(make([]byte, 83)), false},
+		{CIPTypeSTRING, int32(5), false},
+		{CIPTypeUSINT, []byte{1, 2, 3}, true},
+		{CIPTypeSINT, []int8{1, 2, 3}, false},
+		{CIPTypeSINT, []int{1, 2, 3}, false},
+		{CIPTypeDINT, []int32{1, 2}, true},
+		{CIPTypeINT, [2]int16{1, 2}, false},
+		{CIPTypeSTRING, []string{"a", string(make([]byte, 83))}, false},
+		{CIPTypeStruct, struct{ A int32 }{1}, true},
+		{CIPTypeStruct, []byte{1, 2}, false},
+		{CIPTypeStruct, int32(5), false},
+		{CIPTypeDINT, nil, false},
+		{CIPTypeUnknown, int32(5), false},
+	}
+	for _, tt := range tests {
+		err := tt.t.Validate(tt.v)
+		if (err == nil) != tt.want {
+			t.Errorf("%v %T(%v): wanted ok=%v. got %v", tt.t, tt.v, tt.v, tt.want, err)
+		}
+		if tt.t.Accepts(tt.v) != tt.want {
+			t.Errorf("%v %T(%v): Accepts should be %v", tt.t, tt.v, tt.v, tt.want)
+		}
+	}
+}
+
+// a value Validate accepts for a type is one Write sends as that type, and one it turns down for a type Write sends as
+// something else.
+func TestCIPTypeValidateMatchesWrite(t *testing.T) {
+	client := NewClient("localhost")
+	var tests = []struct {
+		t CIPType
+		v any
+	}{
+		{CIPTypeSINT, int8(-1)},
+		{CIPTypeINT, int16(1)},
+		{CIPTypeDINT, int32(12)},
+		{CIPTypeDINT, float64(12)},
+		{CIPTypeDINT, []int32{1, 2}},
+		{CIPTypeUDINT, uint32(1)},
+		{CIPTypeLINT, int64(1)},
+		{CIPTypeLWORD, uint64(1)},
+		{CIPTypeULINT, uint64(1)},
+		{CIPTypeREAL, float32(1.5)},
+		{CIPTypeREAL, 1.5},
+		{CIPTypeREAL, int32(7)},
+		{CIPTypeLREAL, 1.5},
+		{CIPTypeBOOL, true},
+		{CIPTypeSTRING, "hello"},
+		{CIPTypeStruct, struct{ A int32 }{1}},
+	}
+	for _, tt := range tests {
+		// the service, the path size, the path of "T1", and then the type.
+		b, err := client.EncodeWrite("T1", tt.v)
+		if err != nil {
+			t.Errorf("%T(%v): problem encoding write: %v", tt.v, tt.v, err)
+			continue
+		}
+		sent := CIPType(b[6])
+		if sent == CIPTypeStruct && b[8] == 0xCE && b[9] == 0x0F {
+			// the handle of the builtin STRING.
+			sent = CIPTypeSTRING
+		}
+		if tt.t.Accepts(tt.v) != (sent == tt.t) {
+			t.Errorf("%v %T(%v): Write sends it as %v but Accepts is %v", tt.t, tt.v, tt.v, sent, tt.t.Accepts(tt.v))
+		}
+	}
+}
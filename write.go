@@ -5,6 +5,8 @@ import (
 	"encoding/binary"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 )
 
 // The write equivalent to ReadMulti.  value should be a struct where each field has a tag of the form `gologix:"tagname"` that maps
@@ -30,21 +32,96 @@ func (client *Client) WriteMulti(value any) error {
 // write a single value to a single tag.
 // the type of value must correspond to the type of tag in the controller
 func (client *Client) Write(tag string, value any) error {
+	return client.WriteWithTimeout(tag, value, 0)
+}
+
+// Write a single value to a single tag like Write but wait at most timeout for the controller to respond.
+//
+// A timeout of 0 uses WriteTimeout.  If the controller doesn't respond in time you get ErrTimeout and the
+// session stays up.  The write may or may not have happened in that case.
+func (client *Client) WriteWithTimeout(tag string, value any, timeout time.Duration) error {
 	err := client.checkConnection()
 	if err != nil {
 		return fmt.Errorf("could not start write: %w", err)
 	}
+	err = client.checkWritable(tag)
+	if err != nil {
+		return err
+	}
 	v := reflect.ValueOf(value)
-	if v.Kind() == reflect.Struct {
-		return client.write_udt(tag, value)
+	return client.withRetry(tag, func() error {
+		if v.Kind() == reflect.Struct {
+			return client.write_udt(tag, value, timeout)
+		}
+		return client.write_single(tag, value, timeout)
+	})
+}
+
+// Write a single value to a tag with the CIP type picked from T (see GoTypeToCIPType).
+//
+// If the tag is in KnownTags (see ListAllTags) its type is checked before anything is sent.  Otherwise the
+// controller checks it and a mismatch comes back as a type mismatch error.  Go strings are written as the LEN + DATA
+// structure of the builtin STRING type.
+//
+// Example:
+//
+//	err := gologix.Write(client, "TestDint", int32(5))
+func Write[T GoLogixTypes](client *Client, tag string, value T) error {
+	err := client.checkConnection()
+	if err != nil {
+		return fmt.Errorf("could not start write: %w", err)
+	}
+	err = client.checkWritable(tag)
+	if err != nil {
+		return err
+	}
+	ct := GoTypeToCIPType[T]()
+	err = client.checkKnownType(tag, ct)
+	if err != nil {
+		return err
+	}
+	return client.withRetry(tag, func() error {
+		if s, ok := any(value).(string); ok {
+			return client.write_udt(tag, logixString{Value: s}, 0)
+		}
+		return client.write_single(tag, value, 0)
+	})
+}
+
+// if we know the tag from a ListAllTags call make sure its external access allows writes.  Members and elements of a
+// tag go by the tag they are in.  Tags we don't know about are left for the controller to refuse.
+func (client *Client) checkWritable(tag string) error {
+	base, _ := splitTagBase(tag)
+	known, ok := client.KnownTags[strings.ToLower(base)]
+	if !ok || known.Info.ExternalAccess.Writable() {
+		return nil
+	}
+	return fmt.Errorf("can't write %s. its external access is %v: %w", tag, known.Info.ExternalAccess, ErrReadOnly)
+}
+
+// if we know the type of the tag from a ListAllTags call make sure it can hold a ct.
+// tags we don't know about, and structures other than STRING, are left for the controller to check.
+func (client *Client) checkKnownType(tag string, ct CIPType) error {
+	known, ok := client.KnownTags[strings.ToLower(tag)]
+	if !ok || known.Info.Dimension1 != 0 {
+		return nil
+	}
+	if known.Info.Atomic() {
+		if known.Info.Type != ct {
+			return fmt.Errorf("type mismatch: tag %s is %v but you gave %v", tag, known.Info.Type, ct)
+		}
+		return nil
+	}
+	if ct != CIPTypeSTRING && known.UDT != nil && known.UDT.Name == "STRING" {
+		return fmt.Errorf("type mismatch: tag %s is a STRING but you gave %v", tag, ct)
 	}
-	return client.write_single(tag, value)
+	return nil
 }
 
 // write a single UDT struct to a tag.  The UDT *must* be named the same as the struct type and have the same field types.
 // field names don't matter but type names do.  go types will be converted to CIP types as appropriate, but any nested structs
 // must be named the same as the UDT on the plc.
-func (client *Client) write_udt(tag string, value any) error {
+func (client *Client) write_udt(tag string, value any, timeout time.Duration) error {
 	//service = 0x4D // cipService_Write
 	datatype := CIPTypeStruct
 	ioi, err := client.newIOI(tag, datatype)
@@ -66,11 +143,16 @@ func (client *Client) write_udt(tag string, value any) error {
 
 	UDTdata := bytes.NewBuffer([]byte{})
 
-	_, err = Pack(UDTdata, value)
+	_, err = cipPack{order: client.byteOrder()}.pack(UDTdata, value)
 	if err != nil {
 		return fmt.Errorf("problem packing data. %w", err)
 	}
 
+	typeInfo := []byte{byte(datatype), 2, byte(typecrc), byte(typecrc >> 8)}
+	if client.needsFragmenting(ioi, typeInfo, UDTdata.Bytes()) {
+		return client.writeFragmented(tag, ioi, typeInfo, elements, UDTdata.Bytes(), 4, timeout)
+	}
+
 	reqitems := make([]CIPItem, 2)
 	reqitems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
 	reqitems[1] = CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
@@ -86,7 +168,7 @@ func (client *Client) write_udt(tag string, value any) error {
 	if err != nil {
 		return err
 	}
-	hdr, data, err := client.send_recv_data(cipCommandSendUnitData, itemdata)
+	hdr, data, err := client.send_recv_data_timeout(client.writeTimeout(timeout), cipCommandSendUnitData, itemdata)
 	if err != nil {
 		return err
 	}
@@ -108,29 +190,31 @@ func (client *Client) write_udt(tag string, value any) error {
 		return fmt.Errorf("problem deserializing write response header, %w", err)
 	}
 	if hdr2.Status != CIPStatus_OK {
-		extended := uint16(0)
-		if hdr2.StatusExtended == 1 {
-			err = items[1].DeSerialize(&extended)
-			return fmt.Errorf("got status %d:%d:%d instead of 0 in write response.  Problem getting extended status %w",
-				hdr2.Status,
-				hdr2.StatusExtended,
-				extended,
-				err)
-
-		}
-		return fmt.Errorf("got status %d:%d:%d instead of 0 in write response", hdr2.Status, hdr2.StatusExtended, extended)
+		return fmt.Errorf("problem writing %s: %w", tag, writeReplyError(hdr2, &items[1]))
 	}
 	return err
 }
 
 // write a single value to a single tag.
-func (client *Client) write_single(tag string, value any) error {
+func (client *Client) write_single(tag string, value any, timeout time.Duration) error {
 	//service = 0x4D // cipService_Write
+	if b, ok := value.(bool); ok {
+		if base, index, ok := client.boolArrayElement(tag); ok {
+			return client.writeBoolElement(base, index, b, timeout)
+		}
+	}
 	datatype, _ := GoVarToCIPType(value)
 	ioi, err := client.newIOI(tag, datatype)
 	if err != nil {
 		return fmt.Errorf("problem generating IOI. %w", err)
 	}
+	if ioi.BitAccess {
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("can only write a bool to bit %d of %s. got %T", ioi.BitPosition, tag, value)
+		}
+		return client.writeBit(tag, ioi, b, timeout)
+	}
 	elements := uint16(1)
 
 	v := reflect.ValueOf(value)
@@ -148,19 +232,29 @@ func (client *Client) write_single(tag string, value any) error {
 		Elements: elements,
 	}
 
+	payload := CIPItem{}
+	err = payload.SerializeOrder(value, client.byteOrder())
+	if err != nil {
+		return fmt.Errorf("problem serializing value. %w", err)
+	}
+	typeInfo := binary.LittleEndian.AppendUint16(nil, uint16(datatype))
+	if client.needsFragmenting(ioi, typeInfo, payload.Data) {
+		return client.writeFragmented(tag, ioi, typeInfo, elements, payload.Data, datatype.Size(), timeout)
+	}
+
 	reqitems := make([]CIPItem, 2)
 	reqitems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
 	reqitems[1] = CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
 	reqitems[1].Serialize(ioi_header)
 	reqitems[1].Serialize(ioi.Buffer)
 	reqitems[1].Serialize(ioi_footer)
-	reqitems[1].Serialize(value)
+	reqitems[1].Serialize(payload.Data)
 
 	itemdata, err := serializeItems(reqitems)
 	if err != nil {
 		return err
 	}
-	hdr, data, err := client.send_recv_data(cipCommandSendUnitData, itemdata)
+	hdr, data, err := client.send_recv_data_timeout(client.writeTimeout(timeout), cipCommandSendUnitData, itemdata)
 	if err != nil {
 		return err
 	}
@@ -182,21 +276,31 @@ func (client *Client) write_single(tag string, value any) error {
 		return fmt.Errorf("problem deserializing write response header, %w", err)
 	}
 	if hdr2.Status != CIPStatus_OK {
-		extended := uint16(0)
-		if hdr2.StatusExtended == 1 {
-			err = items[1].DeSerialize(&extended)
-			return fmt.Errorf("got status %d:%d:%d instead of 0 in write response.  Problem getting extended status %w",
-				hdr2.Status,
-				hdr2.StatusExtended,
-				extended,
-				err)
-
+		cerr := writeReplyError(hdr2, &items[1])
+		if cerr.General == 0xFF && cerr.extended() == extStatusTypeMismatch {
+			return fmt.Errorf("type mismatch: tag %s is not a %v: %w", tag, datatype, cerr)
 		}
-		return fmt.Errorf("got status %d:%d:%d instead of 0 in write response", hdr2.Status, hdr2.StatusExtended, extended)
+		return fmt.Errorf("problem writing %s: %w", tag, cerr)
 	}
 	return err
 }
 
+// extended status the controller gives when the data type in a write doesn't match the tag.
+const extStatusTypeMismatch = 0x2107
+
+// the error for a write reply with a bad status.  The extended status words, if any, follow the header in item.
+func writeReplyError(hdr msgWriteResultHeader, item *CIPItem) *CIPError {
+	cerr := &CIPError{General: byte(hdr.Status)}
+	for i := 0; i < int(hdr.StatusExtended); i++ {
+		var extended uint16
+		if item.DeSerialize(&extended) != nil {
+			break
+		}
+		cerr.Extended = append(cerr.Extended, extended)
+	}
+	return cerr
+}
+
 type msgWriteResultHeader struct {
 	SequenceCount  uint16
 	Service        CIPService
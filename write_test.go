@@ -0,0 +1,72 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestLogixStringPack(t *testing.T) {
+	b := bytes.Buffer{}
+	n, err := Pack(&b, logixString{Value: "hello"})
+	if err != nil {
+		t.Fatalf("problem packing: %v", err)
+	}
+	if n != 88 || b.Len() != 88 {
+		t.Errorf("STRING should pack to 88 bytes. got %d", b.Len())
+	}
+	dat := b.Bytes()
+	if l := binary.LittleEndian.Uint32(dat); l != 5 {
+		t.Errorf("LEN should be 5. got %d", l)
+	}
+	if string(dat[4:9]) != "hello" {
+		t.Errorf("DATA should start with hello. got %q", dat[4:9])
+	}
+	for i := 9; i < len(dat); i++ {
+		if dat[i] != 0 {
+			t.Errorf("byte %d should be padding. got %d", i, dat[i])
+		}
+	}
+
+	_, crc, err := TypeEncode(logixString{})
+	if err != nil {
+		t.Fatalf("problem encoding type: %v", err)
+	}
+	if crc != 0x0FCE {
+		t.Errorf("wrong structure handle %X", crc)
+	}
+
+	_, err = Pack(&bytes.Buffer{}, logixString{Value: strings.Repeat("x", 83)})
+	if err == nil {
+		t.Errorf("83 characters shouldn't fit in a STRING")
+	}
+}
+
+func TestCheckKnownType(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.KnownTags["testdint"] = KnownTag{Name: "TestDint", Info: TagInfo{Type: CIPTypeDINT}}
+	client.KnownTags["teststring"] = KnownTag{Name: "TestString", Info: TagInfo{Type: CIPTypeStruct, TypeInfo: 0x8F}, UDT: &UDTDescriptor{Name: "STRING"}}
+
+	var tests = []struct {
+		tag  string
+		ct   CIPType
+		fail bool
+	}{
+		{"TestDint", CIPTypeDINT, false},
+		{"TestDint", CIPTypeSTRING, true},
+		{"TestDint", CIPTypeREAL, true},
+		{"TestString", CIPTypeSTRING, false},
+		{"TestString", CIPTypeDINT, true},
+		{"SomethingElse", CIPTypeSTRING, false},
+	}
+	for _, tt := range tests {
+		err := client.checkKnownType(tt.tag, tt.ct)
+		if tt.fail && err == nil {
+			t.Errorf("%s as %v: expected an error", tt.tag, tt.ct)
+		}
+		if !tt.fail && err != nil {
+			t.Errorf("%s as %v: unexpected error %v", tt.tag, tt.ct, err)
+		}
+	}
+}
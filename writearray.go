@@ -0,0 +1,72 @@
+package gologix
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Write a slice to an array tag.  values must be a non-empty slice of one of the atomic go types (ex: []int32 for a
+// DINT array) and its CIP type and element count come from the slice.
+//
+// tag can be the array (which writes from element 0) or an element of it (ex: "Arr[10]") to write from there on.  A
+// slice too big for one request is sent with fragmented writes.
+//
+// If the array is in KnownTags (see ListAllTags) its type and length are checked before anything is sent.  Otherwise
+// the controller checks them and a mismatch comes back as a type mismatch error.  BOOL arrays are packed into DWORDs
+// so write them as a []uint32 with 32 bools in each element.
+func (client *Client) WriteArray(tag string, values any) error {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("values must be a slice. got %T", values)
+	}
+	if v.Len() == 0 {
+		return fmt.Errorf("can't write an empty slice to %s", tag)
+	}
+	if v.Len() > 0xFFFF {
+		return fmt.Errorf("can write at most %d elements at once. got %d", 0xFFFF, v.Len())
+	}
+	elem := v.Type().Elem()
+	if elem.Kind() == reflect.Bool {
+		return fmt.Errorf("can't write a []bool to %s. BOOL arrays are written as a []uint32", tag)
+	}
+	ct, _ := GoVarToCIPType(reflect.Zero(elem).Interface())
+	if ct == CIPTypeUnknown || ct == CIPTypeStruct || ct == CIPTypeSTRING {
+		return fmt.Errorf("can't write a %T to %s. the elements have to be an atomic type", values, tag)
+	}
+	err := client.checkKnownArray(tag, ct, v.Len())
+	if err != nil {
+		return err
+	}
+
+	err = client.checkConnection()
+	if err != nil {
+		return fmt.Errorf("could not start array write: %w", err)
+	}
+	return client.withRetry(tag, func() error {
+		return client.write_single(tag, values, 0)
+	})
+}
+
+// if we know the array from a ListAllTags call make sure it is an array of ct with room for count elements from where
+// tag starts.
+func (client *Client) checkKnownArray(tag string, ct CIPType, count int) error {
+	base, start := tag, 0
+	if b, i, ok := arrayElement(tag); ok {
+		base, start = b, i
+	}
+	known, ok := client.KnownTags[strings.ToLower(base)]
+	if !ok || !known.Info.Atomic() {
+		return nil
+	}
+	if known.Info.Dimension1 == 0 {
+		return fmt.Errorf("tag %s isn't an array", base)
+	}
+	if known.Info.Type != ct {
+		return fmt.Errorf("type mismatch: tag %s is a %v array but you gave %v", base, known.Info.Type, ct)
+	}
+	if known.Info.Dimension2 == 0 && start+count > int(known.Info.Dimension1) {
+		return fmt.Errorf("writing %d elements from %d runs past the end of %s which has %d", count, start, base, known.Info.Dimension1)
+	}
+	return nil
+}
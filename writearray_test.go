@@ -0,0 +1,78 @@
+package gologix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWriteArray(t *testing.T) {
+	client, remote := newPipeClient(t)
+
+	requests := make(chan []byte, 1)
+	go func() {
+		hdr, buf, err := recvData(remote)
+		if err != nil {
+			t.Errorf("problem reading request: %v", err)
+			return
+		}
+		requests <- buf.Bytes()[20:]
+		item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+		item.Serialize(msgWriteResultHeader{Service: CIPService_Write.AsResponse()})
+		b, err := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+		if err != nil {
+			t.Errorf("problem building reply: %v", err)
+			return
+		}
+		writeTestResponse(t, remote, hdr.Context, *b)
+	}()
+
+	done := make(chan error)
+	go func() { done <- client.WriteArray("Data[2]", []int16{1, 2, -1}) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("problem writing array: %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("timed out waiting for WriteArray")
+	}
+
+	req := <-requests
+	want := []byte{
+		byte(CIPService_Write), 0x04, // path size in words
+		0x91, 0x04, 'd', 'a', 't', 'a', 0x28, 0x02,
+		0xC3, 0x00, // INT
+		0x03, 0x00, // 3 elements
+		0x01, 0x00, 0x02, 0x00, 0xFF, 0xFF,
+	}
+	if !check_bytes(req[2:], want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(req[2:]))
+	}
+}
+
+func TestWriteArrayErrors(t *testing.T) {
+	client, _ := newPipeClient(t)
+	client.KnownTags["data"] = KnownTag{Info: TagInfo{Type: CIPTypeDINT, TypeInfo: 0x20, Dimension1: 10}}
+	client.KnownTags["count"] = KnownTag{Info: TagInfo{Type: CIPTypeDINT}}
+
+	var tests = []struct {
+		name   string
+		tag    string
+		values any
+	}{
+		{"not a slice", "Data", int32(1)},
+		{"empty", "Data", []int32{}},
+		{"bools", "Data", []bool{true}},
+		{"strings", "Data", []string{"a"}},
+		{"wrong type", "Data", []float32{1}},
+		{"too long", "Data", make([]int32, 11)},
+		{"too long from an index", "Data[8]", make([]int32, 3)},
+		{"not an array", "Count", []int32{1}},
+	}
+	for _, tt := range tests {
+		err := client.WriteArray(tt.tag, tt.values)
+		if err == nil {
+			t.Errorf("%s: wanted an error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package gologix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TagWrite is one write for WriteMultiple.
+type TagWrite struct {
+	Tag   string
+	Type  CIPType // the CIP type of the tag.  CIPTypeUnknown picks it from the go type of Value (see GoVarToCIPType)
+	Value any     // an atomic value, a slice of atomic values, a string, or a struct as for Write.
+}
+
+// Write a list of tags in as few multi-service requests as will fit in the connection size.
+//
+// The error slice is the same length as writes and in the same order.  A write that fails gets a non-nil error at its
+// index without affecting the rest.  A write that is too big to go in a multi-service request on its own gets an error
+// and you should use Write for it instead.
+//
+// The writes in one request are all sent together, but the controller does them one at a time so a failed write
+// doesn't undo the others.
+func (client *Client) WriteMultiple(writes []TagWrite) []error {
+	errs := make([]error, len(writes))
+
+	err := client.checkConnection()
+	if err != nil {
+		err = fmt.Errorf("could not start multiple write: %w", err)
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	// writes that we can't encode get their error now and are left out of the requests.
+	services := make([][]byte, 0, len(writes))
+	tags := make([]string, 0, len(writes))
+	indexes := make([]int, 0, len(writes))
+	for i, w := range writes {
+		service, err := client.encodeTagWrite(w)
+		if err != nil {
+			errs[i] = fmt.Errorf("problem encoding write of %s: %w", w.Tag, err)
+			continue
+		}
+		if client.multiServiceSize([][]byte{service}) > int(client.ConnectionSize) {
+			errs[i] = fmt.Errorf("write of %s is %d bytes which doesn't fit in a multiple service request. use Write instead", w.Tag, len(service))
+			continue
+		}
+		services = append(services, service)
+		tags = append(tags, w.Tag)
+		indexes = append(indexes, i)
+	}
+
+	n := 0
+	msgs := 0
+	for n < len(services) {
+		msgs += 1
+		n_new := client.countWritesThatFit(services[n:])
+		suberrs, err := client.writeList(tags[n:n+n_new], services[n:n+n_new])
+		for j, idx := range indexes[n : n+n_new] {
+			if err != nil {
+				// the whole request failed so every write in it failed.
+				errs[idx] = err
+				continue
+			}
+			errs[idx] = suberrs[j]
+		}
+		n += n_new
+	}
+
+	client.Logger.Debug("Multiple Write", "messages", msgs, "tags", n)
+	return errs
+}
+
+// build the write service for one entry of a multi-service request: the service header, the path, the type, the
+// element count, and the data.
+func (client *Client) encodeTagWrite(w TagWrite) ([]byte, error) {
+	err := client.checkWritable(w.Tag)
+	if err != nil {
+		return nil, err
+	}
+	value := w.Value
+	if s, ok := value.(string); ok {
+		value = logixString{Value: s}
+	}
+
+	var typeInfo []byte
+	var data []byte
+	elements := uint16(1)
+	datatype := w.Type
+	if reflect.ValueOf(value).Kind() == reflect.Struct {
+		datatype = CIPTypeStruct
+		_, typecrc, err := TypeEncode(value)
+		if err != nil {
+			return nil, fmt.Errorf("problem encoding type. %w", err)
+		}
+		b := bytes.Buffer{}
+		_, err = cipPack{order: client.byteOrder()}.pack(&b, value)
+		if err != nil {
+			return nil, fmt.Errorf("problem packing data. %w", err)
+		}
+		typeInfo = []byte{byte(datatype), 2, byte(typecrc), byte(typecrc >> 8)}
+		data = b.Bytes()
+	} else {
+		ct, count := GoVarToCIPType(value)
+		if ct == CIPTypeStruct && datatype == CIPTypeUnknown {
+			// anything GoVarToCIPType doesn't know is a struct to it but we know this isn't one.
+			return nil, fmt.Errorf("there is no CIP type for a %T. use a sized type such as int32", w.Value)
+		}
+		if datatype == CIPTypeUnknown {
+			datatype = ct
+		}
+		if datatype == CIPTypeUnknown || datatype == CIPTypeStruct || datatype == CIPTypeSTRING {
+			return nil, fmt.Errorf("can't write %T as %v", w.Value, datatype)
+		}
+		elements = uint16(count)
+		payload := CIPItem{}
+		err := payload.SerializeOrder(value, client.byteOrder())
+		if err != nil {
+			return nil, fmt.Errorf("problem serializing value. %w", err)
+		}
+		typeInfo = binary.LittleEndian.AppendUint16(nil, uint16(datatype))
+		data = payload.Data
+	}
+
+	ioi, err := client.newIOI(w.Tag, datatype)
+	if err != nil {
+		return nil, fmt.Errorf("problem generating IOI. %w", err)
+	}
+	if ioi.BitAccess {
+		return nil, fmt.Errorf("can't write bit %d of %s in a multiple write. use Write instead", ioi.BitPosition, w.Tag)
+	}
+	if _, ok := value.(bool); ok {
+		if _, _, ok := client.boolArrayElement(w.Tag); ok {
+			return nil, fmt.Errorf("can't write BOOL array element %s in a multiple write. use Write instead", w.Tag)
+		}
+	}
+
+	b := bytes.Buffer{}
+	h := msgCIPMultiIOIHeader{
+		Service: CIPService_Write,
+		Size:    byte(len(ioi.Buffer) / 2),
+	}
+	err = binary.Write(&b, binary.LittleEndian, h)
+	if err != nil {
+		return nil, fmt.Errorf("problem writing write header to buffer. %w", err)
+	}
+	b.Write(ioi.Buffer)
+	b.Write(typeInfo)
+	err = binary.Write(&b, binary.LittleEndian, elements)
+	if err != nil {
+		return nil, fmt.Errorf("problem writing element count to buffer. %w", err)
+	}
+	b.Write(data)
+	return b.Bytes(), nil
+}
+
+// the size of a multi-service request made up of the given services.
+func (client *Client) multiServiceSize(services [][]byte) int {
+	size := binary.Size(msgCIPConnectedMultiServiceReq{}) + 2*len(services) // the header and jump table
+	for _, s := range services {
+		size += len(s)
+	}
+	return size
+}
+
+// how many of the services fit in one multi-service request.  At least one is always taken so the caller can make
+// progress.  Write replies are only a few bytes each so only the request size matters.
+func (client *Client) countWritesThatFit(services [][]byte) int {
+	size := binary.Size(msgCIPConnectedMultiServiceReq{})
+	for i, s := range services {
+		size += 2 + len(s)
+		if size > int(client.ConnectionSize) && i > 0 {
+			return i
+		}
+	}
+	return len(services)
+}
+
+// send the write services in a single multi-service request.
+//
+// The error return is for problems with the request as a whole.  A problem with one of the writes is returned in the
+// error slice at that write's index and doesn't affect the others.
+//
+// A request that fails as a whole with an error RetryPolicy says to retry is sent again.  None of its writes were
+// done in that case.
+func (client *Client) writeList(tags []string, services [][]byte) ([]error, error) {
+	var errs []error
+	err := client.withRetry(strings.Join(tags, ","), func() error {
+		var err error
+		errs, err = client.writeListOnce(tags, services)
+		return err
+	})
+	return errs, err
+}
+
+func (client *Client) writeListOnce(tags []string, services [][]byte) ([]error, error) {
+	qty := len(services)
+	ioi_header := msgCIPConnectedMultiServiceReq{
+		Sequence:     uint16(sequencer()),
+		Service:      CIPService_MultipleService,
+		PathSize:     2,
+		Path:         [4]byte{0x20, 0x02, 0x24, 0x01},
+		ServiceCount: uint16(qty),
+	}
+
+	b := bytes.Buffer{}
+	jump_table := make([]uint16, qty)
+	jump_start := 2 + qty*2 // 2 bytes + 2 bytes per jump entry
+	for i, s := range services {
+		jump_table[i] = uint16(jump_start + b.Len())
+		b.Write(s)
+	}
+
+	reqItems := make([]CIPItem, 2)
+	reqItems[0] = newItem(cipItem_ConnectionAddress, &client.OTNetworkConnectionID)
+	reqItems[1] = CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+	reqItems[1].Serialize(ioi_header)
+	reqItems[1].Serialize(jump_table)
+	reqItems[1].Serialize(&b)
+
+	itemData, err := serializeItems(reqItems)
+	if err != nil {
+		return nil, err
+	}
+	hdr, data, err := client.send_recv_data_timeout(client.writeTimeout(0), cipCommandSendUnitData, itemData)
+	if err != nil {
+		return nil, err
+	}
+	if hdr.Status != 0 {
+		return nil, fmt.Errorf("problem writing tags. Status %v", CIPStatus(hdr.Status))
+	}
+
+	read_result_header := msgCIPResultHeader{}
+	err = binary.Read(data, binary.LittleEndian, &read_result_header)
+	if err != nil {
+		client.Logger.Warn("Problem reading write result header", "error", err)
+	}
+	items, err := readItems(data)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading items. %w", err)
+	}
+	if len(items) != 2 {
+		return nil, fmt.Errorf("wrong Number of Items. Expected 2 but got %v", len(items))
+	}
+	return parseMultiWriteReply(tags, items[1].Data)
+}
+
+// parse the connected data of a multi-service write reply into an error for each write.
+func parseMultiWriteReply(tags []string, dat []byte) ([]error, error) {
+	// sequence count, service, reserved, general status, extended status size, reply count
+	if len(dat) < 8 {
+		return nil, fmt.Errorf("multiple service reply too short. got %d bytes", len(dat))
+	}
+	service := CIPService(dat[2])
+	if !service.IsResponse() {
+		return nil, fmt.Errorf("wasn't a response service. Got %v", service)
+	}
+	// an embedded service error means at least one of the writes failed.  We'll find out which below.
+	status := CIPStatus(dat[4])
+	if status != CIPStatus_OK && status != CIPStatus_EmbeddedServiceError {
+		cerr, _ := parseCIPStatus(dat[4:])
+		return nil, fmt.Errorf("multiple service request failed: %w", cerr)
+	}
+	qty := int(binary.LittleEndian.Uint16(dat[6:]))
+	if qty != len(tags) {
+		return nil, fmt.Errorf("expected %d replies but got %d", len(tags), qty)
+	}
+	// the offsets count from the start of the reply count.
+	replies := dat[6:]
+	if len(replies) < 2+2*qty {
+		return nil, fmt.Errorf("multiple service reply too short for %d offsets", qty)
+	}
+
+	errs := make([]error, qty)
+	for i := 0; i < qty; i++ {
+		offset := int(binary.LittleEndian.Uint16(replies[2+2*i:]))
+		end := len(replies)
+		if i+1 < qty {
+			end = int(binary.LittleEndian.Uint16(replies[2+2*(i+1):]))
+		}
+		if offset+4 > end || end > len(replies) {
+			return nil, fmt.Errorf("bad offset %d for reply %d", offset, i)
+		}
+		reply := replies[offset:end]
+		if CIPService(reply[0]).UnResponse() != CIPService_Write {
+			errs[i] = fmt.Errorf("expected a write reply for %s but got %v", tags[i], CIPService(reply[0]))
+			continue
+		}
+		if CIPStatus(reply[2]) != CIPStatus_OK {
+			cerr, _ := parseCIPStatus(reply[2:])
+			errs[i] = fmt.Errorf("problem writing %s: %w", tags[i], cerr)
+		}
+	}
+	return errs, nil
+}
@@ -0,0 +1,186 @@
+package gologix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeTagWrite(t *testing.T) {
+	client := NewClient("127.0.0.1")
+	client.knownFirmware = 32
+
+	have, err := client.encodeTagWrite(TagWrite{Tag: "Setpoint", Value: float32(1.5)})
+	if err != nil {
+		t.Fatalf("problem encoding: %v", err)
+	}
+	want := []byte{
+		0x4D, 0x05, // write service, path size in words
+		0x91, 0x08, 's', 'e', 't', 'p', 'o', 'i', 'n', 't',
+		0xCA, 0x00, // REAL
+		0x01, 0x00, // 1 element
+		0x00, 0x00, 0xC0, 0x3F,
+	}
+	if !check_bytes(have, want) {
+		t.Errorf("\nwanted %v\ngot    %v", to_hex(want), to_hex(have))
+	}
+
+	// a given type overrides the go type
+	have, err = client.encodeTagWrite(TagWrite{Tag: "a", Type: CIPTypeDWORD, Value: []uint32{1, 2}})
+	if err != nil {
+		t.Fatalf("problem encoding: %v", err)
+	}
+	if have[6] != byte(CIPTypeDWORD) || binary.LittleEndian.Uint16(have[8:]) != 2 || len(have) != 18 {
+		t.Errorf("wanted a 2 element DWORD write. got %v", to_hex(have))
+	}
+
+	// strings go as the STRING structure
+	have, err = client.encodeTagWrite(TagWrite{Tag: "a", Value: "hi"})
+	if err != nil {
+		t.Fatalf("problem encoding: %v", err)
+	}
+	if have[6] != byte(CIPTypeStruct) || binary.LittleEndian.Uint16(have[8:]) != 0x0FCE || len(have) != 12+88 {
+		t.Errorf("wanted a STRING structure write. got %v", to_hex(have[:12]))
+	}
+
+	_, err = client.encodeTagWrite(TagWrite{Tag: "a", Value: []string{"no"}})
+	if err == nil {
+		t.Errorf("string arrays shouldn't encode")
+	}
+}
+
+// 40 writes should be split over several requests and a failed write should have its error at its own index.
+func TestWriteMultiple(t *testing.T) {
+	client, remote := newPipeClient(t)
+	client.ConnectionSize = 200
+
+	const count = 40
+	requests := make(chan int, count) // the number of writes in each request
+	go func() {
+		seen := 0
+		for seen < count-1 {
+			hdr, buf, err := recvData(remote)
+			if err != nil {
+				t.Errorf("problem reading request: %v", err)
+				return
+			}
+			// items header, connection address item, connected data item header, then the multi-service header
+			dat := buf.Bytes()[20:]
+			services := dat[8:]
+			qty := int(binary.LittleEndian.Uint16(services))
+			requests <- qty
+
+			reply := binary.LittleEndian.AppendUint16(nil, 0)
+			reply = append(reply, byte(CIPService_MultipleService.AsResponse()), 0, 0, 0)
+			reply = binary.LittleEndian.AppendUint16(reply, uint16(qty))
+			for i := 0; i < qty; i++ {
+				reply = binary.LittleEndian.AppendUint16(reply, uint16(2+2*qty+4*i))
+			}
+			for i := 0; i < qty; i++ {
+				service := services[binary.LittleEndian.Uint16(services[2+2*i:]):]
+				if service[0] != byte(CIPService_Write) || service[2] != 0x91 {
+					t.Errorf("expected a symbolic write. got %v", service[:4])
+				}
+				name := string(service[4 : 4+service[3]]) // tag names are lower cased in the IOI
+				n, _ := strconv.Atoi(strings.TrimPrefix(name, "setpoint"))
+				status := byte(CIPStatus_OK)
+				if n%10 == 3 {
+					status = byte(CIPStatus_PathDestinationUnknown)
+					reply[4] = byte(CIPStatus_EmbeddedServiceError)
+				}
+				reply = append(reply, byte(CIPService_Write.AsResponse()), 0, status, 0)
+			}
+			items := []CIPItem{
+				newItem(cipItem_ConnectionAddress, uint32(0)),
+				{Header: cipItemHeader{ID: cipItem_ConnectedData}},
+			}
+			items[1].Serialize(reply)
+			b, err := serializeItems(items)
+			if err != nil {
+				t.Errorf("problem building reply: %v", err)
+				return
+			}
+			writeTestResponse(t, remote, hdr.Context, *b)
+			seen += qty
+		}
+		close(requests)
+	}()
+
+	writes := make([]TagWrite, count)
+	for i := range writes {
+		writes[i] = TagWrite{Tag: fmt.Sprintf("Setpoint%d", i), Value: float32(i)}
+	}
+	writes[7].Value = map[int]int{} // can't be encoded so it never gets sent
+
+	done := make(chan []error)
+	go func() { done <- client.WriteMultiple(writes) }()
+	var errs []error
+	select {
+	case errs = <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("timed out waiting for WriteMultiple")
+	}
+
+	if len(errs) != count {
+		t.Fatalf("wanted %d errors got %d", count, len(errs))
+	}
+	for i, err := range errs {
+		switch {
+		case i == 7:
+			if err == nil {
+				t.Errorf("write 7 should have failed to encode")
+			}
+		case i%10 == 3:
+			if !IsTagNotFound(err) {
+				t.Errorf("write %d should be tag not found. got %v", i, err)
+			}
+		default:
+			if err != nil {
+				t.Errorf("write %d: unexpected error %v", i, err)
+			}
+		}
+	}
+
+	total := 0
+	msgs := 0
+	for qty := range requests {
+		msgs++
+		total += qty
+	}
+	if msgs < 2 || total != count-1 {
+		t.Errorf("wanted the %d good writes split over several requests. got %d in %d", count-1, total, msgs)
+	}
+}
+
+func TestWriteMapErrors(t *testing.T) {
+	_, client := newTestServer(t, map[string]any{"count": int32(0), "setpoint": float32(0)})
+
+	errs := client.WriteMapErrors(map[string]any{
+		"Count":    int32(12),
+		"Setpoint": float32(2.5),
+		"Untyped":  5,
+	})
+	if len(errs) != 1 || errs["Untyped"] == nil {
+		t.Fatalf("wanted just an error for Untyped. got %v", errs)
+	}
+	if !strings.Contains(errs["Untyped"].Error(), "int32") {
+		t.Errorf("the error should say what to use instead of an int. got %v", errs["Untyped"])
+	}
+
+	count, err := Read[int32](client, "Count")
+	if err != nil || count != 12 {
+		t.Errorf("wanted count to be written as 12. got %v %v", count, err)
+	}
+	setpoint, err := Read[float32](client, "Setpoint")
+	if err != nil || setpoint != 2.5 {
+		t.Errorf("wanted setpoint to be written as 2.5. got %v %v", setpoint, err)
+	}
+
+	err = client.WriteMap(map[string]any{"Count": int32(13), "Untyped": 5})
+	if err == nil || !strings.Contains(err.Error(), "Untyped") {
+		t.Errorf("WriteMap should return the error for Untyped. got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package gologix
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// how far apart in relative terms a float can read back from what was written and still match.  A float64 written
+// to a REAL comes back rounded to a float32 which is good to about 7 digits.
+const verifyEpsilon = 1e-6
+
+// Write a value to a tag like Write then read the tag back and make sure it holds what was written.  This catches
+// ladder logic that overwrites a setpoint as soon as it is written.
+//
+// Floats match when they are within a relative 1e-6 of each other and strings match when they are the same after
+// trimming spaces and nulls.  Slices are compared element by element and structs field by field.  A value that
+// doesn't match gives an error that wraps ErrVerifyMismatch and has both values.
+//
+// The read back is a second request so anything that writes the tag in between is seen as a mismatch.  A value that
+// is read back fine but changed a moment later isn't caught.
+func (client *Client) WriteVerify(tag string, value any) error {
+	err := client.Write(tag, value)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(value)
+	var have reflect.Value
+	var dest any
+	if v.Kind() == reflect.Slice {
+		have = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		dest = have.Interface()
+	} else {
+		ptr := reflect.New(v.Type())
+		have = ptr.Elem()
+		dest = ptr.Interface()
+	}
+	err = client.Read(tag, dest)
+	if err != nil {
+		return fmt.Errorf("wrote %s but couldn't read it back: %w", tag, err)
+	}
+	if !valuesMatch(v, have) {
+		return fmt.Errorf("%s read back as %v after writing %v: %w", tag, have.Interface(), value, ErrVerifyMismatch)
+	}
+	return nil
+}
+
+// whether a value read back from a tag matches the one written to it.  See WriteVerify.
+func valuesMatch(want, have reflect.Value) bool {
+	if want.Kind() != have.Kind() {
+		return false
+	}
+	switch want.Kind() {
+	case reflect.Float32, reflect.Float64:
+		a, b := want.Float(), have.Float()
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return math.IsNaN(a) && math.IsNaN(b)
+		}
+		if a == b {
+			return true
+		}
+		return math.Abs(a-b) <= verifyEpsilon*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	case reflect.String:
+		return strings.Trim(want.String(), " \x00") == strings.Trim(have.String(), " \x00")
+	case reflect.Slice, reflect.Array:
+		if want.Len() != have.Len() {
+			return false
+		}
+		for i := 0; i < want.Len(); i++ {
+			if !valuesMatch(want.Index(i), have.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < want.NumField(); i++ {
+			if !want.Type().Field(i).IsExported() {
+				continue
+			}
+			if !valuesMatch(want.Field(i), have.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(want.Interface(), have.Interface())
+}
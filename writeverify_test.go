@@ -0,0 +1,90 @@
+package gologix
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWriteVerify(t *testing.T) {
+	var tests = []struct {
+		name     string
+		readBack int32
+		wantErr  bool
+	}{
+		{"match", 1234, false},
+		{"overwritten", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, remote := newPipeClient(t)
+			go func() {
+				hdr, buf, err := recvData(remote)
+				if err != nil {
+					t.Errorf("problem reading write request: %v", err)
+					return
+				}
+				if s := CIPService(buf.Bytes()[22]); s != CIPService_Write {
+					t.Errorf("wanted a write first. got %v", s)
+				}
+				item := CIPItem{Header: cipItemHeader{ID: cipItem_ConnectedData}}
+				item.Serialize(msgWriteResultHeader{Service: CIPService_Write.AsResponse()})
+				b, _ := serializeItems([]CIPItem{newItem(cipItem_ConnectionAddress, uint32(0)), item})
+				writeTestResponse(t, remote, hdr.Context, *b)
+
+				hdr, buf, err = recvData(remote)
+				if err != nil {
+					t.Errorf("problem reading read request: %v", err)
+					return
+				}
+				if s := CIPService(buf.Bytes()[22]); s != CIPService_Read {
+					t.Errorf("wanted a read back. got %v", s)
+				}
+				writeTestResponse(t, remote, hdr.Context, dintReadReply(t, tt.readBack))
+			}()
+
+			err := client.WriteVerify("Setpoint", int32(1234))
+			if tt.wantErr {
+				if !errors.Is(err, ErrVerifyMismatch) {
+					t.Errorf("wanted ErrVerifyMismatch. got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("problem writing: %v", err)
+			}
+		})
+	}
+}
+
+func TestValuesMatch(t *testing.T) {
+	type udt struct {
+		A int32
+		B float32
+		C string
+	}
+	var tests = []struct {
+		name       string
+		want, have any
+		match      bool
+	}{
+		{"int", int16(5), int16(5), true},
+		{"int differs", int16(5), int16(6), false},
+		{"real rounding", float64(0.1), float64(float32(0.1)), true},
+		{"real differs", float32(1.5), float32(1.6), false},
+		{"tiny real", float32(0), float32(1e-9), true},
+		{"string trimmed", "hello", "hello \x00", true},
+		{"string differs", "hello", "help", false},
+		{"slice", []int32{1, 2, 3}, []int32{1, 2, 3}, true},
+		{"slice differs", []int32{1, 2, 3}, []int32{1, 5, 3}, false},
+		{"struct", udt{1, 2.5, "x"}, udt{1, 2.5, "x "}, true},
+		{"struct differs", udt{1, 2.5, "x"}, udt{2, 2.5, "x"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if have := valuesMatch(reflect.ValueOf(tt.want), reflect.ValueOf(tt.have)); have != tt.match {
+				t.Errorf("wanted %v got %v", tt.match, have)
+			}
+		})
+	}
+}